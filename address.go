@@ -1,5 +1,7 @@
 package generator
 
+import "strings"
+
 // Address represent an address
 type Address struct {
 	Address    string `json:"address,omitempty" validate:"required"`
@@ -10,31 +12,25 @@ type Address struct {
 }
 
 // ToString output address as string
-// Line break are added for new lines
+// Line break are added for new lines, empty lines are skipped
 func (a *Address) ToString() string {
-	addrString := a.Address
-
-	if len(a.Address2) > 0 {
-		addrString += "\n"
-		addrString += a.Address2
-	}
-
-	if len(a.PostalCode) > 0 {
-		addrString += "\n"
-		addrString += a.PostalCode
-	} else {
-		addrString += "\n"
-	}
+	return strings.Join(a.lines(), "\n")
+}
 
-	if len(a.City) > 0 {
-		addrString += " "
-		addrString += a.City
-	}
+// lines returns the non empty address lines
+func (a *Address) lines() []string {
+	lines := []string{}
 
-	if len(a.Country) > 0 {
-		addrString += "\n"
-		addrString += a.Country
+	for _, line := range []string{
+		a.Address,
+		a.Address2,
+		strings.TrimSpace(a.PostalCode + " " + a.City),
+		a.Country,
+	} {
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
 	}
 
-	return addrString
+	return lines
 }
@@ -0,0 +1,47 @@
+package generator
+
+// amountDueBoxHeight is the height (mm) of the amount due box, see Options.ShowAmountDueBox
+const amountDueBoxHeight float64 = 14
+
+// amountDueBoxFontSize is the font size of the amount due box texts
+const amountDueBoxFontSize float64 = 14
+
+// appendAmountDueBox append the balance due in a filled and bordered box, below the last totals line
+func (doc *Document) appendAmountDueBox() {
+	doc.pdf.SetY(doc.pdf.GetY() + 12)
+	y := doc.pdf.GetY()
+
+	// Box
+	doc.pdf.SetFillColor(
+		doc.Options.AmountDueBoxColor[0],
+		doc.Options.AmountDueBoxColor[1],
+		doc.Options.AmountDueBoxColor[2],
+	)
+	doc.pdf.SetDrawColor(
+		doc.Options.AmountDueBorderColor[0],
+		doc.Options.AmountDueBorderColor[1],
+		doc.Options.AmountDueBorderColor[2],
+	)
+	doc.pdf.SetLineWidth(0.5)
+	doc.pdf.Rect(doc.rtlX(doc.rightX(120), 80), y, 80, amountDueBoxHeight, "FD")
+
+	// Title and amount, on each side of the box
+	textColor := doc.textColorOn(doc.Options.AmountDueBoxColor)
+	doc.pdf.SetTextColor(textColor[0], textColor[1], textColor[2])
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", amountDueBoxFontSize)
+	doc.pdf.SetXY(doc.rtlX(doc.rightX(122), 38), y)
+	doc.pdf.CellFormat(38, amountDueBoxHeight, doc.encodeString(doc.Options.TextAmountDue), "0", 0, doc.rtlAlign("L"), false, 0, "")
+	doc.pdf.SetXY(doc.rtlX(doc.rightX(160), 38), y)
+	doc.pdf.CellFormat(38, amountDueBoxHeight, doc.encodeString(doc.formatTotal(doc.BalanceDue())), "0", 0, doc.rtlAlign("R"), false, 0, "")
+
+	// Reset
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+	doc.pdf.SetLineWidth(0.2)
+	doc.pdf.SetDrawColor(0, 0, 0)
+	doc.pdf.SetFont(doc.Options.Font, "", LargeTextFontSize)
+	doc.pdf.SetY(y + amountDueBoxHeight - 10)
+}
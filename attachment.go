@@ -0,0 +1,33 @@
+package generator
+
+import "github.com/go-pdf/fpdf"
+
+// Attachment define a file embedded in the pdf, listed in the reader attachments panel
+type Attachment struct {
+	Name string `json:"name,omitempty" validate:"required"` // Displayed file name ex timesheet.csv
+	Data []byte `json:"data,omitempty" validate:"required"`
+
+	// Mime type of the file ex text/csv.
+	// fpdf does not write embedded files subtypes, so it is used as the attachment description.
+	Mime string `json:"mime,omitempty"`
+}
+
+// Attach embed a file in the document pdf, attachments are kept in order
+func (d *Document) Attach(name string, data []byte, mime string) *Document {
+	d.Attachments = append(d.Attachments, &Attachment{Name: name, Data: data, Mime: mime})
+	return d
+}
+
+// pdfAttachments returns document attachments as fpdf attachments
+func (doc *Document) pdfAttachments() []fpdf.Attachment {
+	attachments := make([]fpdf.Attachment, 0, len(doc.Attachments))
+	for _, attachment := range doc.Attachments {
+		attachments = append(attachments, fpdf.Attachment{
+			Content:     attachment.Data,
+			Filename:    attachment.Name,
+			Description: attachment.Mime,
+		})
+	}
+
+	return attachments
+}
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BatchError lists the documents GenerateBatch failed to build, by index in its docs
+type BatchError struct {
+	Errors map[int]error
+}
+
+// Error returns the documents errors, by index
+func (e *BatchError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, index := range e.indexes() {
+		messages = append(messages, e.Errors[index].Error())
+	}
+
+	return fmt.Sprintf("%d documents failed: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the error of the first failed document
+func (e *BatchError) Unwrap() error {
+	indexes := e.indexes()
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	return e.Errors[indexes[0]]
+}
+
+// indexes returns the failed documents indexes, sorted
+func (e *BatchError) indexes() []int {
+	indexes := make([]int, 0, len(e.Errors))
+	for index := range e.Errors {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	return indexes
+}
+
+// GenerateBatch build docs in parallel, at most concurrency at a time (the number of CPUs when concurrency <= 0),
+// and returns their pdf bytes in docs order. Documents must be distinct, their options can be shared.
+//
+// Every document is built even when some fail: the pdf of failed documents is nil, and the returned error
+// is a *BatchError listing each document error.
+func GenerateBatch(docs []*Document, concurrency int) ([][]byte, error) {
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	results := make([][]byte, len(docs))
+	batchErr := &BatchError{Errors: map[int]error{}}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	indexes := make(chan int)
+
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for index := range indexes {
+				buf := &bytes.Buffer{}
+				if err := docs[index].Write(buf); err != nil {
+					mu.Lock()
+					batchErr.Errors[index] = fmt.Errorf("document %d %q: %w", index, docs[index].Ref, err)
+					mu.Unlock()
+					continue
+				}
+
+				results[index] = buf.Bytes()
+			}
+		}()
+	}
+
+	for index := range docs {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	if len(batchErr.Errors) > 0 {
+		return results, batchErr
+	}
+
+	return results, nil
+}
@@ -3,45 +3,100 @@ package generator
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/go-pdf/fpdf"
 	"github.com/shopspring/decimal"
 )
 
-// Build pdf document from data provided
+// Build pdf document from data provided.
+// Distinct documents can be built concurrently, even sharing Options, taxes and discounts.
+// A document is not safe for concurrent use: Build prepares its items in place and draws on its own pdf.
+// Building a document again starts from a new pdf, settings applied to the previous one (see Document.Pdf) are lost.
 func (doc *Document) Build() (*fpdf.Fpdf, error) {
+	// Start from a new pdf when building again
+	if doc._built {
+		doc.newPdf()
+		doc._pageOffset, doc._pagesAlias = 0, ""
+	}
+	doc._built = true
+
 	// Validate document data
 	if err := doc.Validate(); err != nil {
 		return nil, err
 	}
 
+	// Prepare accounting
+	doc.ac = doc.accounting()
+
+	// Build time of the footer timestamp
+	if doc.Options.ShowGeneratedAt {
+		location, err := doc.Options.generatedAtLocation()
+		if err != nil {
+			return nil, err
+		}
+		doc._generatedAt = time.Now().In(location)
+	}
+
+	// Find items table columns to hide before layout
+	doc._emptyColumns = doc.emptyColumns()
+
+	// Items lines and footnotes are numbered while rendering
+	doc._lineNumber, doc._footnotes = 0, nil
+	doc._runningTotal, doc._totalsRendered = decimal.Zero, false
+
+	// Register items images, their size is part of items heights
+	if err := doc.registerItemImages(); err != nil {
+		return nil, err
+	}
+
 	// Build base doc
-	doc.pdf.SetMargins(BaseMargin, BaseMarginTop, BaseMargin)
-	doc.pdf.SetXY(10, 10)
+	doc.setMargins()
+	doc.pdf.SetXY(doc.leftX(10), doc.topY(10))
 	doc.pdf.SetTextColor(
 		doc.Options.BaseTextColor[0],
 		doc.Options.BaseTextColor[1],
 		doc.Options.BaseTextColor[2],
 	)
 
+	// Embed UTF-8 fonts
+	if err := doc.registerUTF8Fonts(); err != nil {
+		return nil, err
+	}
+
+	// Right to left texts
+	if doc.Options.RTL {
+		doc.pdf.RTL()
+	}
+
 	// Set header
 	if doc.Header != nil {
 		if err := doc.Header.applyHeader(doc); err != nil {
 			return nil, err
 		}
+	} else if len(doc.Options.Watermark) > 0 {
+		doc.pdf.SetHeaderFunc(doc.withWatermark(nil))
 	}
 
-	// Set footer
+	// Add first page
+	doc.pdf.AddPage()
+
+	// Set footer once the first page is added, so the last page of a previously merged document
+	// keeps its own footer (see MergeDocuments)
 	if doc.Footer != nil {
 		if err := doc.Footer.applyFooter(doc); err != nil {
 			return nil, err
 		}
+	} else if doc.Options.ShowPageNumbers || doc.Options.ShowFooterTotal || doc.Options.ShowGeneratedAt {
+		if err := (&HeaderFooter{}).applyFooter(doc); err != nil {
+			return nil, err
+		}
+	} else if doc._pageOffset > 0 {
+		doc.pdf.SetFooterFunc(nil)
 	}
 
-	// Add first page
-	doc.pdf.AddPage()
-
 	// Load font
 	doc.pdf.SetFont(doc.Options.Font, "", 12)
 
@@ -49,94 +104,331 @@ func (doc *Document) Build() (*fpdf.Fpdf, error) {
 	doc.appendTitle()
 
 	// Appenf document metas (ref & version)
-	doc.appendMetas()
+	metasBottom := doc.appendMetas()
+
+	// Append ref barcode below metas
+	if doc.Options.ShowRefBarcode {
+		barcodeBottom, err := doc.appendRefBarcode(metasBottom + 1)
+		if err != nil {
+			return nil, err
+		}
+		metasBottom = barcodeBottom
+	}
+
+	// Append logo
+	companyY := doc.contentTop()
+	if doc.Options.Logo != nil {
+		logoBottom, err := doc.appendLogo()
+		if err != nil {
+			return nil, err
+		}
+		companyY = logoBottom + 2
+	}
 
 	// Append company contact to doc
-	companyBottom := doc.Company.appendCompanyContactToDoc(doc)
+	companyBottom := doc.Company.appendCompanyContactToDoc(doc, companyY)
 
-	// Append customer contact to doc
-	customerBottom := doc.Customer.appendCustomerContactToDoc(doc)
+	// Append customer and shipping recipient contacts to doc
+	customerY := doc.contentTop() + 25
+	if metasBottom+2 > customerY {
+		customerY = metasBottom + 2
+	}
+	customerBottom := doc.appendRecipients(customerY)
 
 	if customerBottom > companyBottom {
-		doc.pdf.SetXY(10, customerBottom)
+		doc.pdf.SetXY(doc.leftX(10), customerBottom)
 	} else {
-		doc.pdf.SetXY(10, companyBottom)
+		doc.pdf.SetXY(doc.leftX(10), companyBottom)
 	}
 
 	// Append description
 	doc.appendDescription()
 
 	// Append items
-	doc.appendItems()
+	if err := doc.appendItems(); err != nil {
+		return nil, err
+	}
+
+	// Append tax summary
+	if doc.Options.ShowTaxSummary && !doc.hidePrices() {
+		if doc.pdf.GetY()+doc.taxSummaryHeight() > doc.maxPageHeight() {
+			doc.pdf.AddPage()
+		}
+		doc.appendTaxSummary()
+	}
+
+	// Append progress billing
+	if doc.ProgressBilling != nil && !doc.hidePrices() {
+		if doc.pdf.GetY()+doc.progressBillingHeight() > doc.maxPageHeight() {
+			doc.pdf.AddPage()
+		}
+		doc.appendProgressBilling()
+	}
 
-	// Check page height (total bloc height = 30, 45 when doc discount)
+	// Check page height (total bloc height = 30, 45 when doc discount, +19 with payment term)
 	offset := doc.pdf.GetY() + 30
 	if doc.Discount != nil {
 		offset += 15
 	}
-	if offset > MaxPageHeight {
+	if len(doc.paymentTerm()) > 0 {
+		offset += 19
+	}
+	if doc.WithholdingTax != nil {
+		offset += 20
+	}
+	if doc.showShipping() {
+		offset += 10
+	}
+	if doc.showTotalDiscount() {
+		offset += 10
+	}
+	if taxLines := doc.totalsTaxLines(); len(taxLines) > 1 {
+		offset += 10 * float64(len(taxLines)-1)
+	}
+	if doc.Options.CashRounding > 0 {
+		offset += 20
+	}
+	if doc.Deposit != nil {
+		offset += 20
+		if doc.Deposit.Tax != nil {
+			offset += 10
+		}
+	}
+	if len(doc.AmountPaid) > 0 {
+		offset += 20
+	}
+	if doc.EarlyPaymentDiscount != nil {
+		offset += 23
+	}
+	if doc.showTotalInWords() {
+		offset += 10
+	}
+	if doc.Options.ShowAmountDueBox {
+		offset += amountDueBoxHeight + 2
+	}
+	if offset > doc.maxPageHeight() && !doc.hidePrices() {
 		doc.pdf.AddPage()
 	}
 
 	// Append notes
-	doc.appendNotes()
+	notesBottom := doc.appendNotes()
 
 	// Append total
-	doc.appendTotal()
+	if !doc.hidePrices() {
+		doc.appendTotal()
+		doc._totalsRendered = true
+	}
 
 	// Append payment term
 	doc.appendPaymentTerm()
 
+	// Append early payment discount
+	if doc.EarlyPaymentDiscount != nil && !doc.hidePrices() {
+		doc.appendEarlyPaymentDiscount()
+	}
+
+	// Append terms below notes and totals
+	if doc.pdf.GetY() < notesBottom {
+		doc.pdf.SetY(notesBottom)
+	}
+	doc.appendReverseCharge()
+	doc.appendTerms()
+
+	// Append items footnotes
+	doc.appendFootnotes()
+
+	// Append EPC QR code
+	if doc.EPCPayment != nil && !doc.hidePrices() {
+		if err := doc.appendEPCQRCode(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Append bank transfer details
+	if doc.PaymentInfo != nil && !doc.hidePrices() {
+		doc.appendPaymentInfo()
+	}
+
+	// Append signatures areas
+	if len(doc.Signatures) > 0 {
+		if err := doc.appendSignatures(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Append remittance tear-off slip
+	if doc.RemittanceSlip != nil && !doc.hidePrices() {
+		doc.appendRemittanceSlip()
+	}
+
+	// Append swiss QR-bill payment slip
+	if doc.SwissQRBill != nil && !doc.hidePrices() {
+		if err := doc.appendSwissQRBill(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Embed attached files
+	if len(doc.Attachments) > 0 {
+		doc.pdf.SetAttachments(doc.pdfAttachments())
+	}
+
 	// Append js to autoprint if AutoPrint == true
 	if doc.Options.AutoPrint {
 		doc.pdf.SetJavascript("print(true);")
 	}
 
+	// Encryption
+	if doc.Options.Password != nil {
+		doc.applyPassword()
+	}
+
+	// Archival metadata
+	if doc.Options.PDFA {
+		doc.applyPDFA()
+	}
+
 	return doc.pdf, nil
 }
 
+// Write build pdf document and stream it to w
+func (doc *Document) Write(w io.Writer) error {
+	pdf, err := doc.Build()
+	if err != nil {
+		return err
+	}
+
+	if !doc.Options.PDFA {
+		return pdf.Output(w)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := pdf.Output(buf); err != nil {
+		return err
+	}
+
+	output, err := appendPDFACatalog(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(output)
+	return err
+}
+
 // appendTitle to document
 func (doc *Document) appendTitle() {
-	title := doc.typeAsString()
+	if doc.Options.ShowHeaderBand {
+		doc.appendHeaderBand()
+		return
+	}
+
+	title := doc.title()
 
 	// Set x y
-	doc.pdf.SetXY(120, BaseMarginTop)
+	doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 80), doc.topY(BaseMarginTop))
 
 	// Draw rect
 	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
-	doc.pdf.Rect(120, BaseMarginTop, 80, 10, "F")
+	doc.pdf.Rect(doc.rtlX(doc.rightX(120), 80), doc.topY(BaseMarginTop), 80, 10, "F")
 
-	// Draw text
-	doc.pdf.SetFont(doc.Options.Font, "", 14)
-	doc.pdf.CellFormat(80, 10, doc.encodeString(title), "0", 0, "C", false, 0, "")
-}
+	// Draw text, raised above the subtitle
+	doc.setFont(doc.Options.TitleFont, doc.Options.Font, "", 14)
+	if len(doc.Subtitle) == 0 {
+		doc.pdf.CellFormat(80, 10, doc.encodeString(title), "0", 0, "C", false, 0, "")
+		return
+	}
 
-// appendMetas to document
-func (doc *Document) appendMetas() {
-	// Append ref
-	refString := fmt.Sprintf("%s: %s", doc.Options.TextRefTitle, doc.Ref)
+	doc.pdf.CellFormat(80, 6.5, doc.encodeString(title), "0", 0, "C", false, 0, "")
+	doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 80), doc.topY(BaseMarginTop)+6)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.CellFormat(80, 3.5, doc.encodeString(doc.Subtitle), "0", 0, "C", false, 0, "")
+}
 
-	doc.pdf.SetXY(120, BaseMarginTop+11)
-	doc.pdf.SetFont(doc.Options.Font, "", 8)
-	doc.pdf.CellFormat(80, 4, doc.encodeString(refString), "0", 0, "R", false, 0, "")
+// appendMetas to document, returns the metas bottom y
+func (doc *Document) appendMetas() float64 {
+	// Append ref, rendered in the header band when shown
+	top := doc.topY(BaseMarginTop + 11)
+	if doc.Options.ShowHeaderBand {
+		top = doc.contentTop() - 4
+	} else {
+		doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 80), top)
+		doc.pdf.SetFont(doc.Options.Font, "", 8)
+		doc.pdf.CellFormat(80, 4, doc.encodeString(doc.refString()), "0", 0, doc.rtlAlign("R"), false, 0, "")
+	}
 
 	// Append version
 	if len(doc.Version) > 0 {
 		versionString := fmt.Sprintf("%s: %s", doc.Options.TextVersionTitle, doc.Version)
-		doc.pdf.SetXY(120, BaseMarginTop+15)
+		doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 80), top+4)
 		doc.pdf.SetFont(doc.Options.Font, "", 8)
-		doc.pdf.CellFormat(80, 4, doc.encodeString(versionString), "0", 0, "R", false, 0, "")
+		doc.pdf.CellFormat(80, 4, doc.encodeString(versionString), "0", 0, doc.rtlAlign("R"), false, 0, "")
 	}
 
 	// Append date
-	date := time.Now().Format("01/02/2006")
+	date := time.Now().Format(doc.Options.DateLayout)
 	if len(doc.Date) > 0 {
 		date = doc.Date
 	}
 	dateString := fmt.Sprintf("%s: %s", doc.Options.TextDateTitle, date)
-	doc.pdf.SetXY(120, BaseMarginTop+19)
+	doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 80), top+8)
 	doc.pdf.SetFont(doc.Options.Font, "", 8)
-	doc.pdf.CellFormat(80, 4, doc.encodeString(dateString), "0", 0, "R", false, 0, "")
+	doc.pdf.CellFormat(80, 4, doc.encodeString(dateString), "0", 0, doc.rtlAlign("R"), false, 0, "")
+	y := top + 12
+
+	// Append due date
+	if len(doc.DueDate) > 0 {
+		dueDateString := fmt.Sprintf("%s: %s", doc.Options.TextDueDateTitle, doc.DueDate)
+		doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 80), y)
+		doc.pdf.CellFormat(80, 4, doc.encodeString(dueDateString), "0", 0, doc.rtlAlign("R"), false, 0, "")
+		y += 4
+	}
+
+	// Append overdue label
+	if doc.IsOverdue(time.Now()) {
+		doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 80), y)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", 10)
+		doc.pdf.SetTextColor(200, 0, 0)
+		doc.pdf.CellFormat(80, 5, doc.encodeString(doc.Options.TextOverdue), "0", 0, doc.rtlAlign("R"), false, 0, "")
+		doc.pdf.SetTextColor(
+			doc.Options.BaseTextColor[0],
+			doc.Options.BaseTextColor[1],
+			doc.Options.BaseTextColor[2],
+		)
+		doc.pdf.SetFont(doc.Options.Font, "", 8)
+		y += 5
+	}
+
+	// Append quotation validity date and expired label
+	if doc.Type == Quotation && len(doc.ValidityDate) > 0 {
+		validityString := fmt.Sprintf("%s: %s", doc.Options.TextValidityDateTitle, doc.ValidityDate)
+		doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 80), y)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", 8)
+		doc.pdf.CellFormat(80, 4, doc.encodeString(validityString), "0", 0, doc.rtlAlign("R"), false, 0, "")
+		doc.pdf.SetFont(doc.Options.Font, "", 8)
+		y += 4
+	}
+
+	if doc.IsExpired(time.Now()) {
+		doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 80), y)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", 10)
+		doc.pdf.SetTextColor(200, 0, 0)
+		doc.pdf.CellFormat(80, 5, doc.encodeString(doc.Options.TextExpired), "0", 0, doc.rtlAlign("R"), false, 0, "")
+		doc.pdf.SetTextColor(
+			doc.Options.BaseTextColor[0],
+			doc.Options.BaseTextColor[1],
+			doc.Options.BaseTextColor[2],
+		)
+		doc.pdf.SetFont(doc.Options.Font, "", 8)
+		y += 5
+	}
+
+	// Append payment status stamp
+	if doc.showStatus() {
+		y = doc.appendStatus(y)
+	}
+
+	return y
 }
 
 // appendDescription to document
@@ -144,170 +436,172 @@ func (doc *Document) appendDescription() {
 	if len(doc.Description) > 0 {
 		doc.pdf.SetY(doc.pdf.GetY() + 10)
 		doc.pdf.SetFont(doc.Options.Font, "", 10)
-		doc.pdf.MultiCell(190, 5, doc.encodeString(doc.Description), "B", "L", false)
+		doc.pdf.MultiCell(doc.contentWidth(), 5, doc.encodeString(doc.Description), "B", doc.rtlAlign("L"), false)
 	}
 }
 
-// drawsTableTitles in document
-func (doc *Document) drawsTableTitles() {
-	// Draw table titles
-	doc.pdf.SetX(10)
-	doc.pdf.SetY(doc.pdf.GetY() + 5)
-	doc.pdf.SetFont(doc.Options.BoldFont, "B", 8)
+// drawItemHeader draw the items columns titles and separator lines at y, returns the first item line y
+func (doc *Document) drawItemHeader(y float64) float64 {
+	doc.setFont(doc.Options.HeaderFont, doc.Options.BoldFont, "B", doc.compactFontSize(8))
+	height, _ := doc.itemsHeaderHeight()
 
 	// Draw rec
 	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
-	doc.pdf.Rect(10, doc.pdf.GetY(), 190, 6, "F")
+	width := doc.contentWidth()
+	doc.pdf.Rect(doc.rtlX(doc.leftX(10), width), y, width, height, "F")
 
-	// Name
-	doc.pdf.SetX(ItemColNameOffset)
-	doc.pdf.CellFormat(
-		ItemColHTPriceOffset-ItemColNameOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsNameTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Unit price
-	doc.pdf.SetX(ItemColHTPriceOffset)
-	doc.pdf.CellFormat(
-		ItemColPriceInclVATOffset-ItemColHTPriceOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsUnitCostTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
+	// Draw separator lines
+	doc.pdf.SetDrawColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
+	doc.pdf.Line(doc.leftX(10), y, doc.leftX(10)+width, y)
+	doc.pdf.Line(doc.leftX(10), y+height, doc.leftX(10)+width, y+height)
+	doc.pdf.SetDrawColor(0, 0, 0)
 
-	// PriceInclVAT
-	doc.pdf.SetX(ItemColPriceInclVATOffset)
-	doc.pdf.CellFormat(
-		ItemColQtyOffset-ItemColPriceInclVATOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsQuantityTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Qty
-	doc.pdf.SetX(ItemColQtyOffset)
-	doc.pdf.CellFormat(
-		ItemColTaxOffset-ItemColQtyOffset,
-		6,
-		doc.encodeString("Qty"),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Tax
-	doc.pdf.SetX(ItemColTaxOffset)
-	doc.pdf.CellFormat(
-		ItemColDiscountOffset-ItemColTaxOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsTaxTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Discount
-	doc.pdf.SetX(ItemColDiscountOffset)
-	doc.pdf.CellFormat(
-		ItemColTotalTTCOffset-ItemColDiscountOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsDiscountTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
+	for _, column := range doc.itemColumns() {
+		doc.pdf.SetXY(doc.rtlX(column.X, column.Width), y)
+		doc.pdf.CellFormat(
+			column.Width,
+			height,
+			doc.encodeString(doc.itemColumnTitle(column)),
+			"0",
+			0,
+			doc.rtlAlign(column.Align),
+			false,
+			0,
+			"",
+		)
+	}
 
-	// TOTAL TTC
-	doc.pdf.SetX(ItemColTotalTTCOffset)
-	doc.pdf.CellFormat(
-		190-ItemColTotalTTCOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsTotalTTCTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
+	return y + height + 2
 }
 
 // appendItems to document
-func (doc *Document) appendItems() {
-	doc.drawsTableTitles()
+func (doc *Document) appendItems() error {
+	_, marginTop := doc.itemsHeaderHeight()
+	doc._itemsHeight, doc._itemsTop = 0, doc.pdf.GetY()+marginTop
+	doc.pdf.SetX(doc.leftX(10))
+	doc.pdf.SetY(doc.drawItemHeader(doc.pdf.GetY() + marginTop))
+	doc.setItemFont(false)
+
+	// Without sections, render all items in a single untitled block
+	if !doc.hasSections() {
+		err := doc.appendItemsBlock(doc.sortedItems())
+		doc._itemsHeight += doc.pdf.GetY() - doc._itemsTop
+		return err
+	}
 
-	doc.pdf.SetX(10)
-	doc.pdf.SetY(doc.pdf.GetY() + 8)
-	doc.pdf.SetFont(doc.Options.Font, "", 8)
+	for _, section := range doc.itemSections() {
+		if len(section.Title) > 0 {
+			// Keep section title with its first item
+			doc.ensureItemsSpace(6 + section.Items[0].height(doc))
+			doc.appendSectionTitle(section)
+		}
 
-	for i := 0; i < len(doc.Items); i++ {
-		item := doc.Items[i]
+		if err := doc.appendItemsBlock(section.Items); err != nil {
+			return err
+		}
 
-		// Check item tax
-		if item.Tax == nil {
-			item.Tax = doc.DefaultTax
+		if len(section.Title) > 0 && !doc.hidePrices() {
+			doc.ensureItemsSpace(4)
+			doc.appendSectionSubtotal(section)
 		}
+	}
 
-		// Append to pdf
-		item.appendColTo(doc.Options, doc)
+	doc._itemsHeight += doc.pdf.GetY() - doc._itemsTop
+	return nil
+}
 
-		if doc.pdf.GetY() > MaxPageHeight {
-			// Add page
-			doc.pdf.AddPage()
-			doc.drawsTableTitles()
-			doc.pdf.SetFont(doc.Options.Font, "", 8)
+// appendItemsBlock append items lines to document
+func (doc *Document) appendItemsBlock(items []*Item) error {
+	for row, item := range items {
+		// Move to next page if the line does not fit
+		doc.ensureItemsSpace(item.height(doc))
+
+		// Append to pdf
+		if err := item.appendColTo(doc.Options, doc, row); err != nil {
+			return err
 		}
 
-		doc.pdf.SetX(10)
-		doc.pdf.SetY(doc.pdf.GetY() + 6)
+		doc.pdf.SetX(doc.leftX(10))
+		doc.pdf.SetY(doc.pdf.GetY() + doc.itemsRowsSpacing())
 	}
+
+	return nil
 }
 
-// appendNotes to document
-func (doc *Document) appendNotes() {
-	if len(doc.Notes) == 0 {
+// ensureItemsSpace add a new page with table titles if height does not fit in current page
+func (doc *Document) ensureItemsSpace(height float64) {
+	if doc.pdf.GetY()+height <= doc.maxPageHeight() {
 		return
 	}
 
+	// Add page
+	_, marginTop := doc.itemsHeaderHeight()
+	doc._itemsHeight += doc.pdf.GetY() - doc._itemsTop
+	if doc.Options.ShowContinuedMarkers {
+		doc.appendContinuedMarker(doc.Options.TextContinuedNextPage)
+	}
+	doc.pdf.AddPage()
+	doc._itemsTop = doc.pdf.GetY() + marginTop
+	doc.pdf.SetX(doc.leftX(10))
+	doc.pdf.SetY(doc.drawItemHeader(doc.pdf.GetY() + marginTop))
+	if doc.Options.ShowContinuedMarkers {
+		doc.appendContinuedMarker(doc.Options.TextContinuedPreviousPage)
+	}
+	doc.setItemFont(false)
+}
+
+// appendContinuedMarker append text right aligned below the items table at current y, see Options.ShowContinuedMarkers
+func (doc *Document) appendContinuedMarker(text string) {
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+
+	width := doc.contentWidth()
+	doc.pdf.SetXY(doc.rtlX(doc.margins().Left, width), doc.pdf.GetY())
+	doc.pdf.CellFormat(width, 4, doc.encodeString(text), "0", 0, doc.rtlAlign("R"), false, 0, "")
+	doc.pdf.SetXY(doc.leftX(10), doc.pdf.GetY()+4)
+
+	// Reset color
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+}
+
+// appendNotes to document, returns the notes bottom y
+func (doc *Document) appendNotes() float64 {
+	if len(doc.Notes) == 0 {
+		return doc.pdf.GetY()
+	}
+
 	currentY := doc.pdf.GetY()
 
+	// Notes on the left of totals, on the right of mirrored totals
+	pageWidth, _ := doc.pageSize()
+	notesMargin := pageWidth - doc.rightX(110)
+
 	doc.pdf.SetFont(doc.Options.Font, "", 9)
-	doc.pdf.SetX(BaseMargin)
-	doc.pdf.SetRightMargin(100)
+	doc.pdf.SetX(doc.margins().Left)
+	doc.pdf.SetRightMargin(notesMargin)
+	if doc.Options.RTL {
+		doc.pdf.SetLeftMargin(notesMargin)
+		doc.pdf.SetRightMargin(doc.margins().Right)
+	}
 	doc.pdf.SetY(currentY + 10)
 
 	_, lineHt := doc.pdf.GetFontSize()
 	html := doc.pdf.HTMLBasicNew()
 	html.Write(lineHt, doc.encodeString(doc.Notes))
+	notesBottom := doc.pdf.GetY() + lineHt
 
-	doc.pdf.SetRightMargin(BaseMargin)
+	doc.setMargins()
 	doc.pdf.SetY(currentY)
+
+	return notesBottom
 }
 
 // appendTotal to document
@@ -320,23 +614,29 @@ func (doc *Document) appendTotal() {
 		doc.Options.BaseTextColor[2],
 	)
 
+	// Draw items and document discounts total
+	if doc.showTotalDiscount() {
+		doc.drawTotalLine(doc.Options.TextTotalDiscount, doc.formatTotal(doc.totalDiscount().Neg()))
+		doc.pdf.SetY(doc.pdf.GetY() + 10)
+	}
+
 	// Draw TOTAL HT title
-	doc.pdf.SetX(120)
+	doc.pdf.SetX(doc.rtlX(doc.rightX(120), 38))
 	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
-	doc.pdf.Rect(120, doc.pdf.GetY(), 40, 10, "F")
-	doc.pdf.CellFormat(38, 10, doc.encodeString(doc.Options.TextTotalTotal), "0", 0, "R", false, 0, "")
+	doc.pdf.Rect(doc.rtlX(doc.rightX(120), 40), doc.pdf.GetY(), 40, 10, "F")
+	doc.pdf.CellFormat(38, 10, doc.encodeString(doc.Options.TextTotalTotal), "0", 0, doc.rtlAlign("R"), false, 0, "")
 
 	// Draw TOTAL HT amount
-	doc.pdf.SetX(162)
+	doc.pdf.SetX(doc.rtlX(doc.rightX(162), 40))
 	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
-	doc.pdf.Rect(160, doc.pdf.GetY(), 40, 10, "F")
+	doc.pdf.Rect(doc.rtlX(doc.rightX(160), 40), doc.pdf.GetY(), 40, 10, "F")
 	doc.pdf.CellFormat(
 		40,
 		10,
-		doc.encodeString(doc.ac.FormatMoneyDecimal(doc.TotalWithoutTaxAndWithoutDocumentDiscount())),
+		doc.encodeString(doc.formatTotal(doc.TotalWithoutTaxAndWithoutDocumentDiscount())),
 		"0",
 		0,
-		"L",
+		doc.rtlAlign("L"),
 		false,
 		0,
 		"",
@@ -346,15 +646,15 @@ func (doc *Document) appendTotal() {
 		baseY := doc.pdf.GetY() + 10
 
 		// Draw discounted title
-		doc.pdf.SetXY(120, baseY)
+		doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 38), baseY)
 		doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
-		doc.pdf.Rect(120, doc.pdf.GetY(), 40, 15, "F")
+		doc.pdf.Rect(doc.rtlX(doc.rightX(120), 40), doc.pdf.GetY(), 40, 15, "F")
 
 		// title
-		doc.pdf.CellFormat(38, 7.5, doc.encodeString(doc.Options.TextTotalDiscounted), "0", 0, "BR", false, 0, "")
+		doc.pdf.CellFormat(38, 7.5, doc.encodeString(doc.Options.TextTotalDiscounted), "0", 0, doc.rtlAlign("BR"), false, 0, "")
 
 		// description
-		doc.pdf.SetXY(120, baseY+7.5)
+		doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 38), baseY+7.5)
 		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
 		doc.pdf.SetTextColor(
 			doc.Options.GreyTextColor[0],
@@ -369,19 +669,17 @@ func (doc *Document) appendTotal() {
 			descString.WriteString(discountAmount.String())
 			descString.WriteString(" % / -")
 			descString.WriteString(doc.ac.FormatMoneyDecimal(
-				doc.TotalWithoutTaxAndWithoutDocumentDiscount().Sub(doc.TotalWithoutTax())),
+				doc.TotalWithoutTaxAndWithoutDocumentDiscount().Sub(doc.totalWithDocumentDiscount())),
 			)
 		} else {
 			descString.WriteString("-")
 			descString.WriteString(doc.ac.FormatMoneyDecimal(discountAmount))
 			descString.WriteString(" / -")
-			descString.WriteString(
-				discountAmount.Mul(decimal.NewFromFloat(100)).Div(doc.TotalWithoutTaxAndWithoutDocumentDiscount()).StringFixed(2),
-			)
+			descString.WriteString(doc.discountPercent().StringFixed(2))
 			descString.WriteString(" %")
 		}
 
-		doc.pdf.CellFormat(38, 7.5, doc.encodeString(descString.String()), "0", 0, "TR", false, 0, "")
+		doc.pdf.CellFormat(38, 7.5, doc.encodeString(descString.String()), "0", 0, doc.rtlAlign("TR"), false, 0, "")
 
 		doc.pdf.SetFont(doc.Options.Font, "", LargeTextFontSize)
 		doc.pdf.SetTextColor(
@@ -392,16 +690,16 @@ func (doc *Document) appendTotal() {
 
 		// Draw discount amount
 		doc.pdf.SetY(baseY)
-		doc.pdf.SetX(162)
+		doc.pdf.SetX(doc.rtlX(doc.rightX(162), 40))
 		doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
-		doc.pdf.Rect(160, doc.pdf.GetY(), 40, 15, "F")
+		doc.pdf.Rect(doc.rtlX(doc.rightX(160), 40), doc.pdf.GetY(), 40, 15, "F")
 		doc.pdf.CellFormat(
 			40,
 			15,
-			doc.encodeString(doc.ac.FormatMoneyDecimal(doc.TotalWithoutTax())),
+			doc.encodeString(doc.formatTotal(doc.totalWithDocumentDiscount())),
 			"0",
 			0,
-			"L",
+			doc.rtlAlign("L"),
 			false,
 			0,
 			"",
@@ -411,64 +709,151 @@ func (doc *Document) appendTotal() {
 		doc.pdf.SetY(doc.pdf.GetY() + 10)
 	}
 
-	// Draw tax title
-	doc.pdf.SetX(120)
-	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
-	doc.pdf.Rect(120, doc.pdf.GetY(), 40, 10, "F")
-	doc.pdf.CellFormat(38, 10, doc.encodeString(doc.Options.TextTotalTax), "0", 0, "R", false, 0, "")
+	// Shipping
+	if doc.showShipping() {
+		doc.drawTotalLine(doc.Options.TextTotalShipping, doc.formatTotal(doc.shippingAmount()))
+		doc.pdf.SetY(doc.pdf.GetY() + 10)
+	}
 
-	// Draw tax amount
-	doc.pdf.SetX(162)
-	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
-	doc.pdf.Rect(160, doc.pdf.GetY(), 40, 10, "F")
-	doc.pdf.CellFormat(
-		40,
-		10,
-		doc.encodeString(doc.ac.FormatMoneyDecimal(doc.Tax())),
-		"0",
-		0,
-		"L",
-		false,
-		0,
-		"",
-	)
+	// Draw tax, one line by tax name, or by rate with totals details
+	if taxLines := doc.totalsTaxLines(); len(taxLines) > 0 {
+		for index, line := range taxLines {
+			if index > 0 {
+				doc.pdf.SetY(doc.pdf.GetY() + 10)
+			}
+			doc.drawTotalLine(doc.taxSummaryTotalTitle(line), doc.formatTotal(line.Tax))
+		}
+	} else {
+		doc.drawTotalLine(doc.Options.TextTotalTax, doc.formatTotal(doc.Tax()))
+	}
 
-	// Draw total with tax title
+	// Draw total with tax, in bold
 	doc.pdf.SetY(doc.pdf.GetY() + 10)
-	doc.pdf.SetX(120)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+	doc.drawTotalLine(doc.Options.TextTotalWithTax, doc.formatTotal(doc.TotalWithTax()))
+	doc.pdf.SetFont(doc.Options.Font, "", LargeTextFontSize)
+
+	if doc.Options.ShowGrandTotalBox {
+		doc.pdf.SetDrawColor(doc.Options.BaseTextColor[0], doc.Options.BaseTextColor[1], doc.Options.BaseTextColor[2])
+		doc.pdf.Rect(doc.rtlX(doc.rightX(120), 80), doc.pdf.GetY(), 80, 10, "D")
+		doc.pdf.SetDrawColor(0, 0, 0)
+	}
+
+	// Withholding tax and net payable
+	if doc.WithholdingTax != nil {
+		doc.appendTotalLine(
+			doc.Options.TextTotalWithholdingTax,
+			doc.formatTotal(doc.Withholding().Neg()),
+		)
+		if doc.Options.CashRounding <= 0 {
+			doc.appendTotalLine(
+				doc.Options.TextTotalNetPayable,
+				doc.formatTotal(doc.NetPayable()),
+			)
+		}
+	}
+
+	// Cash rounding adjustment and rounded net payable
+	if doc.Options.CashRounding > 0 {
+		doc.appendTotalLine(
+			doc.Options.TextTotalRounding,
+			doc.formatTotal(doc.CashRoundingAdjustment()),
+		)
+		doc.appendTotalLine(
+			doc.Options.TextTotalNetPayable,
+			doc.formatTotal(doc.NetPayable()),
+		)
+	}
+
+	// Deposit due and remaining
+	if doc.Deposit != nil {
+		doc.appendDeposit()
+	}
+
+	// Amount paid and balance due
+	if len(doc.AmountPaid) > 0 {
+		doc.appendAmountPaid()
+	}
+
+	// Amount due highlight box
+	if doc.Options.ShowAmountDueBox {
+		doc.appendAmountDueBox()
+	}
+
+	// Total with tax spelled out
+	if doc.showTotalInWords() {
+		doc.appendTotalInWords()
+	}
+}
+
+// appendTotalLine append a title / value line below the current totals line
+func (doc *Document) appendTotalLine(title string, value string) {
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+	doc.drawTotalLine(title, value)
+}
+
+// drawTotalLine draw a title / value totals line at current y
+func (doc *Document) drawTotalLine(title string, value string) {
+	// Draw title
+	doc.pdf.SetX(doc.rtlX(doc.rightX(120), 38))
 	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
-	doc.pdf.Rect(120, doc.pdf.GetY(), 40, 10, "F")
-	doc.pdf.CellFormat(38, 10, doc.encodeString(doc.Options.TextTotalWithTax), "0", 0, "R", false, 0, "")
+	doc.pdf.Rect(doc.rtlX(doc.rightX(120), 40), doc.pdf.GetY(), 40, 10, "F")
+	doc.pdf.CellFormat(38, 10, doc.encodeString(title), "0", 0, doc.rtlAlign("R"), false, 0, "")
 
-	// Draw total with tax amount
-	doc.pdf.SetX(162)
+	// Draw value
+	doc.pdf.SetX(doc.rtlX(doc.rightX(162), 40))
 	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
-	doc.pdf.Rect(160, doc.pdf.GetY(), 40, 10, "F")
-	doc.pdf.CellFormat(
-		40,
-		10,
-		doc.encodeString(doc.ac.FormatMoneyDecimal(doc.TotalWithTax())),
-		"0",
-		0,
-		"L",
-		false,
-		0,
-		"",
+	doc.pdf.Rect(doc.rtlX(doc.rightX(160), 40), doc.pdf.GetY(), 40, 10, "F")
+	doc.pdf.CellFormat(40, 10, doc.encodeString(value), "0", 0, doc.rtlAlign("L"), false, 0, "")
+}
+
+// appendTerms to document, one paragraph per line
+func (doc *Document) appendTerms() {
+	if len(doc.Terms) == 0 {
+		return
+	}
+
+	// Break pages above footer
+	autoPageBreak, pageBreakMargin := doc.pdf.GetAutoPageBreak()
+	_, pageHeight := doc.pdf.GetPageSize()
+	doc.pdf.SetAutoPageBreak(true, pageHeight-doc.maxPageHeight())
+
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+
+	for _, paragraph := range strings.Split(doc.Terms, "\n") {
+		doc.pdf.SetX(doc.rtlX(doc.margins().Left, doc.contentWidth()))
+		doc.pdf.MultiCell(doc.contentWidth(), 3, doc.encodeString(paragraph), "0", doc.rtlAlign("L"), false)
+		doc.pdf.SetY(doc.pdf.GetY() + 1)
+	}
+
+	// Reset font and page break
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
 	)
+	doc.pdf.SetAutoPageBreak(autoPageBreak, pageBreakMargin)
 }
 
 // appendPaymentTerm to document
 func (doc *Document) appendPaymentTerm() {
-	if len(doc.PaymentTerm) > 0 {
+	if paymentTerm := doc.paymentTerm(); len(paymentTerm) > 0 {
 		paymentTermString := fmt.Sprintf(
 			"%s: %s",
 			doc.encodeString(doc.Options.TextPaymentTermTitle),
-			doc.encodeString(doc.PaymentTerm),
+			doc.encodeString(paymentTerm),
 		)
 		doc.pdf.SetY(doc.pdf.GetY() + 15)
 
-		doc.pdf.SetX(120)
+		doc.pdf.SetX(doc.rtlX(doc.rightX(120), 80))
 		doc.pdf.SetFont(doc.Options.BoldFont, "B", 10)
-		doc.pdf.CellFormat(80, 4, doc.encodeString(paymentTermString), "0", 0, "R", false, 0, "")
+		doc.pdf.CellFormat(80, 4, doc.encodeString(paymentTermString), "0", 0, doc.rtlAlign("R"), false, 0, "")
 	}
 }
@@ -0,0 +1,301 @@
+package generator
+
+import (
+	"errors"
+)
+
+// ErrInvalidColumnOffsets when columns offsets are not increasing or overflow the page
+var ErrInvalidColumnOffsets = errors.New("invalid column offsets")
+
+// ColumnOffsets define the x offset (mm) of each items table column, in rendering order.
+// End defines the right edge of the last column. Offsets are expressed for an A4 portrait page,
+// they are scaled to the width of other page sizes and orientations (see Options.PageSize).
+type ColumnOffsets struct {
+	Name         float64 `default:"10" json:"name,omitempty"`
+	HTPrice      float64 `default:"97" json:"ht_price,omitempty"`
+	PriceInclVAT float64 `default:"113" json:"price_incl_vat,omitempty"`
+	Qty          float64 `default:"127" json:"qty,omitempty"`
+	Discount     float64 `default:"140" json:"discount,omitempty"`
+	Tax          float64 `default:"157" json:"tax,omitempty"`
+	TotalTTC     float64 `default:"175" json:"total_ttc,omitempty"`
+	End          float64 `default:"190" json:"end,omitempty"`
+
+	// RefWidth is the width of the items ref column, taken from the name column when Options.ShowItemRef is set
+	RefWidth float64 `default:"18" json:"ref_width,omitempty"`
+}
+
+// validate columns offsets against page width
+func (c *ColumnOffsets) validate(pageWidth float64) error {
+	offsets := []float64{c.Name, c.HTPrice, c.PriceInclVAT, c.Qty, c.Discount, c.Tax, c.TotalTTC, c.End}
+
+	for i := 1; i < len(offsets); i++ {
+		if offsets[i] <= offsets[i-1] {
+			return ErrInvalidColumnOffsets
+		}
+	}
+
+	if c.Name < 0 || c.End > pageWidth || c.RefWidth >= c.HTPrice-c.Name {
+		return ErrInvalidColumnOffsets
+	}
+
+	return nil
+}
+
+// ErrInvalidItemColumns when Options.ItemColumns has no name column or overflows the table width
+var ErrInvalidItemColumns = errors.New("invalid item columns")
+
+// Items table built-in columns keys, see ItemColumn.Key
+const (
+	ItemColumnLineNumber string = "line_number"
+	ItemColumnRef        string = "ref"
+	ItemColumnName       string = "name"
+	ItemColumnUnitCost   string = "unit_cost"
+	ItemColumnQuantity   string = "quantity"
+	ItemColumnQty        string = "qty"
+	ItemColumnDiscount   string = "discount"
+	ItemColumnTax        string = "tax"
+	ItemColumnTotal      string = "total"
+)
+
+// lineNumberWidth is the width (mm) of the line numbers column, taken from the name column, see Options.ShowLineNumbers
+const lineNumberWidth float64 = 8
+
+// ItemColumn define an items table column, either built-in (see ItemColumn* keys) or custom.
+// Columns are laid out from left to right starting at ColumnOffsets.Name,
+// the name column takes the width left before ColumnOffsets.End.
+type ItemColumn struct {
+	Key   string  `json:"key,omitempty" validate:"required"` // Built-in column key, or Item.Fields key of custom columns
+	Title string  `json:"title,omitempty"`                   // Built-in columns default to Options.TextItems* titles
+	Width float64 `json:"width,omitempty" validate:"gte=0"`  // Ignored for the name column
+	Align string  `json:"align,omitempty"`                   // Horizontal alignment L, C or R, default L
+
+	// Value returns the cell text of custom columns and takes precedence over Key
+	Value func(item *Item) string `json:"-"`
+}
+
+// itemColumn is an ItemColumn positioned in the items table
+type itemColumn struct {
+	*ItemColumn
+	X     float64
+	Width float64
+}
+
+// priceColumns are hidden with prices, see Options.HideDeliveryNotePrices
+var priceColumns = map[string]bool{
+	ItemColumnUnitCost: true,
+	ItemColumnDiscount: true,
+	ItemColumnTax:      true,
+	ItemColumnTotal:    true,
+}
+
+// defaultItemColumns returns the built-in columns laid out with ColumnOffsets
+func (doc *Document) defaultItemColumns() []*itemColumn {
+	cols := doc.columnOffsets()
+	columns := []*itemColumn{}
+
+	// Line numbers and ref columns are taken from the name column
+	x, nameWidth := cols.Name, cols.HTPrice-cols.Name
+	if doc.Options.ShowLineNumbers {
+		columns = append(columns, &itemColumn{&ItemColumn{Key: ItemColumnLineNumber}, x, lineNumberWidth})
+		x, nameWidth = x+lineNumberWidth, nameWidth-lineNumberWidth
+	}
+	if doc.Options.ShowItemRef {
+		columns = append(columns, &itemColumn{&ItemColumn{Key: ItemColumnRef}, x, cols.RefWidth})
+		x, nameWidth = x+cols.RefWidth, nameWidth-cols.RefWidth
+	}
+	columns = append(columns, &itemColumn{&ItemColumn{Key: ItemColumnName}, x, nameWidth})
+
+	return append(columns,
+		&itemColumn{&ItemColumn{Key: ItemColumnUnitCost}, cols.HTPrice, cols.PriceInclVAT - cols.HTPrice},
+		&itemColumn{&ItemColumn{Key: ItemColumnQuantity}, cols.PriceInclVAT, cols.Qty - cols.PriceInclVAT},
+		&itemColumn{&ItemColumn{Key: ItemColumnQty}, cols.Qty, cols.Discount - cols.Qty},
+		&itemColumn{&ItemColumn{Key: ItemColumnDiscount}, cols.Discount, cols.Tax - cols.Discount},
+		&itemColumn{&ItemColumn{Key: ItemColumnTax}, cols.Tax, cols.TotalTTC - cols.Tax},
+		&itemColumn{&ItemColumn{Key: ItemColumnTotal}, cols.TotalTTC, cols.End - cols.TotalTTC},
+	)
+}
+
+// itemColumns returns the rendered items table columns, Options.ItemColumns or the built-in ones
+func (doc *Document) itemColumns() []*itemColumn {
+	columns := []*itemColumn{}
+
+	if len(doc.Options.ItemColumns) == 0 {
+		for _, column := range doc.defaultItemColumns() {
+			if !doc.hidePrices() || !priceColumns[column.Key] {
+				columns = append(columns, column)
+			}
+		}
+
+		return doc.hideEmptyColumns(columns)
+	}
+
+	// Name column takes the width left by the other columns
+	cols := doc.columnOffsets()
+	nameWidth := cols.End - cols.Name
+	for _, column := range doc.Options.ItemColumns {
+		if doc.hidePrices() && priceColumns[column.Key] {
+			continue
+		}
+
+		width := column.Width
+		if column.Key == ItemColumnName {
+			width = 0
+		}
+
+		columns = append(columns, &itemColumn{ItemColumn: column, Width: width})
+		nameWidth -= width
+	}
+
+	x := cols.Name
+	for _, column := range columns {
+		if column.Key == ItemColumnName {
+			column.Width = nameWidth
+		}
+
+		column.X = x
+		x += column.Width
+	}
+
+	return doc.hideEmptyColumns(columns)
+}
+
+// emptyColumns returns the tax and discount columns keys unused by all items, see Options.HideEmptyColumns
+func (doc *Document) emptyColumns() map[string]bool {
+	empty := map[string]bool{}
+	if !doc.Options.HideEmptyColumns {
+		return empty
+	}
+
+	empty[ItemColumnTax], empty[ItemColumnDiscount] = true, true
+	for _, item := range doc.Items {
+		if item.hasTax() {
+			empty[ItemColumnTax] = false
+		}
+		if item.hasDiscount() {
+			empty[ItemColumnDiscount] = false
+		}
+	}
+
+	return empty
+}
+
+// hideEmptyColumns drops the columns of emptyColumns computed when building, their width is given to the name column
+func (doc *Document) hideEmptyColumns(columns []*itemColumn) []*itemColumn {
+	if len(doc._emptyColumns) == 0 || len(columns) == 0 {
+		return columns
+	}
+
+	kept := []*itemColumn{}
+	freed := 0.0
+	for _, column := range columns {
+		if doc._emptyColumns[column.Key] {
+			freed += column.Width
+			continue
+		}
+		kept = append(kept, column)
+	}
+
+	x := columns[0].X
+	for _, column := range kept {
+		if column.Key == ItemColumnName {
+			column.Width += freed
+		}
+
+		column.X = x
+		x += column.Width
+	}
+
+	return kept
+}
+
+// validateItemColumns checks Options.ItemColumns has a name column and fits in the table
+func (doc *Document) validateItemColumns() error {
+	if len(doc.Options.ItemColumns) == 0 {
+		return nil
+	}
+
+	names := 0
+	width := 0.0
+	for _, column := range doc.Options.ItemColumns {
+		if column.Key == ItemColumnName {
+			names++
+			continue
+		}
+
+		if column.Width <= 0 {
+			return ErrInvalidItemColumns
+		}
+		width += column.Width
+	}
+
+	cols := doc.columnOffsets()
+	if names != 1 || width >= cols.End-cols.Name {
+		return ErrInvalidItemColumns
+	}
+
+	return nil
+}
+
+// itemColumnTitle returns the header label of column
+func (doc *Document) itemColumnTitle(column *itemColumn) string {
+	if len(column.Title) > 0 {
+		return column.Title
+	}
+
+	switch column.Key {
+	case ItemColumnLineNumber:
+		return doc.Options.TextItemsLineNumberTitle
+	case ItemColumnRef:
+		return doc.Options.TextItemsRefTitle
+	case ItemColumnName:
+		return doc.Options.TextItemsNameTitle
+	case ItemColumnUnitCost:
+		if doc.Options.ShowGrossUnitCost {
+			return doc.Options.TextItemsGrossUnitCostTitle
+		}
+		return doc.Options.TextItemsUnitCostTitle
+	case ItemColumnQuantity:
+		return doc.Options.TextItemsQuantityTitle
+	case ItemColumnQty:
+		return "Qty"
+	case ItemColumnDiscount:
+		return doc.Options.TextItemsDiscountTitle
+	case ItemColumnTax:
+		return doc.Options.TextItemsTaxTitle
+	case ItemColumnTotal:
+		return doc.Options.TextItemsTotalTTCTitle
+	}
+
+	return ""
+}
+
+// nameColumn returns the x offset and width of the items name column
+func (doc *Document) nameColumn() (float64, float64) {
+	for _, column := range doc.itemColumns() {
+		if column.Key == ItemColumnName {
+			return column.X, column.Width
+		}
+	}
+
+	cols := doc.columnOffsets()
+	return cols.Name, cols.HTPrice - cols.Name
+}
+
+// subtotalColumns returns the columns of section subtotals title and amount,
+// the total column when rendered or the last one, and the column before it
+func (doc *Document) subtotalColumns() (*itemColumn, *itemColumn) {
+	columns := doc.itemColumns()
+
+	index := len(columns) - 1
+	for i, column := range columns {
+		if column.Key == ItemColumnTotal {
+			index = i
+		}
+	}
+
+	if index == 0 {
+		return columns[0], columns[0]
+	}
+
+	return columns[index-1], columns[index]
+}
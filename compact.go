@@ -0,0 +1,63 @@
+package generator
+
+// Items table spacing (mm) of the default and compact layouts, see Options.Compact
+const (
+	itemLineHeight         float64 = 3
+	itemDetailsSpacing     float64 = 1
+	itemRowsSpacing        float64 = 6
+	itemHeaderHeight       float64 = 6
+	itemHeaderMarginTop    float64 = 5
+	compactLineHeight      float64 = 2.5
+	compactDetailsSpacing  float64 = 0.5
+	compactRowsSpacing     float64 = 2
+	compactHeaderHeight    float64 = 4
+	compactHeaderMarginTop float64 = 2
+
+	// compactFontScale scales items and table header font sizes down in compact layout
+	compactFontScale float64 = 0.85
+)
+
+// itemsLineHeight returns the height of an items text line
+func (doc *Document) itemsLineHeight() float64 {
+	if doc.Options.Compact {
+		return compactLineHeight
+	}
+
+	return itemLineHeight
+}
+
+// itemsDetailsSpacing returns the space between an item name and each of its details
+func (doc *Document) itemsDetailsSpacing() float64 {
+	if doc.Options.Compact {
+		return compactDetailsSpacing
+	}
+
+	return itemDetailsSpacing
+}
+
+// itemsRowsSpacing returns the space between two items lines
+func (doc *Document) itemsRowsSpacing() float64 {
+	if doc.Options.Compact {
+		return compactRowsSpacing
+	}
+
+	return itemRowsSpacing
+}
+
+// itemsHeaderHeight returns the height of the items table header, and its margin above
+func (doc *Document) itemsHeaderHeight() (float64, float64) {
+	if doc.Options.Compact {
+		return compactHeaderHeight, compactHeaderMarginTop
+	}
+
+	return itemHeaderHeight, itemHeaderMarginTop
+}
+
+// compactFontSize returns size scaled down in compact layout
+func (doc *Document) compactFontSize(size float64) float64 {
+	if doc.Options.Compact {
+		return size * compactFontScale
+	}
+
+	return size
+}
@@ -10,6 +10,12 @@ const (
 	// DeliveryNote define the "delievry note" document type
 	DeliveryNote string = "DELIVERY_NOTE"
 
+	// CreditNote define the "credit note" document type, totals are rendered as negative amounts
+	CreditNote string = "CREDIT_NOTE"
+
+	// ProForma define the "pro forma invoice" document type
+	ProForma string = "PRO_FORMA"
+
 	// BaseMargin define base margin used in documents
 	BaseMargin float64 = 10
 
@@ -19,11 +25,15 @@ const (
 	// HeaderMarginTop define base header margin top used in documents
 	HeaderMarginTop float64 = 5
 
-	// MaxPageHeight define the maximum height for a single page
+	// HeaderBandHeight define the height of the first page header band, see Options.ShowHeaderBand
+	HeaderBandHeight float64 = 25
+
+	// MaxPageHeight define the maximum height for a single A4 portrait page,
+	// other page sizes keep the same space for the footer
 	MaxPageHeight float64 = 260
 )
 
-// Cols offsets
+// Cols offsets defaults, see Options.ColumnOffsets
 const (
 	// ItemColNameOffset ...
 	ItemColNameOffset float64 = 10
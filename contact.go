@@ -19,6 +19,12 @@ type Contact struct {
 	ZipCode     string   `json:"zipCode,omitempty"`
 	City        string   `json:"city,omitempty"`
 
+	// Legal identifiers and contact details, rendered below the address when set
+	VATNumber          string `json:"vat_number,omitempty"` // Required on EU invoices
+	RegistrationNumber string `json:"registration_number,omitempty"`
+	Email              string `json:"email,omitempty"`
+	Phone              string `json:"phone,omitempty"`
+
 	// AddtionnalInfo to append after contact informations. You can use basic html here (bold, italic tags).
 	AddtionnalInfo []string `json:"additional_info,omitempty"`
 }
@@ -38,11 +44,17 @@ func (c *Contact) appendContactTODoc(
 		// Create filename
 		fileName := b64.StdEncoding.EncodeToString([]byte(c.Name))
 
+		// Flatten transparent logos of PDF/A documents, undecodable logos are left to fpdf
+		logo := c.Logo
+		if opaque, err := doc.opaqueImage(logo); err == nil {
+			logo = opaque
+		}
+
 		// Create reader from logo bytes
-		ioReader := bytes.NewReader(c.Logo)
+		ioReader := bytes.NewReader(logo)
 
 		// Get image format
-		_, format, _ := image.DecodeConfig(bytes.NewReader(c.Logo))
+		_, format, _ := image.DecodeConfig(bytes.NewReader(logo))
 
 		// Register image in pdf
 		imageInfo := doc.pdf.RegisterImageOptionsReader(fileName, fpdf.ImageOptions{
@@ -52,7 +64,8 @@ func (c *Contact) appendContactTODoc(
 		if imageInfo != nil {
 			var imageOpt fpdf.ImageOptions
 			imageOpt.ImageType = format
-			doc.pdf.ImageOptions(fileName, doc.pdf.GetX(), y, 0, 37, false, imageOpt, 0, "")
+			logoWidth := 37 * imageInfo.Width() / imageInfo.Height()
+			doc.pdf.ImageOptions(fileName, doc.rtlX(doc.pdf.GetX(), logoWidth), y, 0, 37, false, imageOpt, 0, "")
 			doc.pdf.SetY(y + 35)
 		}
 	}
@@ -69,34 +82,26 @@ func (c *Contact) appendContactTODoc(
 	}
 
 	// Reset x
-	doc.pdf.SetX(x)
+	doc.pdf.SetX(doc.rtlX(x, 70))
 
 	// Name rect
-	doc.pdf.Rect(x, doc.pdf.GetY(), 70, 8, "F")
+	doc.pdf.Rect(doc.rtlX(x, 70), doc.pdf.GetY(), 70, 8, "F")
 
 	// Set name
 	doc.pdf.SetFont(doc.Options.BoldFont, "B", 10)
-	doc.pdf.Cell(40, 8, doc.encodeString(c.Name))
+	doc.pdf.CellFormat(70, 8, doc.encodeString(c.Name), "", 0, doc.rtlAlign(""), false, 0, "")
 	doc.pdf.SetFont(doc.Options.Font, "", 10)
 
 	if c.Address != nil {
 		// Address rect
-		var addrRectHeight float64 = 17
+		addrRectHeight := 5*float64(len(c.Address.lines())) + 2
 
-		if len(c.Address.Address2) > 0 {
-			addrRectHeight = addrRectHeight + 5
-		}
-
-		if len(c.Address.Country) == 0 {
-			addrRectHeight = addrRectHeight - 5
-		}
-
-		doc.pdf.Rect(x, doc.pdf.GetY()+9, 70, addrRectHeight, "F")
+		doc.pdf.Rect(doc.rtlX(x, 70), doc.pdf.GetY()+9, 70, addrRectHeight, "F")
 
 		// Set address
 		doc.pdf.SetFont(doc.Options.Font, "", 10)
-		doc.pdf.SetXY(x, doc.pdf.GetY()+10)
-		doc.pdf.MultiCell(70, 5, doc.encodeString(c.Address.ToString()), "0", "L", false)
+		doc.pdf.SetXY(doc.rtlX(x, 70), doc.pdf.GetY()+10)
+		doc.pdf.MultiCell(70, 5, doc.encodeString(c.Address.ToString()), "0", doc.rtlAlign("L"), false)
 	} else if c.Country != "" {
 		var addrRectHeight float64 = 10
 		content := ""
@@ -109,9 +114,22 @@ func (c *Contact) appendContactTODoc(
 			addrRectHeight = addrRectHeight + 5
 		}
 		content = fmt.Sprintf("%s%s", content, c.Country)
-		doc.pdf.Rect(x, doc.pdf.GetY()+9, 70, addrRectHeight, "F")
-		doc.pdf.SetXY(x, doc.pdf.GetY()+10)
-		doc.pdf.MultiCell(70, 5, doc.encodeString(content), "0", "L", false)
+		doc.pdf.Rect(doc.rtlX(x, 70), doc.pdf.GetY()+9, 70, addrRectHeight, "F")
+		doc.pdf.SetXY(doc.rtlX(x, 70), doc.pdf.GetY()+10)
+		doc.pdf.MultiCell(70, 5, doc.encodeString(content), "0", doc.rtlAlign("L"), false)
+	}
+
+	// Identifiers
+	if details := c.detailsLines(doc); len(details) > 0 {
+		doc.pdf.SetFontSize(SmallTextFontSize)
+		doc.pdf.SetXY(x, doc.pdf.GetY()+2)
+
+		for _, line := range details {
+			doc.pdf.SetXY(doc.rtlX(x, 70), doc.pdf.GetY())
+			doc.pdf.MultiCell(70, 3, doc.encodeString(line), "0", doc.rtlAlign("L"), false)
+		}
+
+		doc.pdf.SetFontSize(BaseTextFontSize)
 	}
 
 	// Addtionnal info
@@ -121,8 +139,8 @@ func (c *Contact) appendContactTODoc(
 		doc.pdf.SetXY(x, doc.pdf.GetY()+2)
 
 		for _, line := range c.AddtionnalInfo {
-			doc.pdf.SetXY(x, doc.pdf.GetY())
-			doc.pdf.MultiCell(70, 3, doc.encodeString(line), "0", "L", false)
+			doc.pdf.SetXY(doc.rtlX(x, 70), doc.pdf.GetY())
+			doc.pdf.MultiCell(70, 3, doc.encodeString(line), "0", doc.rtlAlign("L"), false)
 		}
 
 		doc.pdf.SetXY(x, doc.pdf.GetY())
@@ -132,13 +150,31 @@ func (c *Contact) appendContactTODoc(
 	return doc.pdf.GetY()
 }
 
-// appendCompanyContactToDoc append the company contact to the document
-func (c *Contact) appendCompanyContactToDoc(doc *Document) float64 {
-	x, y, _, _ := doc.pdf.GetMargins()
+// detailsLines returns the contact identifiers and details lines, empty fields are skipped
+func (c *Contact) detailsLines(doc *Document) []string {
+	lines := []string{}
+
+	for _, detail := range [][2]string{
+		{doc.Options.TextContactVATNumberTitle, c.VATNumber},
+		{doc.Options.TextContactRegistrationNumberTitle, c.RegistrationNumber},
+		{doc.Options.TextContactEmailTitle, c.Email},
+		{doc.Options.TextContactPhoneTitle, c.Phone},
+	} {
+		if len(detail[1]) > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %s", detail[0], detail[1]))
+		}
+	}
+
+	return lines
+}
+
+// appendCompanyContactToDoc append the company contact to the document at y
+func (c *Contact) appendCompanyContactToDoc(doc *Document, y float64) float64 {
+	x, _, _, _ := doc.pdf.GetMargins()
 	return c.appendContactTODoc(x, y, true, "L", doc)
 }
 
-// appendCustomerContactToDoc append the customer contact to the document
-func (c *Contact) appendCustomerContactToDoc(doc *Document) float64 {
-	return c.appendContactTODoc(130, BaseMarginTop+25, true, "R", doc)
+// appendCustomerContactToDoc append the customer contact to the document at y
+func (c *Contact) appendCustomerContactToDoc(doc *Document, y float64) float64 {
+	return c.appendContactTODoc(doc.rightX(130), y, true, "R", doc)
 }
@@ -0,0 +1,31 @@
+package generator
+
+import "math"
+
+// lightTextColor is the text color on dark backgrounds, see Document.textColorOn
+var lightTextColor = []int{255, 255, 255}
+
+// isDark returns true when white text contrasts more than black text with color, from its WCAG relative luminance
+func isDark(color []int) bool {
+	channel := func(c int) float64 {
+		v := float64(c) / 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+
+	luminance := 0.2126*channel(color[0]) + 0.7152*channel(color[1]) + 0.0722*channel(color[2])
+
+	// Contrast ratios with white (1.05 / (L + 0.05)) and black ((L + 0.05) / 0.05) are equal at L ≈ 0.179
+	return luminance < 0.179
+}
+
+// textColorOn returns the color of texts drawn on background: white on dark backgrounds, BaseTextColor otherwise
+func (doc *Document) textColorOn(background []int) []int {
+	if isDark(background) {
+		return lightTextColor
+	}
+
+	return doc.Options.BaseTextColor
+}
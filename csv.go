@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidCSV when items csv cannot be read, or a cell is not valid
+var ErrInvalidCSV = errors.New("invalid csv")
+
+// itemsCSVColumns maps items csv header names, lower cased, to the item field they set
+var itemsCSVColumns = map[string]string{
+	"ref":         "ref",
+	"name":        "name",
+	"description": "description",
+	"unit cost":   "unit_cost",
+	"unit_cost":   "unit_cost",
+	"quantity":    "quantity",
+	"qty":         "quantity",
+	"unit":        "unit",
+	"tax %":       "tax",
+	"tax":         "tax",
+	"discount":    "discount",
+}
+
+// ReadItemsCSV returns the items of a csv, one item by row after a header row naming its columns:
+// Name, Unit cost, Quantity, Tax %, Discount, and optionally Ref, Description and Unit (case insensitive,
+// json keys are accepted too). Tax is a percent, with or without % sign. Discount is a percent when it
+// ends with %, an amount otherwise. Empty tax and discount cells are ignored.
+//
+// Numeric cells are checked, errors report the csv line number of the row (the header is line 1).
+func ReadItemsCSV(r io.Reader) ([]*Item, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %s", ErrInvalidCSV, err)
+	}
+
+	fields := make([]string, len(header))
+	found := map[string]bool{}
+	for index, name := range header {
+		field, ok := itemsCSVColumns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("%w: header: unknown column %q", ErrInvalidCSV, name)
+		}
+		fields[index] = field
+		found[field] = true
+	}
+
+	for _, field := range []string{"name", "unit_cost", "quantity"} {
+		if !found[field] {
+			return nil, fmt.Errorf("%w: header: missing column %s", ErrInvalidCSV, field)
+		}
+	}
+
+	items := []*Item{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCSV, err)
+		}
+
+		item, err := newCSVItem(fields, record)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %s", ErrInvalidCSV, line, err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// newCSVItem returns the item of a csv record, fields are the item fields of record cells
+func newCSVItem(fields []string, record []string) (*Item, error) {
+	item := &Item{}
+
+	for index, value := range record {
+		value = strings.TrimSpace(value)
+
+		switch fields[index] {
+		case "ref":
+			item.Ref = value
+		case "name":
+			item.Name = value
+		case "description":
+			item.Description = value
+		case "unit":
+			item.Unit = value
+		case "unit_cost":
+			if _, err := parseDecimal("unit_cost", value); err != nil {
+				return nil, err
+			}
+			item.UnitCost = value
+		case "quantity":
+			if _, err := parseDecimal("quantity", value); err != nil {
+				return nil, err
+			}
+			item.Quantity = value
+		case "tax":
+			if len(value) == 0 {
+				continue
+			}
+			percent := strings.TrimSpace(strings.TrimSuffix(value, "%"))
+			if _, err := parseDecimal("tax", percent); err != nil {
+				return nil, err
+			}
+			item.Tax = &Tax{Percent: percent}
+		case "discount":
+			if len(value) == 0 {
+				continue
+			}
+			if strings.HasSuffix(value, "%") {
+				percent := strings.TrimSpace(strings.TrimSuffix(value, "%"))
+				if _, err := parseDecimal("discount", percent); err != nil {
+					return nil, err
+				}
+				item.Discount = &Discount{Percent: percent}
+				continue
+			}
+			if _, err := parseDecimal("discount", value); err != nil {
+				return nil, err
+			}
+			item.Discount = &Discount{Amount: value}
+		}
+	}
+
+	if len(item.Name) == 0 {
+		return nil, errors.New("field name: required")
+	}
+
+	return item, nil
+}
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"github.com/leekchan/accounting"
+)
+
+// Currency symbol positions
+const (
+	CurrencyPositionBefore string = "before"
+	CurrencyPositionAfter  string = "after"
+)
+
+// Currency define how money amounts are formatted.
+// Symbol must include the space separating it from the amount, if any.
+type Currency struct {
+	Symbol    string
+	Code      string // ISO 4217 code, ex EUR
+	Precision int
+	Thousand  string
+	Decimal   string
+	Position  string // CurrencyPositionBefore or CurrencyPositionAfter
+}
+
+// Common currencies
+var (
+	// CurrencyEUR formats amounts as "1 234,56 €"
+	CurrencyEUR = Currency{Symbol: " €", Code: "EUR", Precision: 2, Thousand: " ", Decimal: ",", Position: CurrencyPositionAfter}
+
+	// CurrencyUSD formats amounts as "$1,234.56"
+	CurrencyUSD = Currency{Symbol: "$", Code: "USD", Precision: 2, Thousand: ",", Decimal: ".", Position: CurrencyPositionBefore}
+
+	// CurrencyGBP formats amounts as "£1,234.56"
+	CurrencyGBP = Currency{Symbol: "£", Code: "GBP", Precision: 2, Thousand: ",", Decimal: ".", Position: CurrencyPositionBefore}
+
+	// CurrencyCHF formats amounts as "CHF 1'234.56"
+	CurrencyCHF = Currency{Symbol: "CHF ", Code: "CHF", Precision: 2, Thousand: "'", Decimal: ".", Position: CurrencyPositionBefore}
+)
+
+// SetCurrency of document, replacing all Options.Currency* fields
+func (d *Document) SetCurrency(currency Currency) *Document {
+	d.Options.CurrencySymbol = currency.Symbol
+	d.Options.CurrencyCode = currency.Code
+	d.Options.CurrencyPrecision = currency.Precision
+	d.Options.CurrencyThousand = currency.Thousand
+	d.Options.CurrencyDecimal = currency.Decimal
+	d.Options.CurrencyPosition = currency.Position
+	return d
+}
+
+// currencySymbol define a document currency symbol override, see SetCurrencySymbol
+type currencySymbol struct {
+	symbol   string
+	position string
+}
+
+// SetCurrencySymbol of document ex "€ " or "EUR ", with its position (CurrencyPositionBefore or
+// CurrencyPositionAfter, Options.CurrencyPosition when empty). Unlike SetCurrency, options are left
+// untouched so they can stay shared with other documents: the symbol applies to this document builds only.
+func (d *Document) SetCurrencySymbol(symbol string, position string) *Document {
+	d._currencySymbol = &currencySymbol{symbol: symbol, position: position}
+	return d
+}
+
+// newAccounting returns the money formatter configured from options
+func newAccounting(options *Options) accounting.Accounting {
+	return accounting.Accounting{
+		Symbol:    options.CurrencySymbol,
+		Precision: options.CurrencyPrecision,
+		Thousand:  options.CurrencyThousand,
+		Decimal:   options.CurrencyDecimal,
+		Format:    currencyFormat(options.CurrencyPosition),
+	}
+}
+
+// currencyFormat returns the accounting format of a currency symbol position
+func currencyFormat(position string) string {
+	if position == CurrencyPositionAfter {
+		return "%v%s"
+	}
+
+	return "%s%v"
+}
+
+// accounting returns the document money formatter, from options and the document currency symbol
+func (doc *Document) accounting() accounting.Accounting {
+	ac := newAccounting(doc.Options)
+	applyLocale(&ac, doc.Options.Locale)
+
+	if override := doc._currencySymbol; override != nil {
+		ac.Symbol = override.symbol
+		if len(override.position) > 0 {
+			ac.Format = currencyFormat(override.position)
+		}
+	}
+
+	return ac
+}
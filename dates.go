@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrInvalidDueDate when the document due date cannot be parsed with Options.DateLayout
+var ErrInvalidDueDate = errors.New("invalid due date")
+
+// ErrInvalidValidityDate when the document validity date cannot be parsed with Options.DateLayout
+var ErrInvalidValidityDate = errors.New("invalid validity date")
+
+// ErrInvalidItemPeriod when an item period date cannot be parsed with Options.DateLayout, or ends before its start
+var ErrInvalidItemPeriod = errors.New("invalid item period")
+
+// parseDate parse value with Options.DateLayout
+func (doc *Document) parseDate(value string) (time.Time, error) {
+	return time.Parse(doc.Options.DateLayout, value)
+}
+
+// today returns the now date at midnight
+func today(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// IsOverdue returns true when the document is a not fully paid invoice and its due date is before the now day.
+// An invoice due today is not overdue.
+func (doc *Document) IsOverdue(now time.Time) bool {
+	if doc.Type != Invoice || doc.Status() == StatusPaid || len(doc.DueDate) == 0 {
+		return false
+	}
+
+	dueDate, err := doc.parseDate(doc.DueDate)
+	if err != nil {
+		return false
+	}
+
+	return dueDate.Before(today(now))
+}
+
+// IsExpired returns true when the document is a quotation and its validity date is before the now day.
+// A quotation valid until today is not expired.
+func (doc *Document) IsExpired(now time.Time) bool {
+	if doc.Type != Quotation || len(doc.ValidityDate) == 0 {
+		return false
+	}
+
+	validityDate, err := doc.parseDate(doc.ValidityDate)
+	if err != nil {
+		return false
+	}
+
+	return validityDate.Before(today(now))
+}
+
+// paymentTerm returns the document payment term, or the days between issue and due dates (ex Net 30)
+func (doc *Document) paymentTerm() string {
+	if len(doc.PaymentTerm) > 0 || len(doc.DueDate) == 0 {
+		return doc.PaymentTerm
+	}
+
+	dueDate, err := doc.parseDate(doc.DueDate)
+	if err != nil {
+		return ""
+	}
+
+	issueDate := today(time.Now())
+	if len(doc.Date) > 0 {
+		if issueDate, err = doc.parseDate(doc.Date); err != nil {
+			return ""
+		}
+	}
+
+	days := int(math.Round(dueDate.Sub(issueDate).Hours() / 24))
+	if days < 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(doc.Options.TextPaymentTermNet, days)
+}
+
+// validateItemPeriod checks item period dates, and that the period does not end before its start
+func (doc *Document) validateItemPeriod(item *Item) error {
+	var start, end time.Time
+	if len(item.PeriodStart) > 0 {
+		date, err := doc.parseDate(item.PeriodStart)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidItemPeriod, err)
+		}
+		start = date
+	}
+
+	if len(item.PeriodEnd) > 0 {
+		date, err := doc.parseDate(item.PeriodEnd)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidItemPeriod, err)
+		}
+		end = date
+	}
+
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return fmt.Errorf("%w: %s is before %s", ErrInvalidItemPeriod, item.PeriodEnd, item.PeriodStart)
+	}
+
+	return nil
+}
+
+// itemPeriod returns the item service period label, empty without period dates
+func (doc *Document) itemPeriod(item *Item) string {
+	switch {
+	case len(item.PeriodStart) > 0 && len(item.PeriodEnd) > 0:
+		return fmt.Sprintf(doc.Options.TextItemPeriod, item.PeriodStart, item.PeriodEnd)
+	case len(item.PeriodStart) > 0:
+		return fmt.Sprintf(doc.Options.TextItemPeriodFrom, item.PeriodStart)
+	case len(item.PeriodEnd) > 0:
+		return fmt.Sprintf(doc.Options.TextItemPeriodUntil, item.PeriodEnd)
+	}
+
+	return ""
+}
@@ -0,0 +1,21 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidDecimal when a string field can't be converted to decimal
+var ErrInvalidDecimal = errors.New("invalid decimal")
+
+// parseDecimal convert value to decimal, errors are wrapped with the json field name
+func parseDecimal(field string, value string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return d, fmt.Errorf("field %s: %w %q", field, ErrInvalidDecimal, value)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Deposit define the part of the document total due now, as percent or fixed amount (see Document.DepositDue).
+// Without Tax, the deposit is taken on the net payable (tax included).
+// With Tax, the deposit is taken on the total without tax and Tax is applied to the deposit base.
+type Deposit struct {
+	Percent string `json:"percent,omitempty"` // Deposit in percent ex 30
+	Amount  string `json:"amount,omitempty"`  // Deposit in amount ex 500.00
+	Tax     *Tax   `json:"tax,omitempty"`
+
+	_percent decimal.Decimal
+	_amount  decimal.Decimal
+}
+
+// Prepare convert strings to decimal
+func (d *Deposit) Prepare() error {
+	if len(d.Percent) == 0 && len(d.Amount) == 0 {
+		return ErrInvalidDeposit
+	}
+
+	// Percent
+	if len(d.Percent) > 0 {
+		percent, err := parseDecimal("percent", d.Percent)
+		if err != nil {
+			return err
+		}
+		d._percent = percent
+	}
+
+	// Amount
+	if len(d.Amount) > 0 {
+		amount, err := parseDecimal("amount", d.Amount)
+		if err != nil {
+			return err
+		}
+		d._amount = amount
+	}
+
+	if d.Tax != nil {
+		if err := d.Tax.Prepare(); err != nil {
+			return fmt.Errorf("tax: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// isPercent returns true when the deposit is a percent of the document total, amount wins over percent
+func (d *Deposit) isPercent() bool {
+	return len(d.Amount) == 0
+}
+
+// DepositBase return the deposit without its tax, see Deposit
+func (doc *Document) DepositBase() decimal.Decimal {
+	if doc.Deposit == nil {
+		return decimal.Zero
+	}
+
+	if !doc.Deposit.isPercent() {
+		return doc.Deposit._amount
+	}
+
+	total := doc.NetPayable()
+	if doc.Deposit.Tax != nil {
+		total = doc.TotalWithoutTax()
+	}
+
+	return doc.Options.round(total.Mul(doc.Deposit._percent).Div(decimal.NewFromFloat(100)))
+}
+
+// DepositTax return the tax applied to the deposit base, zero without Deposit.Tax
+func (doc *Document) DepositTax() decimal.Decimal {
+	if doc.Deposit == nil || doc.Deposit.Tax == nil {
+		return decimal.Zero
+	}
+
+	taxType, taxAmount := doc.Deposit.Tax.getTax()
+	if taxType == TaxTypeAmount {
+		return taxAmount
+	}
+
+	return doc.Options.round(doc.DepositBase().Mul(taxAmount).Div(decimal.NewFromFloat(100)))
+}
+
+// DepositDue return the deposit due now, with its tax
+func (doc *Document) DepositDue() decimal.Decimal {
+	return doc.DepositBase().Add(doc.DepositTax())
+}
+
+// DepositRemaining return the net payable left to bill after the deposit
+func (doc *Document) DepositRemaining() decimal.Decimal {
+	return doc.NetPayable().Sub(doc.DepositDue())
+}
+
+// depositTitle returns the deposit invoice title, with the percent of percent deposits
+func (doc *Document) depositTitle() string {
+	if !doc.Deposit.isPercent() {
+		return doc.Options.TextTypeDepositInvoice
+	}
+
+	return fmt.Sprintf("%s — %s %%", doc.Options.TextTypeDepositInvoice, doc.Deposit.Percent)
+}
+
+// appendDeposit lines below totals
+func (doc *Document) appendDeposit() {
+	if doc.Deposit.Tax != nil {
+		doc.appendTotalLine(
+			doc.Options.TextTotalDepositTax,
+			doc.formatTotal(doc.DepositTax()),
+		)
+	}
+	doc.appendTotalLine(
+		doc.Options.TextTotalDeposit,
+		doc.formatTotal(doc.DepositDue()),
+	)
+	doc.appendTotalLine(
+		doc.Options.TextTotalDepositRemaining,
+		doc.formatTotal(doc.DepositRemaining()),
+	)
+}
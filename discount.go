@@ -9,6 +9,9 @@ import (
 // ErrInvalidDiscount when percent and amount are empty
 var ErrInvalidDiscount = errors.New("invalid discount")
 
+// ErrInvalidDeposit when percent and amount are empty
+var ErrInvalidDeposit = errors.New("invalid deposit")
+
 // Discount types
 const (
 	DiscountTypeAmount  string = "amount"
@@ -19,12 +22,9 @@ const (
 type Discount struct {
 	Percent string `json:"percent,omitempty"` // Discount in percent ex 17
 	Amount  string `json:"amount,omitempty"`  // Discount in amount ex 123.40
-
-	_percent decimal.Decimal
-	_amount  decimal.Decimal
 }
 
-// Prepare convert strings to decimal
+// Prepare check strings are valid decimals. It does not modify the discount, which can be shared by documents.
 func (d *Discount) Prepare() error {
 	if len(d.Percent) == 0 && len(d.Amount) == 0 {
 		return ErrInvalidDiscount
@@ -32,25 +32,35 @@ func (d *Discount) Prepare() error {
 
 	// Percent
 	if len(d.Percent) > 0 {
-		percent, err := decimal.NewFromString(d.Percent)
-		if err != nil {
+		if _, err := parseDecimal("percent", d.Percent); err != nil {
 			return err
 		}
-		d._percent = percent
 	}
 
 	// Amount
 	if len(d.Amount) > 0 {
-		amount, err := decimal.NewFromString(d.Amount)
-		if err != nil {
+		if _, err := parseDecimal("amount", d.Amount); err != nil {
 			return err
 		}
-		d._amount = amount
 	}
 
 	return nil
 }
 
+// AmountOf returns the amount taken by the discount from total: its amount, or its percent of total.
+// Amount discounts are negative on negative totals, reducing return lines refund.
+func (d *Discount) AmountOf(total decimal.Decimal) decimal.Decimal {
+	discountType, value := d.getDiscount()
+	if discountType == DiscountTypeAmount {
+		if total.IsNegative() {
+			return value.Neg()
+		}
+		return value
+	}
+
+	return total.Mul(value.Div(decimal.NewFromFloat(100)))
+}
+
 // getDiscount as return the discount type and value
 func (t *Discount) getDiscount() (string, decimal.Decimal) {
 	tax := "0"
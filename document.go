@@ -1,8 +1,11 @@
 package generator
 
 import (
+	"time"
+
 	"github.com/go-pdf/fpdf"
 	"github.com/leekchan/accounting"
+	"github.com/shopspring/decimal"
 )
 
 // Document define base document
@@ -10,26 +13,103 @@ type Document struct {
 	pdf *fpdf.Fpdf
 	ac  accounting.Accounting
 
+	// translate is the default unicode translator of the document pdf, not shared with other
+	// documents since fpdf translators are not safe for concurrent use
+	translate UnicodeTranslateFunc
+
+	_amountPaid decimal.Decimal
+
+	// Currency symbol of this document only, see SetCurrencySymbol
+	_currencySymbol *currencySymbol
+
+	// Build already ran, the next one starts from a new pdf
+	_built bool
+
+	// Tax and discount columns unused by items, see Options.HideEmptyColumns
+	_emptyColumns map[string]bool
+
+	// Number of the last rendered items line, see Options.ShowLineNumbers
+	_lineNumber int
+
+	// Items footnotes in markers order, collected while rendering items, see Item.Footnote
+	_footnotes []string
+
+	// Total of the rendered items lines, and totals block rendered, see Options.ShowFooterTotal
+	_runningTotal   decimal.Decimal
+	_totalsRendered bool
+
+	// Build time in Options.GeneratedAtTimezone, see Options.ShowGeneratedAt
+	_generatedAt time.Time
+
+	// Items table height summed over pages, and top of its part in the current page, see EstimateHeight
+	_itemsHeight float64
+	_itemsTop    float64
+
+	// Merged documents pages, see MergeDocuments
+	_pageOffset int
+	_pagesAlias string
+
 	Options      *Options      `json:"options,omitempty"`
 	Header       *HeaderFooter `json:"header,omitempty"`
 	Footer       *HeaderFooter `json:"footer,omitempty"`
-	Type         string        `json:"type,omitempty" validate:"required,oneof=INVOICE DELIVERY_NOTE QUOTATION"`
-	Ref          string        `json:"ref,omitempty" validate:"required,min=1,max=32"`
+	Type         string        `json:"type,omitempty" validate:"required,oneof=INVOICE DELIVERY_NOTE QUOTATION CREDIT_NOTE PRO_FORMA"`
+	Ref          string        `json:"ref,omitempty" validate:"required_without=Number,max=32"`
+	Number       *Number       `json:"number,omitempty"` // Replaces Ref once expanded in Validate, see Number
 	Version      string        `json:"version,omitempty" validate:"max=32"`
 	ClientRef    string        `json:"client_ref,omitempty" validate:"max=64"`
+	Title        string        `json:"title,omitempty" validate:"max=64"`     // Replaces the document type title ex TAX INVOICE
+	Subtitle     string        `json:"subtitle,omitempty" validate:"max=128"` // Rendered below the title, in a smaller font
 	Description  string        `json:"description,omitempty" validate:"max=1024"`
 	Notes        string        `json:"notes,omitempty"`
+	Terms        string        `json:"terms,omitempty"` // Terms and conditions rendered below totals, one paragraph per line
 	Company      *Contact      `json:"company,omitempty" validate:"required"`
 	Customer     *Contact      `json:"customer,omitempty" validate:"required"`
-	Items        []*Item       `json:"items,omitempty"`
-	Date         string        `json:"date,omitempty"`
-	ValidityDate string        `json:"validity_date,omitempty"`
+	ShipTo       *Contact      `json:"ship_to,omitempty"` // Shipping recipient, rendered below the customer when it differs
+	Items        []*Item       `json:"items,omitempty" validate:"dive,required"`
+	Date         string        `json:"date,omitempty"`          // Issue date, formatted with Options.DateLayout
+	DueDate      string        `json:"due_date,omitempty"`      // Formatted with Options.DateLayout
+	Paid         bool          `json:"paid,omitempty"`          // Paid invoices are never overdue, see Document.IsOverdue
+	AmountPaid   string        `json:"amount_paid,omitempty"`   // Amount already paid, see Document.BalanceDue and Document.Status
+	ValidityDate string        `json:"validity_date,omitempty"` // Quotations validity, see Document.IsExpired
 	PaymentTerm  string        `json:"payment_term,omitempty"`
 	DefaultTax   *Tax          `json:"default_tax,omitempty"`
-	Discount     *Discount     `json:"discount,omitempty"`
+	Discount     *Discount     `json:"discount,omitempty"` // Applied after items discounts, see Document.TotalWithoutTax
+	Shipping     *Shipping     `json:"shipping,omitempty"` // Not discounted, see Shipping
+	Deposit      *Deposit      `json:"deposit,omitempty"`  // Part of the net payable due now, see Document.DepositDue
+
+	// ProgressBilling renders the contract progress of a milestone invoice below items, see ProgressBilling
+	ProgressBilling *ProgressBilling `json:"progress_billing,omitempty"`
+
+	// EarlyPaymentDiscount advertise a discounted net payable for early payment, see EarlyPaymentDiscount
+	EarlyPaymentDiscount *EarlyPaymentDiscount `json:"early_payment_discount,omitempty"`
+
+	// ReverseCharge applies 0% instead of items and shipping taxes, and renders Options.TextReverseCharge.
+	// Additional items Taxes are not VAT, they are kept. Items and Shipping are left unchanged.
+	ReverseCharge bool `json:"reverse_charge,omitempty"`
+
+	// WithholdingTax withheld by the customer, computed on the total without tax (see Document.Withholding)
+	WithholdingTax *Tax `json:"withholding_tax,omitempty"`
+
+	// EPCPayment renders a SEPA Credit Transfer QR code for the net payable (see Document.EPCPayload)
+	EPCPayment *EPCPayment `json:"epc_payment,omitempty"`
+
+	// PaymentInfo renders the bank transfer details at the bottom of the last page
+	PaymentInfo *PaymentInfo `json:"payment_info,omitempty"`
+
+	// SwissQRBill renders a swiss QR-bill payment slip at the bottom of the last page (see Document.SwissQRBillPayload)
+	SwissQRBill *SwissQRBill `json:"swiss_qr_bill,omitempty"`
+
+	// RemittanceSlip renders a tear-off payment stub at the bottom of the last page
+	RemittanceSlip *RemittanceSlip `json:"remittance_slip,omitempty"`
+
+	// Signatures areas rendered at the bottom of the last page (see Document.AppendSignature)
+	Signatures []*Signature `json:"signatures,omitempty" validate:"max=2,dive,required"`
+
+	// Attachments embedded in the pdf (see Document.Attach)
+	Attachments []*Attachment `json:"attachments,omitempty" validate:"dive"`
 }
 
-// Pdf returns the underlying *fpdf.Fpdf used to build document
+// Pdf returns the underlying *fpdf.Fpdf used to build document, replaced by a new one when building again
 func (doc *Document) Pdf() *fpdf.Fpdf {
 	return doc.pdf
 }
@@ -40,13 +120,35 @@ func (doc *Document) SetUnicodeTranslator(fn UnicodeTranslateFunc) {
 	doc.Options.UnicodeTranslateFunc = fn
 }
 
-// encodeString encodes the string using doc.Options.UnicodeTranslateFunc
+// encodeString encodes the string using doc.Options.UnicodeTranslateFunc, or the pdf cp1252 translator when nil.
+// UTF-8 fonts use str as is.
 func (doc *Document) encodeString(str string) string {
-	return doc.Options.UnicodeTranslateFunc(str)
+	if doc.utf8Font() {
+		return str
+	}
+
+	if doc.Options.UnicodeTranslateFunc != nil {
+		return doc.Options.UnicodeTranslateFunc(str)
+	}
+
+	return doc.translate(str)
+}
+
+// title returns Document.Title, or the document type as string
+func (d *Document) title() string {
+	if len(d.Title) > 0 {
+		return d.Title
+	}
+
+	return d.typeAsString()
 }
 
 // typeAsString return the document type as string
 func (d *Document) typeAsString() string {
+	if d.Type == Invoice && d.Deposit != nil {
+		return d.depositTitle()
+	}
+
 	if d.Type == Invoice {
 		return d.Options.TextTypeInvoice
 	}
@@ -55,5 +157,27 @@ func (d *Document) typeAsString() string {
 		return d.Options.TextTypeQuotation
 	}
 
+	if d.Type == CreditNote {
+		return d.Options.TextTypeCreditNote
+	}
+
+	if d.Type == ProForma {
+		return d.Options.TextTypeProForma
+	}
+
 	return d.Options.TextTypeDeliveryNote
 }
+
+// hidePrices returns true if prices must not be rendered
+func (d *Document) hidePrices() bool {
+	return d.Type == DeliveryNote && d.Options.HideDeliveryNotePrices
+}
+
+// formatTotal format a total amount, as a negative amount for credit notes
+func (d *Document) formatTotal(value decimal.Decimal) string {
+	if d.Type == CreditNote {
+		value = value.Neg()
+	}
+
+	return d.ac.FormatMoneyDecimal(value)
+}
@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// EarlyPaymentDiscount define a cash discount offered for payment within Days of the issue date
+// ex 2% within 10 days. It is only advertised below totals, the net payable is unchanged.
+type EarlyPaymentDiscount struct {
+	Percent string `json:"percent,omitempty" validate:"required"` // Discount in percent ex 2
+	Days    int    `json:"days,omitempty" validate:"gt=0"`
+
+	_percent decimal.Decimal
+}
+
+// Prepare convert strings to decimal
+func (d *EarlyPaymentDiscount) Prepare() error {
+	percent, err := parseDecimal("percent", d.Percent)
+	if err != nil {
+		return err
+	}
+	d._percent = percent
+
+	return nil
+}
+
+// EarlyPaymentDiscountAmount return the early payment discount, taken on the net payable
+func (doc *Document) EarlyPaymentDiscountAmount() decimal.Decimal {
+	if doc.EarlyPaymentDiscount == nil {
+		return decimal.Zero
+	}
+
+	return doc.Options.round(doc.NetPayable().Mul(doc.EarlyPaymentDiscount._percent).Div(decimal.NewFromFloat(100)))
+}
+
+// EarlyPaymentTotal return the net payable reduced by the early payment discount
+func (doc *Document) EarlyPaymentTotal() decimal.Decimal {
+	return doc.NetPayable().Sub(doc.EarlyPaymentDiscountAmount())
+}
+
+// appendEarlyPaymentDiscount mention below totals and payment term
+func (doc *Document) appendEarlyPaymentDiscount() {
+	discount := doc.EarlyPaymentDiscount
+
+	text := fmt.Sprintf(
+		doc.Options.TextEarlyPaymentDiscount,
+		discount.Percent,
+		discount.Days,
+		doc.formatTotal(doc.EarlyPaymentTotal()),
+	)
+
+	offset := 15.0
+	if len(doc.paymentTerm()) > 0 {
+		offset = 6
+	}
+	doc.pdf.SetY(doc.pdf.GetY() + offset)
+
+	doc.pdf.SetX(doc.rtlX(doc.rightX(120), 80))
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.MultiCell(80, 4, doc.encodeString(text), "0", doc.rtlAlign("R"), false)
+}
@@ -0,0 +1,179 @@
+package generator
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// eInvoice is the EN 16931 breakdown of a document shared by the xml invoice formats (see MarshalFacturX
+// and MarshalUBL): lines net amounts, document allowances and charges by tax rate, and rounded totals.
+type eInvoice struct {
+	lines []*eInvoiceLine
+	taxes []*eInvoiceTax
+
+	lineTotal      decimal.Decimal
+	allowanceTotal decimal.Decimal
+	chargeTotal    decimal.Decimal
+	taxBasis       decimal.Decimal
+	taxTotal       decimal.Decimal
+	grandTotal     decimal.Decimal
+	rounding       decimal.Decimal
+
+	precision int32 // Options.CurrencyPrecision, amounts are rounded to it
+}
+
+// eInvoiceLine is an item line of an eInvoice
+type eInvoiceLine struct {
+	item      *Item
+	rate      decimal.Decimal
+	net       decimal.Decimal // Line total without tax, with item discounts
+	allowance decimal.Decimal // Item discounts
+}
+
+// eInvoiceTax is the tax breakdown of an eInvoice rate, the document discount is an allowance
+// applied proportionally to every rate, and shipping a charge of its own rate
+type eInvoiceTax struct {
+	rate      decimal.Decimal
+	basis     decimal.Decimal
+	tax       decimal.Decimal
+	allowance decimal.Decimal
+	charge    decimal.Decimal
+}
+
+// hasAmountTax returns true when an item or shipping tax is a fixed amount, which cannot be expressed as a rate
+func (doc *Document) hasAmountTax() bool {
+	taxes := []*Tax{}
+	for _, item := range doc.Items {
+		taxes = append(taxes, item.taxes()...)
+	}
+	if doc.showShipping() {
+		taxes = append(taxes, doc.effectiveShippingTax())
+	}
+
+	for _, tax := range taxes {
+		if tax == nil {
+			continue
+		}
+		if taxType, _ := tax.getTax(); taxType == TaxTypeAmount {
+			return true
+		}
+	}
+
+	return false
+}
+
+// taxRate returns the percent of tax, zero without tax. Amount taxes must be excluded first, see hasAmountTax
+func taxRate(tax *Tax) decimal.Decimal {
+	if tax == nil {
+		return decimal.Zero
+	}
+
+	_, rate := tax.getTax()
+	return rate
+}
+
+// hasMultipleTaxes returns true when an item has several taxes, levies cannot share its VAT category rate
+func (doc *Document) hasMultipleTaxes() bool {
+	for _, item := range doc.Items {
+		if len(item.taxes()) > 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// itemTaxRate returns the percent of the item tax, zero without tax.
+// Items with several taxes must be excluded first, see hasMultipleTaxes
+func itemTaxRate(item *Item) decimal.Decimal {
+	taxes := item.taxes()
+	if len(taxes) == 0 {
+		return decimal.Zero
+	}
+
+	return taxRate(taxes[0])
+}
+
+// issueDate returns the document date parsed with Options.DateLayout, or now without date
+func (doc *Document) issueDate() (time.Time, error) {
+	if len(doc.Date) == 0 {
+		return time.Now(), nil
+	}
+
+	return doc.parseDate(doc.Date)
+}
+
+// newEInvoice returns the eInvoice breakdown of document.
+// Document must have been validated (see Document.Validate) and have no amount taxes nor items with several taxes.
+func (doc *Document) newEInvoice() *eInvoice {
+	inv := &eInvoice{precision: int32(doc.Options.CurrencyPrecision)}
+
+	// Lines and line totals by rate
+	hundred := decimal.NewFromFloat(100)
+	taxByRate := map[string]*eInvoiceTax{}
+	rateTax := func(rate decimal.Decimal) *eInvoiceTax {
+		tax, ok := taxByRate[rate.String()]
+		if !ok {
+			tax = &eInvoiceTax{rate: rate}
+			taxByRate[rate.String()] = tax
+			inv.taxes = append(inv.taxes, tax)
+		}
+		return tax
+	}
+
+	for _, item := range doc.sortedItems() {
+		line := &eInvoiceLine{item: item, rate: itemTaxRate(item), net: item._payedPriceExclVAT.Round(inv.precision)}
+		line.allowance = item.TotalWithoutTaxAndWithoutDiscount().Round(inv.precision).Sub(line.net)
+		inv.lines = append(inv.lines, line)
+
+		inv.lineTotal = inv.lineTotal.Add(line.net)
+		tax := rateTax(line.rate)
+		tax.basis = tax.basis.Add(line.net)
+	}
+
+	// Shipping charge rate, not discounted
+	var shippingTax *eInvoiceTax
+	if doc.showShipping() {
+		shippingTax = rateTax(taxRate(doc.effectiveShippingTax()))
+	}
+
+	// Allowances, charges and taxes by rate
+	discountPercent := doc.discountPercent()
+	for _, tax := range inv.taxes {
+		tax.allowance = tax.basis.Mul(discountPercent).Div(hundred).Round(inv.precision)
+		tax.basis = tax.basis.Sub(tax.allowance)
+
+		if tax == shippingTax {
+			tax.charge = doc.shippingAmount().Round(inv.precision)
+			tax.basis = tax.basis.Add(tax.charge)
+		}
+
+		tax.tax = tax.basis.Mul(tax.rate).Div(hundred).Round(inv.precision)
+
+		inv.allowanceTotal = inv.allowanceTotal.Add(tax.allowance)
+		inv.chargeTotal = inv.chargeTotal.Add(tax.charge)
+		inv.taxTotal = inv.taxTotal.Add(tax.tax)
+	}
+
+	inv.taxBasis = inv.lineTotal.Sub(inv.allowanceTotal).Add(inv.chargeTotal)
+	inv.grandTotal = inv.taxBasis.Add(inv.taxTotal)
+	inv.rounding = doc.CashRoundingAdjustment().Round(inv.precision)
+
+	return inv
+}
+
+// amount returns value formatted with the document currency precision ex 1234.50
+func (inv *eInvoice) amount(value decimal.Decimal) string {
+	return value.StringFixed(inv.precision)
+}
+
+// duePayable returns the grand total with cash rounding
+func (inv *eInvoice) duePayable() decimal.Decimal {
+	return inv.grandTotal.Add(inv.rounding)
+}
+
+// matches returns true when the breakdown totals equal the rendered document ones
+func (inv *eInvoice) matches(doc *Document) bool {
+	return inv.taxTotal.Equal(doc.Tax().Round(inv.precision)) && inv.grandTotal.Equal(doc.TotalWithTax().Round(inv.precision))
+}
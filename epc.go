@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf/contrib/barcode"
+	"github.com/shopspring/decimal"
+)
+
+// ErrEPCInvalidCurrency when document currency is not EUR, the only currency allowed by the EPC standard
+var ErrEPCInvalidCurrency = errors.New("epc qr code: currency must be EUR")
+
+// ErrEPCInvalidAmount when document net payable is out of the EPC standard range (0.01 to 999999999.99)
+var ErrEPCInvalidAmount = errors.New("epc qr code: invalid amount")
+
+// ErrEPCReferenceAndRemittance when both structured reference and unstructured remittance are set
+var ErrEPCReferenceAndRemittance = errors.New("epc qr code: reference and remittance are mutually exclusive")
+
+// EPCPayment define the beneficiary informations of an EPC QR code (SEPA Credit Transfer, EPC069-12).
+// The QR code amount is the document net payable.
+type EPCPayment struct {
+	BeneficiaryName string `json:"beneficiary_name,omitempty" validate:"required,min=1,max=70"`
+	IBAN            string `json:"iban,omitempty" validate:"required,min=15,max=34"`
+	BIC             string `json:"bic,omitempty" validate:"omitempty,min=8,max=11"`
+	Purpose         string `json:"purpose,omitempty" validate:"omitempty,len=4"`
+	Reference       string `json:"reference,omitempty" validate:"max=35"`   // Structured creditor reference (ISO 11649)
+	Remittance      string `json:"remittance,omitempty" validate:"max=140"` // Unstructured remittance information
+	Information     string `json:"information,omitempty" validate:"max=70"` // Beneficiary to originator information
+
+	// Position and size of the QR code, defaults to left margin below totals, 30mm wide
+	X    float64 `json:"x,omitempty"`
+	Y    float64 `json:"y,omitempty"`
+	Size float64 `json:"size,omitempty"`
+}
+
+// EPCPayload returns the EPC QR code payload of the document
+func (doc *Document) EPCPayload() (string, error) {
+	p := doc.EPCPayment
+
+	if doc.Options.CurrencyCode != "EUR" {
+		return "", ErrEPCInvalidCurrency
+	}
+
+	if len(p.Reference) > 0 && len(p.Remittance) > 0 {
+		return "", ErrEPCReferenceAndRemittance
+	}
+
+	amount := doc.NetPayable().Round(2)
+	if amount.LessThan(decimal.New(1, -2)) || amount.GreaterThan(decimal.RequireFromString("999999999.99")) {
+		return "", ErrEPCInvalidAmount
+	}
+
+	lines := []string{
+		"BCD",
+		"002",
+		"1", // UTF-8
+		"SCT",
+		strings.ToUpper(strings.ReplaceAll(p.BIC, " ", "")),
+		p.BeneficiaryName,
+		strings.ToUpper(strings.ReplaceAll(p.IBAN, " ", "")),
+		"EUR" + amount.StringFixed(2),
+		p.Purpose,
+		p.Reference,
+		p.Remittance,
+		p.Information,
+	}
+
+	// Trailing empty lines are omitted
+	for len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// appendEPCQRCode to document
+func (doc *Document) appendEPCQRCode() error {
+	payload, err := doc.EPCPayload()
+	if err != nil {
+		return err
+	}
+
+	size := doc.EPCPayment.Size
+	if size == 0 {
+		size = 30
+	}
+
+	x, y := doc.EPCPayment.X, doc.EPCPayment.Y
+	if x == 0 {
+		x = doc.margins().Left
+	}
+	if y == 0 {
+		y = doc.pdf.GetY() + 10
+		if y+size+5 > doc.maxPageHeight() {
+			doc.pdf.AddPage()
+			y = doc.margins().Top
+		}
+	}
+
+	// EPC standard requires error correction level M
+	key := barcode.RegisterQR(doc.pdf, payload, qr.M, qr.Unicode)
+	barcode.Barcode(doc.pdf, key, x, y, size, size, false)
+
+	// Title
+	doc.pdf.SetXY(x, y+size+1)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.CellFormat(size, 3, doc.encodeString(doc.Options.TextEPCQRCodeTitle), "0", 0, "C", false, 0, "")
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetY(y + size + 5)
+
+	return doc.pdf.Error()
+}
@@ -0,0 +1,13 @@
+package generator
+
+// EstimateHeight lays out the document without writing its pdf, and returns the height (mm) of its items
+// table, summed over pages, with its number of pages: the items fit on a single page when pages is 1.
+// Like Build, settings applied to a previous pdf (see Document.Pdf) are lost.
+func (doc *Document) EstimateHeight() (height float64, pages int, err error) {
+	pdf, err := doc.Build()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return doc._itemsHeight, pdf.PageCount() - doc._pageOffset, nil
+}
@@ -0,0 +1,472 @@
+package generator
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/shopspring/decimal"
+)
+
+// FacturXFileName is the name of the xml invoice attached to Factur-X documents
+const FacturXFileName string = "factur-x.xml"
+
+// FacturX errors
+var (
+	// ErrFacturXInvalidType when the document is not an invoice or a credit note
+	ErrFacturXInvalidType = errors.New("factur-x: document must be an invoice or a credit note")
+
+	// ErrFacturXAmountTax when an item tax is a fixed amount, which cannot be expressed as a rate
+	ErrFacturXAmountTax = errors.New("factur-x: amount taxes are not supported")
+
+	// ErrFacturXMultipleTaxes when an item has several taxes, which cannot be expressed as a single VAT category
+	ErrFacturXMultipleTaxes = errors.New("factur-x: items with several taxes are not supported")
+
+	// ErrFacturXInvalidDate when the document date cannot be parsed
+	ErrFacturXInvalidDate = errors.New("factur-x: invalid document date")
+
+	// ErrFacturXTotalsMismatch when the xml totals differ from the rendered ones
+	ErrFacturXTotalsMismatch = errors.New("factur-x: xml totals do not match document totals")
+)
+
+// BuildFacturX build pdf document like Build, and embed a Factur-X (BASIC profile)
+// CII xml invoice derived from the document data, along with the PDF/A-3 XMP metadata.
+//
+// Note that fpdf does not write output intents, so a strict PDF/A-3 validation
+// requires post-processing the output.
+func (doc *Document) BuildFacturX() (*fpdf.Fpdf, error) {
+	if doc.Type != Invoice && doc.Type != CreditNote {
+		return nil, ErrFacturXInvalidType
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	xmlBytes, err := doc.MarshalFacturX()
+	if err != nil {
+		return nil, err
+	}
+
+	pdf.SetAttachments(append(doc.pdfAttachments(), fpdf.Attachment{
+		Content:     xmlBytes,
+		Filename:    FacturXFileName,
+		Description: "Factur-X invoice",
+	}))
+	pdf.SetXmpMetadata([]byte(facturXXmp))
+
+	return pdf, pdf.Error()
+}
+
+// MarshalFacturX returns the Factur-X (BASIC profile) CII xml of the document.
+// Document must have been validated (see Document.Validate).
+func (doc *Document) MarshalFacturX() ([]byte, error) {
+	issueDate, err := doc.issueDate()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrFacturXInvalidDate, err)
+	}
+
+	if doc.hasAmountTax() {
+		return nil, ErrFacturXAmountTax
+	}
+
+	if doc.hasMultipleTaxes() {
+		return nil, ErrFacturXMultipleTaxes
+	}
+
+	typeCode := "380"
+	if doc.Type == CreditNote {
+		typeCode = "381"
+	}
+
+	currency := doc.Options.CurrencyCode
+	inv := &cxiInvoice{
+		XmlnsRsm: "urn:un:unece:uncefact:data:standard:CrossIndustryInvoice:100",
+		XmlnsRam: "urn:un:unece:uncefact:data:standard:ReusableAggregateBusinessInformationEntity:100",
+		XmlnsUdt: "urn:un:unece:uncefact:data:standard:UnqualifiedDataType:100",
+		XmlnsQdt: "urn:un:unece:uncefact:data:standard:QualifiedDataType:100",
+		Context: cxiContext{
+			Guideline: cxiID{ID: "urn:cen.eu:en16931:2017#compliant#urn:factur-x.eu:1p0:basic"},
+		},
+		Header: cxiHeader{
+			ID:        doc.Ref,
+			TypeCode:  typeCode,
+			IssueDate: cxiDateTime{Date: cxiDate{Format: "102", Value: issueDate.Format("20060102")}},
+		},
+	}
+
+	breakdown := doc.newEInvoice()
+
+	// Lines
+	for n, l := range breakdown.lines {
+		line := cxiLine{
+			Document:  cxiLineDocument{LineID: strconv.Itoa(n + 1)},
+			Product:   cxiProduct{Name: l.item.Name},
+			Agreement: cxiLineAgreement{NetPrice: cxiPrice{Amount: breakdown.amount(l.item.unitCostWithoutTax())}},
+			Delivery:  cxiLineDelivery{Quantity: cxiQuantity{UnitCode: "C62", Value: l.item._quantity.String()}},
+			Settlement: cxiLineSettlement{
+				Tax:     newCxiTax(l.rate, nil, nil, breakdown.precision),
+				Summary: cxiLineSummary{LineTotal: breakdown.amount(l.net)},
+			},
+		}
+
+		if !l.allowance.IsZero() {
+			line.Settlement.Allowances = []cxiAllowance{{
+				Indicator: cxiIndicator{Value: false},
+				Amount:    breakdown.amount(l.allowance),
+			}}
+		}
+
+		inv.Transaction.Lines = append(inv.Transaction.Lines, line)
+	}
+
+	// Parties
+	inv.Transaction.Agreement.Seller = newCxiParty(doc.Company)
+	inv.Transaction.Agreement.Buyer = newCxiParty(doc.Customer)
+	inv.Transaction.Agreement.BuyerReference = doc.ClientRef
+
+	// Settlement, taxes and allowances by rate
+	settlement := &inv.Transaction.Settlement
+	settlement.Currency = currency
+
+	for _, t := range breakdown.taxes {
+		basis, tax := t.basis, t.tax
+		settlement.Taxes = append(settlement.Taxes, newCxiTax(t.rate, &basis, &tax, breakdown.precision))
+
+		if !t.allowance.IsZero() {
+			settlement.Allowances = append(settlement.Allowances, cxiAllowance{
+				Indicator: cxiIndicator{Value: false},
+				Amount:    breakdown.amount(t.allowance),
+				Tax:       newCxiTax(t.rate, nil, nil, breakdown.precision),
+			})
+		}
+
+		if !t.charge.IsZero() {
+			settlement.Allowances = append(settlement.Allowances, cxiAllowance{
+				Indicator: cxiIndicator{Value: true},
+				Amount:    breakdown.amount(t.charge),
+				Reason:    doc.Options.TextTotalShipping,
+				Tax:       newCxiTax(t.rate, nil, nil, breakdown.precision),
+			})
+		}
+	}
+
+	if len(doc.PaymentTerm) > 0 {
+		settlement.PaymentTerms = &cxiPaymentTerms{Description: doc.PaymentTerm}
+	}
+
+	settlement.Summary = cxiSummary{
+		LineTotal:  breakdown.amount(breakdown.lineTotal),
+		TaxBasis:   breakdown.amount(breakdown.taxBasis),
+		TaxTotal:   cxiAmount{Currency: currency, Value: breakdown.amount(breakdown.taxTotal)},
+		GrandTotal: breakdown.amount(breakdown.grandTotal),
+		DuePayable: breakdown.amount(breakdown.duePayable()),
+	}
+	if !breakdown.allowanceTotal.IsZero() {
+		settlement.Summary.AllowanceTotal = breakdown.amount(breakdown.allowanceTotal)
+	}
+	if !breakdown.chargeTotal.IsZero() {
+		settlement.Summary.ChargeTotal = breakdown.amount(breakdown.chargeTotal)
+	}
+	if !breakdown.rounding.IsZero() {
+		settlement.Summary.Rounding = breakdown.amount(breakdown.rounding)
+	}
+
+	// Reverse charge taxes category
+	if doc.ReverseCharge {
+		for n := range inv.Transaction.Lines {
+			inv.Transaction.Lines[n].Settlement.Tax.CategoryCode = "AE"
+		}
+		for _, allowance := range settlement.Allowances {
+			allowance.Tax.CategoryCode = "AE"
+		}
+		for _, tax := range settlement.Taxes {
+			tax.CategoryCode = "AE"
+			tax.ExemptionReason = doc.Options.TextReverseCharge
+		}
+	}
+
+	// Check xml totals against document ones
+	if !breakdown.matches(doc) {
+		return nil, ErrFacturXTotalsMismatch
+	}
+
+	out, err := xml.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), out...), nil
+}
+
+// newCxiParty returns the cii trade party of contact
+func newCxiParty(c *Contact) cxiParty {
+	party := cxiParty{Name: c.Name}
+
+	if c.Address != nil {
+		party.Address = &cxiAddress{
+			Postcode: c.Address.PostalCode,
+			LineOne:  c.Address.Address,
+			LineTwo:  c.Address.Address2,
+			City:     c.Address.City,
+		}
+
+		if len(c.Address.Country) == 2 {
+			party.Address.Country = c.Address.Country
+		}
+	}
+
+	if len(c.VATNumber) > 0 {
+		party.TaxRegistration = &cxiTaxRegistration{ID: cxiSchemeID{SchemeID: "VA", Value: c.VATNumber}}
+	}
+
+	return party
+}
+
+// newCxiTax returns the cii trade tax for rate, with optional basis and tax amounts of currency precision
+func newCxiTax(rate decimal.Decimal, basis *decimal.Decimal, tax *decimal.Decimal, precision int32) *cxiTax {
+	t := &cxiTax{
+		TypeCode:     "VAT",
+		CategoryCode: "S",
+		Rate:         rate.StringFixed(2),
+	}
+
+	if rate.IsZero() {
+		t.CategoryCode = "Z"
+	}
+
+	if tax != nil {
+		t.Calculated = tax.StringFixed(precision)
+	}
+
+	if basis != nil {
+		t.Basis = basis.StringFixed(precision)
+	}
+
+	return t
+}
+
+// Cross Industry Invoice (CII) xml structure, limited to the Factur-X BASIC profile
+type cxiInvoice struct {
+	XMLName     xml.Name       `xml:"rsm:CrossIndustryInvoice"`
+	XmlnsRsm    string         `xml:"xmlns:rsm,attr"`
+	XmlnsRam    string         `xml:"xmlns:ram,attr"`
+	XmlnsUdt    string         `xml:"xmlns:udt,attr"`
+	XmlnsQdt    string         `xml:"xmlns:qdt,attr"`
+	Context     cxiContext     `xml:"rsm:ExchangedDocumentContext"`
+	Header      cxiHeader      `xml:"rsm:ExchangedDocument"`
+	Transaction cxiTransaction `xml:"rsm:SupplyChainTradeTransaction"`
+}
+
+type cxiID struct {
+	ID string `xml:"ram:ID"`
+}
+
+type cxiContext struct {
+	Guideline cxiID `xml:"ram:GuidelineSpecifiedDocumentContextParameter"`
+}
+
+type cxiDate struct {
+	Format string `xml:"format,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type cxiDateTime struct {
+	Date cxiDate `xml:"udt:DateTimeString"`
+}
+
+type cxiHeader struct {
+	ID        string      `xml:"ram:ID"`
+	TypeCode  string      `xml:"ram:TypeCode"`
+	IssueDate cxiDateTime `xml:"ram:IssueDateTime"`
+}
+
+type cxiTransaction struct {
+	Lines      []cxiLine     `xml:"ram:IncludedSupplyChainTradeLineItem"`
+	Agreement  cxiAgreement  `xml:"ram:ApplicableHeaderTradeAgreement"`
+	Delivery   struct{}      `xml:"ram:ApplicableHeaderTradeDelivery"`
+	Settlement cxiSettlement `xml:"ram:ApplicableHeaderTradeSettlement"`
+}
+
+type cxiLineDocument struct {
+	LineID string `xml:"ram:LineID"`
+}
+
+type cxiProduct struct {
+	Name string `xml:"ram:Name"`
+}
+
+type cxiPrice struct {
+	Amount string `xml:"ram:ChargeAmount"`
+}
+
+type cxiLineAgreement struct {
+	NetPrice cxiPrice `xml:"ram:NetPriceProductTradePrice"`
+}
+
+type cxiQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type cxiLineDelivery struct {
+	Quantity cxiQuantity `xml:"ram:BilledQuantity"`
+}
+
+type cxiLineSummary struct {
+	LineTotal string `xml:"ram:LineTotalAmount"`
+}
+
+type cxiLineSettlement struct {
+	Tax        *cxiTax        `xml:"ram:ApplicableTradeTax"`
+	Allowances []cxiAllowance `xml:"ram:SpecifiedTradeAllowanceCharge"`
+	Summary    cxiLineSummary `xml:"ram:SpecifiedTradeSettlementLineMonetarySummation"`
+}
+
+type cxiLine struct {
+	Document   cxiLineDocument   `xml:"ram:AssociatedDocumentLineDocument"`
+	Product    cxiProduct        `xml:"ram:SpecifiedTradeProduct"`
+	Agreement  cxiLineAgreement  `xml:"ram:SpecifiedLineTradeAgreement"`
+	Delivery   cxiLineDelivery   `xml:"ram:SpecifiedLineTradeDelivery"`
+	Settlement cxiLineSettlement `xml:"ram:SpecifiedLineTradeSettlement"`
+}
+
+type cxiAddress struct {
+	Postcode string `xml:"ram:PostcodeCode,omitempty"`
+	LineOne  string `xml:"ram:LineOne,omitempty"`
+	LineTwo  string `xml:"ram:LineTwo,omitempty"`
+	City     string `xml:"ram:CityName,omitempty"`
+	Country  string `xml:"ram:CountryID,omitempty"`
+}
+
+type cxiParty struct {
+	Name            string              `xml:"ram:Name"`
+	Address         *cxiAddress         `xml:"ram:PostalTradeAddress,omitempty"`
+	TaxRegistration *cxiTaxRegistration `xml:"ram:SpecifiedTaxRegistration,omitempty"`
+}
+
+type cxiTaxRegistration struct {
+	ID cxiSchemeID `xml:"ram:ID"`
+}
+
+type cxiSchemeID struct {
+	SchemeID string `xml:"schemeID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type cxiAgreement struct {
+	BuyerReference string   `xml:"ram:BuyerReference,omitempty"`
+	Seller         cxiParty `xml:"ram:SellerTradeParty"`
+	Buyer          cxiParty `xml:"ram:BuyerTradeParty"`
+}
+
+type cxiTax struct {
+	Calculated      string `xml:"ram:CalculatedAmount,omitempty"`
+	TypeCode        string `xml:"ram:TypeCode"`
+	ExemptionReason string `xml:"ram:ExemptionReason,omitempty"`
+	Basis           string `xml:"ram:BasisAmount,omitempty"`
+	CategoryCode    string `xml:"ram:CategoryCode"`
+	Rate            string `xml:"ram:RateApplicablePercent"`
+}
+
+type cxiIndicator struct {
+	Value bool `xml:"udt:Indicator"`
+}
+
+type cxiAllowance struct {
+	Indicator cxiIndicator `xml:"ram:ChargeIndicator"`
+	Amount    string       `xml:"ram:ActualAmount"`
+	Reason    string       `xml:"ram:Reason,omitempty"`
+	Tax       *cxiTax      `xml:"ram:CategoryTradeTax,omitempty"`
+}
+
+type cxiPaymentTerms struct {
+	Description string `xml:"ram:Description"`
+}
+
+type cxiAmount struct {
+	Currency string `xml:"currencyID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type cxiSummary struct {
+	LineTotal      string    `xml:"ram:LineTotalAmount"`
+	ChargeTotal    string    `xml:"ram:ChargeTotalAmount,omitempty"`
+	AllowanceTotal string    `xml:"ram:AllowanceTotalAmount,omitempty"`
+	TaxBasis       string    `xml:"ram:TaxBasisTotalAmount"`
+	TaxTotal       cxiAmount `xml:"ram:TaxTotalAmount"`
+	GrandTotal     string    `xml:"ram:GrandTotalAmount"`
+	Rounding       string    `xml:"ram:RoundingAmount,omitempty"`
+	DuePayable     string    `xml:"ram:DuePayableAmount"`
+}
+
+type cxiSettlement struct {
+	Currency     string           `xml:"ram:InvoiceCurrencyCode"`
+	Taxes        []*cxiTax        `xml:"ram:ApplicableTradeTax"`
+	Allowances   []cxiAllowance   `xml:"ram:SpecifiedTradeAllowanceCharge"`
+	PaymentTerms *cxiPaymentTerms `xml:"ram:SpecifiedTradePaymentTerms,omitempty"`
+	Summary      cxiSummary       `xml:"ram:SpecifiedTradeSettlementHeaderMonetarySummation"`
+}
+
+// facturXXmp is the XMP metadata declaring PDF/A-3B conformance and the Factur-X extension schema
+const facturXXmp string = `<?xpacket begin="` + "\ufeff" + `" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+  <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+    <rdf:Description rdf:about="" xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/">
+      <pdfaid:part>3</pdfaid:part>
+      <pdfaid:conformance>B</pdfaid:conformance>
+    </rdf:Description>
+    <rdf:Description rdf:about="" xmlns:fx="urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#">
+      <fx:DocumentType>INVOICE</fx:DocumentType>
+      <fx:DocumentFileName>factur-x.xml</fx:DocumentFileName>
+      <fx:Version>1.0</fx:Version>
+      <fx:ConformanceLevel>BASIC</fx:ConformanceLevel>
+    </rdf:Description>
+    <rdf:Description rdf:about=""
+      xmlns:pdfaExtension="http://www.aiim.org/pdfa/ns/extension/"
+      xmlns:pdfaSchema="http://www.aiim.org/pdfa/ns/schema#"
+      xmlns:pdfaProperty="http://www.aiim.org/pdfa/ns/property#">
+      <pdfaExtension:schemas>
+        <rdf:Bag>
+          <rdf:li rdf:parseType="Resource">
+            <pdfaSchema:schema>Factur-X PDFA Extension Schema</pdfaSchema:schema>
+            <pdfaSchema:namespaceURI>urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#</pdfaSchema:namespaceURI>
+            <pdfaSchema:prefix>fx</pdfaSchema:prefix>
+            <pdfaSchema:property>
+              <rdf:Seq>
+                <rdf:li rdf:parseType="Resource">
+                  <pdfaProperty:name>DocumentFileName</pdfaProperty:name>
+                  <pdfaProperty:valueType>Text</pdfaProperty:valueType>
+                  <pdfaProperty:category>external</pdfaProperty:category>
+                  <pdfaProperty:description>name of the embedded XML invoice file</pdfaProperty:description>
+                </rdf:li>
+                <rdf:li rdf:parseType="Resource">
+                  <pdfaProperty:name>DocumentType</pdfaProperty:name>
+                  <pdfaProperty:valueType>Text</pdfaProperty:valueType>
+                  <pdfaProperty:category>external</pdfaProperty:category>
+                  <pdfaProperty:description>INVOICE</pdfaProperty:description>
+                </rdf:li>
+                <rdf:li rdf:parseType="Resource">
+                  <pdfaProperty:name>Version</pdfaProperty:name>
+                  <pdfaProperty:valueType>Text</pdfaProperty:valueType>
+                  <pdfaProperty:category>external</pdfaProperty:category>
+                  <pdfaProperty:description>The actual version of the Factur-X XML schema</pdfaProperty:description>
+                </rdf:li>
+                <rdf:li rdf:parseType="Resource">
+                  <pdfaProperty:name>ConformanceLevel</pdfaProperty:name>
+                  <pdfaProperty:valueType>Text</pdfaProperty:valueType>
+                  <pdfaProperty:category>external</pdfaProperty:category>
+                  <pdfaProperty:description>The conformance level of the embedded Factur-X data</pdfaProperty:description>
+                </rdf:li>
+              </rdf:Seq>
+            </pdfaSchema:property>
+          </rdf:li>
+        </rdf:Bag>
+      </pdfaExtension:schemas>
+    </rdf:Description>
+  </rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`
@@ -0,0 +1,85 @@
+package generator
+
+import "strings"
+
+// UTF8FontFamily is the family name of the fonts registered from Options.FontFile and Options.BoldFontFile
+const UTF8FontFamily string = "UTF8Font"
+
+// TextFont define the font of a document part, see Options.TitleFont. Empty fields keep the part default font.
+// Family is a core font (Helvetica, Times, Courier) or a font registered with Document.Pdf().
+type TextFont struct {
+	Family string  `json:"family,omitempty"`
+	Style  string  `json:"style,omitempty" validate:"omitempty,oneof=R B I BI"` // R for regular, B bold, I italic
+	Size   float64 `json:"size,omitempty" validate:"gte=0"`
+}
+
+// resolve returns the font family, style and size, or the given defaults for empty fields
+func (f *TextFont) resolve(family string, style string, size float64) (string, string, float64) {
+	if f == nil {
+		return family, style, size
+	}
+
+	if len(f.Family) > 0 {
+		family = f.Family
+	}
+	if len(f.Style) > 0 {
+		style = strings.TrimPrefix(f.Style, "R")
+	}
+	if f.Size > 0 {
+		size = f.Size
+	}
+
+	return family, style, size
+}
+
+// setFont use font, or the given part default for its empty fields
+func (doc *Document) setFont(font *TextFont, family string, style string, size float64) {
+	doc.pdf.SetFont(font.resolve(family, style, size))
+}
+
+// setItemFont use Options.ItemFont for items lines, scaled down for descriptions when small
+func (doc *Document) setItemFont(small bool) {
+	family, style, size := doc.Options.ItemFont.resolve(doc.Options.Font, "", BaseTextFontSize)
+	if small {
+		size = size * SmallTextFontSize / BaseTextFontSize
+	}
+	size = doc.compactFontSize(size)
+
+	doc.pdf.SetFont(family, style, size)
+}
+
+// utf8Font returns true when document texts are rendered with a unicode font and must not be translated
+func (doc *Document) utf8Font() bool {
+	return len(doc.Options.FontFile) > 0 || doc.Options.UTF8Font
+}
+
+// registerUTF8Fonts embed Options.FontFile and Options.BoldFontFile TrueType fonts,
+// and use them as document fonts
+func (doc *Document) registerUTF8Fonts() error {
+	if len(doc.Options.FontFile) == 0 {
+		return nil
+	}
+
+	boldFontFile := doc.Options.BoldFontFile
+	if len(boldFontFile) == 0 {
+		boldFontFile = doc.Options.FontFile
+	}
+
+	// Italic styles are used by notes html, and use the regular and bold files
+	doc.pdf.AddUTF8Font(UTF8FontFamily, "", doc.Options.FontFile)
+	doc.pdf.AddUTF8Font(UTF8FontFamily, "I", doc.Options.FontFile)
+	doc.pdf.AddUTF8Font(UTF8FontFamily, "B", boldFontFile)
+	doc.pdf.AddUTF8Font(UTF8FontFamily, "BI", boldFontFile)
+
+	if err := doc.pdf.Error(); err != nil {
+		return err
+	}
+
+	// Set in New when FontFile is known, Options shared by documents are not modified while building
+	if doc.Options.Font != UTF8FontFamily || doc.Options.BoldFont != UTF8FontFamily {
+		doc.Options.Font = UTF8FontFamily
+		doc.Options.BoldFont = UTF8FontFamily
+	}
+
+	return nil
+}
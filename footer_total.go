@@ -0,0 +1,27 @@
+package generator
+
+import "fmt"
+
+// appendFooterTotal draw, right aligned at y, the total of the items rendered so far, or the document
+// total with tax once the totals block is rendered, see Options.ShowFooterTotal
+func (doc *Document) appendFooterTotal(y float64) {
+	title, total := doc.Options.TextFooterCarriedForward, doc._runningTotal
+	if doc._totalsRendered {
+		title, total = doc.Options.TextTotalWithTax, doc.TotalWithTax()
+	}
+
+	width := 80.0
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", SmallTextFontSize)
+	doc.pdf.SetXY(doc.rtlX(doc.rightX(200-BaseMargin)-width, width), y)
+	doc.pdf.CellFormat(
+		width,
+		5,
+		doc.encodeString(fmt.Sprintf("%s: %s", title, doc.formatTotal(total))),
+		"0",
+		0,
+		doc.rtlAlign("R"),
+		false,
+		0,
+		"",
+	)
+}
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"strconv"
+)
+
+// footnoteMarkerWidth is the width (mm) reserved after an item name for its footnote marker
+const footnoteMarkerWidth = 3
+
+// footnoteMarkerFontSize is the font size of footnotes markers, raised above the text baseline
+const footnoteMarkerFontSize = 5
+
+// footnoteWidth returns the width (mm) reserved for the item footnote marker after its name
+func (i *Item) footnoteWidth() float64 {
+	if len(i.Footnote) == 0 {
+		return 0
+	}
+
+	return footnoteMarkerWidth
+}
+
+// appendFootnoteMarkerTo draw the item footnote marker after the last line of its name, drawn in a x, width cell
+// at y, y being the top of this line. Markers are numbered in render order, the footnote is collected for appendFootnotes.
+func (i *Item) appendFootnoteMarkerTo(doc *Document, x float64, width float64, y float64, align string) {
+	doc._footnotes = append(doc._footnotes, i.Footnote)
+
+	lines := doc.pdf.SplitLines([]byte(doc.encodeString(i.Name)), width)
+	lineWidth := 0.0
+	if len(lines) > 0 {
+		lineWidth = doc.pdf.GetStringWidth(string(lines[len(lines)-1]))
+	}
+
+	// Last line position in its cell
+	margin := doc.pdf.GetCellMargin()
+	start := x + margin
+	switch align {
+	case "R":
+		start = x + width - margin - lineWidth
+	case "C":
+		start = x + (width-lineWidth)/2
+	}
+
+	// Marker after the text, in reading order
+	markerX := start + lineWidth
+	if doc.Options.RTL {
+		markerX = start - footnoteMarkerWidth
+	}
+
+	doc.appendFootnoteMarker(markerX, y, len(doc._footnotes))
+	doc.setItemFont(false)
+}
+
+// appendFootnoteMarker draw footnote marker number at x, raised in a line starting at y.
+// The font is left to the marker one, callers reset theirs.
+func (doc *Document) appendFootnoteMarker(x float64, y float64, number int) {
+	doc.pdf.SetFont(doc.Options.Font, "", footnoteMarkerFontSize)
+	doc.pdf.SetXY(x, y)
+	doc.pdf.CellFormat(footnoteMarkerWidth, 2, strconv.Itoa(number), "0", 0, "L", false, 0, "")
+}
+
+// appendFootnotes append the numbered list of items footnotes, in their markers order
+func (doc *Document) appendFootnotes() {
+	if len(doc._footnotes) == 0 {
+		return
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+
+	for index, footnote := range doc._footnotes {
+		if doc.pdf.GetY()+4 > doc.maxPageHeight() {
+			doc.pdf.AddPage()
+		}
+
+		y := doc.pdf.GetY()
+		doc.appendFootnoteMarker(doc.rtlX(doc.margins().Left, footnoteMarkerWidth), y, index+1)
+		doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+
+		width := doc.contentWidth() - footnoteMarkerWidth
+		doc.pdf.SetXY(doc.rtlX(doc.margins().Left+footnoteMarkerWidth, width), y)
+		doc.pdf.MultiCell(width, 3, doc.encodeString(footnote), "0", doc.rtlAlign("L"), false)
+		doc.pdf.SetY(doc.pdf.GetY() + 1)
+	}
+
+	// Reset font
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+}
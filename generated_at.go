@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTimezone when Options.GeneratedAtTimezone is not a known IANA time zone
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// generatedAtLocation returns the location of Options.GeneratedAtTimezone, local time when empty
+func (o *Options) generatedAtLocation() (*time.Location, error) {
+	if len(o.GeneratedAtTimezone) == 0 {
+		return time.Local, nil
+	}
+
+	location, err := time.LoadLocation(o.GeneratedAtTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTimezone, err)
+	}
+
+	return location, nil
+}
+
+// appendGeneratedAt draw TextGeneratedAt with the build time at y, left aligned on the page margin,
+// see Options.ShowGeneratedAt
+func (doc *Document) appendGeneratedAt(y float64) {
+	width := 80.0
+	doc.pdf.SetFont(doc.Options.Font, "", ExtraSmallTextFontSize)
+	doc.pdf.SetXY(doc.rtlX(doc.margins().Left, width), y)
+	doc.pdf.CellFormat(
+		width,
+		5,
+		doc.encodeString(fmt.Sprintf(doc.Options.TextGeneratedAt, doc._generatedAt.Format(doc.Options.GeneratedAtLayout))),
+		"0",
+		0,
+		doc.rtlAlign("L"),
+		false,
+		0,
+		"",
+	)
+}
@@ -6,35 +6,51 @@ import (
 
 	"github.com/creasty/defaults"
 	"github.com/go-pdf/fpdf"
-	"github.com/leekchan/accounting"
 )
 
 var ErrInvalidDocumentType = errors.New("invalid document type")
 
-// New return a new documents with provided types and defaults
+// New return a new documents with provided types and defaults.
+// Options are completed in place: once New returned, they can be shared by documents built concurrently.
 func New(docType string, options *Options) (*Document, error) {
+	options.applyLanguage()
+	options.applyTheme()
 	_ = defaults.Set(options)
 
-	if docType != Invoice && docType != Quotation && docType != DeliveryNote {
-		return nil, ErrInvalidDocumentType
-	}
-
 	doc := &Document{
 		Options: options,
 		Type:    docType,
 	}
 
-	// Prepare pdf
-	doc.pdf = fpdf.New("P", "mm", "A4", "")
-	doc.Options.UnicodeTranslateFunc = doc.pdf.UnicodeTranslatorFromDescriptor("")
-
-	// Prepare accounting
-	doc.ac = accounting.Accounting{
-		Symbol:    doc.Options.CurrencySymbol,
-		Precision: doc.Options.CurrencyPrecision,
-		Thousand:  doc.Options.CurrencyThousand,
-		Decimal:   doc.Options.CurrencyDecimal,
+	if err := doc.init(); err != nil {
+		return nil, err
 	}
 
 	return doc, nil
 }
+
+// init check the document type and prepare its pdf, once its options are completed
+func (doc *Document) init() error {
+	// UTF-8 fonts family, see Document.registerUTF8Fonts
+	if len(doc.Options.FontFile) > 0 {
+		doc.Options.Font = UTF8FontFamily
+		doc.Options.BoldFont = UTF8FontFamily
+	}
+
+	if doc.Type != Invoice && doc.Type != Quotation && doc.Type != DeliveryNote && doc.Type != CreditNote && doc.Type != ProForma {
+		return ErrInvalidDocumentType
+	}
+
+	// Prepare pdf
+	doc.newPdf()
+
+	return nil
+}
+
+// newPdf replace the document pdf by a new one, of Options.PageSize and Options.Orientation.
+// Its resources are sorted, so that a document is rendered to the same bytes again (but its creation date).
+func (doc *Document) newPdf() {
+	doc.pdf = fpdf.New(doc.Options.pdfOrientation(), "mm", doc.Options.PageSize, "")
+	doc.pdf.SetCatalogSort(true)
+	doc.translate = doc.pdf.UnicodeTranslatorFromDescriptor("")
+}
@@ -1,9 +1,26 @@
 package generator
 
 import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
 	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
 )
 
 func TestNewWithInvalidType(t *testing.T) {
@@ -82,10 +99,10 @@ func TestNew(t *testing.T) {
 
 	for i := 0; i < 10; i++ {
 		doc.AppendItem(&Item{
-			Name:         "Cupcake ipsum dolor sit amet bonbon, coucou bonbon lala jojo, mama titi toto",
-			Description:  "Cupcake ipsum dolor sit amet bonbon, Cupcake ipsum dolor sit amet bonbon, Cupcake ipsum dolor sit amet bonbon",
-			PriceExclVAT: "99876.89",
-			PriceInclVAT: "2",
+			Name:        "Cupcake ipsum dolor sit amet bonbon, coucou bonbon lala jojo, mama titi toto",
+			Description: "Cupcake ipsum dolor sit amet bonbon, Cupcake ipsum dolor sit amet bonbon, Cupcake ipsum dolor sit amet bonbon",
+			UnitCost:    "99876.89",
+			Quantity:    "2",
 			Tax: &Tax{
 				Percent: "20",
 			},
@@ -93,9 +110,9 @@ func TestNew(t *testing.T) {
 	}
 
 	doc.AppendItem(&Item{
-		Name:         "Test",
-		PriceExclVAT: "99876.89",
-		PriceInclVAT: "2",
+		Name:     "Test",
+		UnitCost: "99876.89",
+		Quantity: "2",
 		Tax: &Tax{
 			Amount: "89",
 		},
@@ -105,18 +122,18 @@ func TestNew(t *testing.T) {
 	})
 
 	doc.AppendItem(&Item{
-		Name:         "Test",
-		PriceExclVAT: "3576.89",
-		PriceInclVAT: "2",
+		Name:     "Test",
+		UnitCost: "3576.89",
+		Quantity: "2",
 		Discount: &Discount{
 			Percent: "50",
 		},
 	})
 
 	doc.AppendItem(&Item{
-		Name:         "Test",
-		PriceExclVAT: "889.89",
-		PriceInclVAT: "2",
+		Name:     "Test",
+		UnitCost: "889.89",
+		Quantity: "2",
 		Discount: &Discount{
 			Amount: "234.67",
 		},
@@ -144,3 +161,4050 @@ func TestNew(t *testing.T) {
 		t.Errorf(err.Error())
 	}
 }
+
+// newTestDocument returns a minimal valid document to build upon in tests
+func newTestDocument(t *testing.T, options *Options) *Document {
+	t.Helper()
+
+	doc, err := New(Invoice, options)
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	doc.SetRef("ref")
+	doc.SetCompany(&Contact{Name: "Test Company"})
+	doc.SetCustomer(&Contact{Name: "Test Customer"})
+
+	return doc
+}
+
+func TestBuildWithInvalidPayedPrice(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{
+		Name:              "Test",
+		UnitCost:          "10",
+		Quantity:          "2",
+		PayedPriceInclVAT: "12,50",
+		Tax:               &Tax{Percent: "20"},
+		Discount:          &Discount{Percent: "10"},
+	})
+
+	if _, err := doc.Build(); err == nil {
+		t.Fatalf("expected error on invalid payed price, got nil")
+	}
+}
+
+// buildToString builds the document without compression and returns the raw pdf
+func buildToString(t *testing.T, doc *Document) string {
+	t.Helper()
+
+	doc.pdf.SetCompression(false)
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := pdf.Output(buf); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	return buf.String()
+}
+
+// rebuildToString builds the document again, from a new compressed pdf, and returns the raw pdf with inflated streams
+func rebuildToString(t *testing.T, doc *Document) string {
+	t.Helper()
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := pdf.Output(buf); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	return regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).ReplaceAllStringFunc(buf.String(), func(stream string) string {
+		reader, err := zlib.NewReader(strings.NewReader(stream[len("stream\n") : len(stream)-len("\nendstream")]))
+		if err != nil {
+			return stream
+		}
+		inflated, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("got error %v", err)
+		}
+		return "stream\n" + string(inflated) + "\nendstream"
+	})
+}
+
+func TestCurrencyPrecision(t *testing.T) {
+	cases := []struct {
+		precision int
+		expected  string
+	}{
+		{precision: 0, expected: "JPY 1 235"},
+		{precision: 3, expected: "JPY 1 234.568"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{CurrencySymbol: "JPY "})
+		doc.SetCurrencyPrecision(c.precision)
+		doc.AppendItem(&Item{
+			Name:     "Test",
+			UnitCost: "1234.5678",
+			Quantity: "1",
+		})
+
+		out := buildToString(t, doc)
+		if !strings.Contains(out, "("+c.expected+")") {
+			t.Errorf("precision %d: expected %q in output", c.precision, c.expected)
+		}
+	}
+}
+
+func TestItemUnit(t *testing.T) {
+	for _, hide := range []bool{false, true} {
+		doc := newTestDocument(t, &Options{HideItemUnit: hide})
+		doc.AppendItem(&Item{
+			Name:     "Test",
+			UnitCost: "10",
+			Quantity: "5",
+			Unit:     "kg",
+		})
+
+		out := buildToString(t, doc)
+		if strings.Contains(out, "(5 kg)") == hide {
+			t.Errorf("hide %v: unexpected unit rendering", hide)
+		}
+	}
+}
+
+func TestItemSections(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Design", Section: "Phase 1", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.AppendItem(&Item{Name: "Misc", UnitCost: "5", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "Build", Section: "Phase 2", UnitCost: "200", Quantity: "1", Discount: &Discount{Percent: "50"}})
+	doc.AppendItem(&Item{Name: "Review", Section: "Phase 1", UnitCost: "50", Quantity: "2"})
+
+	out := buildToString(t, doc)
+
+	sections := doc.itemSections()
+	if len(sections) != 3 || sections[0].Title != "" || sections[1].Title != "Phase 1" || sections[2].Title != "Phase 2" {
+		t.Fatalf("unexpected sections %+v", sections)
+	}
+
+	if sub := sections[1].Subtotal(); !sub.Equal(decimal.NewFromInt(220)) {
+		t.Errorf("expected Phase 1 subtotal 220, got %s", sub)
+	}
+
+	if sub := sections[2].Subtotal(); !sub.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("expected Phase 2 subtotal 100, got %s", sub)
+	}
+
+	if total := doc.TotalWithTax(); !total.Equal(decimal.NewFromInt(325)) {
+		t.Errorf("expected grand total 325, got %s", total)
+	}
+
+	for _, expected := range []string{"(Phase 1)", "(Phase 2)", "(Subtotal)", "(\x80 220.00)"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output", expected)
+		}
+	}
+}
+
+func TestBuildFacturX(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.SetDate("02/03/2021")
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "50", Quantity: "2", Tax: &Tax{Percent: "10"}, Discount: &Discount{Percent: "10"}})
+	doc.SetDiscount(&Discount{Percent: "10"})
+	doc.pdf.SetCompression(false)
+
+	pdf, err := doc.BuildFacturX()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	xmlBytes, err := doc.MarshalFacturX()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	for _, expected := range []string{
+		"<ram:ID>urn:cen.eu:en16931:2017#compliant#urn:factur-x.eu:1p0:basic</ram:ID>",
+		`<udt:DateTimeString format="102">20210203</udt:DateTimeString>`,
+		"<ram:LineTotalAmount>190.00</ram:LineTotalAmount>",
+		"<ram:AllowanceTotalAmount>19.00</ram:AllowanceTotalAmount>",
+		`<ram:TaxTotalAmount currencyID="EUR">26.10</ram:TaxTotalAmount>`,
+		"<ram:GrandTotalAmount>197.10</ram:GrandTotalAmount>",
+	} {
+		if !strings.Contains(string(xmlBytes), expected) {
+			t.Errorf("expected %q in xml", expected)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := pdf.Output(buf); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if !strings.Contains(buf.String(), FacturXFileName) || !strings.Contains(buf.String(), "<pdfaid:part>3</pdfaid:part>") {
+		t.Errorf("expected factur-x attachment and metadata in pdf")
+	}
+
+	doc.SetType(Quotation)
+	if _, err := doc.BuildFacturX(); !errors.Is(err, ErrFacturXInvalidType) {
+		t.Errorf("expected ErrFacturXInvalidType, got %v", err)
+	}
+}
+
+func TestItemsPageBreaks(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.SetPaymentTerm("02/04/2021")
+
+	for i := 0; i < 60; i++ {
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	}
+
+	out := buildToString(t, doc)
+
+	if pages := doc.pdf.PageCount(); pages != 3 {
+		t.Fatalf("expected 3 pages, got %d", pages)
+	}
+
+	// Table titles are drawn on every page
+	if count := strings.Count(out, "("+doc.Options.TextItemsUnitCostTitle+")"); count != 3 {
+		t.Errorf("expected table titles 3 times, got %d", count)
+	}
+}
+
+func TestDocumentDiscount(t *testing.T) {
+	cases := []struct {
+		discount         *Discount
+		expectedTotal    string
+		expectedTax      string
+		expectedWithTax  string
+		expectedDescLine string
+	}{
+		// Items: 100 at 20% with 10% item discount (90), 100 tax amount 5 (100)
+		{discount: &Discount{Percent: "10"}, expectedTotal: "171", expectedTax: "21.2", expectedWithTax: "192.2", expectedDescLine: "(-10 % / -\x80 19.00)"},
+		{discount: &Discount{Amount: "38"}, expectedTotal: "152", expectedTax: "19.4", expectedWithTax: "171.4", expectedDescLine: "(-\x80 38.00 / -20.00 %)"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}, Discount: &Discount{Percent: "10"}})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "50", Quantity: "2", Tax: &Tax{Amount: "5"}})
+		doc.SetDiscount(c.discount)
+
+		out := buildToString(t, doc)
+
+		if total := doc.TotalWithoutTax(); total.String() != c.expectedTotal {
+			t.Errorf("expected total without tax %s, got %s", c.expectedTotal, total)
+		}
+
+		if tax := doc.Tax(); tax.String() != c.expectedTax {
+			t.Errorf("expected tax %s, got %s", c.expectedTax, tax)
+		}
+
+		if total := doc.TotalWithTax(); total.String() != c.expectedWithTax {
+			t.Errorf("expected total with tax %s, got %s", c.expectedWithTax, total)
+		}
+
+		if !strings.Contains(out, c.expectedDescLine) {
+			t.Errorf("expected %q in output", c.expectedDescLine)
+		}
+	}
+}
+
+func TestWithholdingTax(t *testing.T) {
+	cases := []struct {
+		tax                *Tax
+		expectedWithheld   string
+		expectedNetPayable string
+	}{
+		// Total without tax 1000, VAT 22% 220
+		{tax: &Tax{Percent: "20"}, expectedWithheld: "200", expectedNetPayable: "1020"},
+		{tax: &Tax{Amount: "150"}, expectedWithheld: "150", expectedNetPayable: "1070"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{})
+		doc.AppendItem(&Item{Name: "Consulting", UnitCost: "500", Quantity: "2", Tax: &Tax{Percent: "22"}})
+		doc.SetWithholdingTax(c.tax)
+
+		out := buildToString(t, doc)
+
+		if total := doc.TotalWithTax(); total.String() != "1220" {
+			t.Errorf("expected total with tax 1220, got %s", total)
+		}
+
+		if withheld := doc.Withholding(); withheld.String() != c.expectedWithheld {
+			t.Errorf("expected withholding %s, got %s", c.expectedWithheld, withheld)
+		}
+
+		if net := doc.NetPayable(); net.String() != c.expectedNetPayable {
+			t.Errorf("expected net payable %s, got %s", c.expectedNetPayable, net)
+		}
+
+		for _, expected := range []string{"(WITHHOLDING TAX)", "(NET PAYABLE)"} {
+			if !strings.Contains(out, expected) {
+				t.Errorf("expected %q in output", expected)
+			}
+		}
+	}
+}
+
+func TestRoundingMode(t *testing.T) {
+	cases := []struct {
+		mode          string
+		expectedLine  string
+		expectedTotal string
+	}{
+		{mode: RoundingModeNone, expectedLine: "0.15", expectedTotal: "0.45"},
+		{mode: RoundingModeHalfUp, expectedLine: "0.16", expectedTotal: "0.48"},
+		{mode: RoundingModeHalfEven, expectedLine: "0.14", expectedTotal: "0.42"},
+		{mode: RoundingModeTruncate, expectedLine: "0.14", expectedTotal: "0.42"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{RoundingMode: c.mode})
+		for i := 0; i < 3; i++ {
+			doc.AppendItem(&Item{Name: "Test", UnitCost: "0.125", Quantity: "1", Tax: &Tax{Percent: "20"}})
+		}
+
+		if err := doc.Validate(); err != nil {
+			t.Fatalf("got error %v", err)
+		}
+
+		linesTotal := decimal.Zero
+		for _, item := range doc.Items {
+			line := item.TotalWithTaxAndDiscount()
+			if line.String() != c.expectedLine {
+				t.Errorf("mode %q: expected line total %s, got %s", c.mode, c.expectedLine, line)
+			}
+			linesTotal = linesTotal.Add(line)
+		}
+
+		total := doc.TotalWithTax()
+		if total.String() != c.expectedTotal || !total.Equal(linesTotal) {
+			t.Errorf("mode %q: expected total %s equal to lines total %s, got %s", c.mode, c.expectedTotal, linesTotal, total)
+		}
+	}
+
+	doc := newTestDocument(t, &Options{RoundingMode: "invalid"})
+	if err := doc.Validate(); err == nil {
+		t.Errorf("expected error on invalid rounding mode")
+	}
+}
+
+func TestTaxSummary(t *testing.T) {
+	doc := newTestDocument(t, &Options{ShowTaxSummary: true})
+	doc.AppendItem(&Item{Name: "A", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.AppendItem(&Item{Name: "B", UnitCost: "50", Quantity: "2", Tax: &Tax{Percent: "10"}})
+	doc.AppendItem(&Item{Name: "C", UnitCost: "30", Quantity: "1", Tax: &Tax{Percent: "0"}})
+	doc.AppendItem(&Item{Name: "D", UnitCost: "20", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "E", UnitCost: "400", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.AppendItem(&Item{Name: "F", UnitCost: "10", Quantity: "1", Tax: &Tax{Amount: "3"}})
+
+	out := buildToString(t, doc)
+
+	expected := []struct {
+		taxType string
+		percent string
+		base    string
+		tax     string
+	}{
+		{taxType: TaxTypePercent, percent: "20", base: "500", tax: "100"},
+		{taxType: TaxTypePercent, percent: "10", base: "100", tax: "10"},
+		{taxType: TaxTypePercent, percent: "0", base: "50", tax: "0"},
+		{taxType: TaxTypeAmount, percent: "0", base: "10", tax: "3"},
+	}
+
+	lines := doc.TaxSummary()
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d", len(expected), len(lines))
+	}
+
+	taxTotal := decimal.Zero
+	for n, e := range expected {
+		l := lines[n]
+		if l.Type != e.taxType || l.Percent.String() != e.percent || l.Base.String() != e.base || l.Tax.String() != e.tax {
+			t.Errorf("line %d: expected %+v, got %s %s %s %s", n, e, l.Type, l.Percent, l.Base, l.Tax)
+		}
+		taxTotal = taxTotal.Add(l.Tax)
+	}
+
+	if !taxTotal.Equal(doc.Tax()) {
+		t.Errorf("expected summary tax %s to equal document tax %s", taxTotal, doc.Tax())
+	}
+
+	for _, expected := range []string{"(Tax rate)", "(20 %)", "(\x80 600.00)"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output", expected)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	newDoc := func() *Document {
+		doc := newTestDocument(t, &Options{})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+		return doc
+	}
+
+	// Buffer
+	buf := &bytes.Buffer{}
+	if err := newDoc().Write(buf); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Errorf("expected pdf header in buffer")
+	}
+
+	// File
+	f, err := os.CreateTemp(t.TempDir(), "*.pdf")
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	defer f.Close()
+
+	if err := newDoc().Write(f); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	content, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if !bytes.HasPrefix(content, []byte("%PDF-")) {
+		t.Errorf("expected pdf header in file")
+	}
+}
+
+func TestColumnOffsets(t *testing.T) {
+	doc := newTestDocument(t, &Options{
+		ColumnOffsets: ColumnOffsets{
+			Name:         10,
+			HTPrice:      60,
+			PriceInclVAT: 80,
+			Qty:          95,
+			Discount:     105,
+			Tax:          120,
+			TotalTTC:     135,
+			End:          150,
+		},
+	})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1", Tax: &Tax{Percent: "20"}, Discount: &Discount{Amount: "1"}})
+
+	out := buildToString(t, doc)
+
+	// Every text position stays inside the page
+	pageWidth, _ := doc.pdf.GetPageSize()
+	maxX := pageWidth * 72 / 25.4
+	positions := regexp.MustCompile(`BT ([0-9.]+) [0-9.]+ Td`).FindAllStringSubmatch(out, -1)
+	if len(positions) == 0 {
+		t.Fatalf("expected text positions in output")
+	}
+
+	for _, pos := range positions {
+		x, _ := strconv.ParseFloat(pos[1], 64)
+		if x >= maxX {
+			t.Errorf("text at x %v overflows page width %v", x, maxX)
+		}
+	}
+
+	// Invalid offsets
+	doc = newTestDocument(t, &Options{ColumnOffsets: ColumnOffsets{End: 300}})
+	if _, err := doc.Build(); !errors.Is(err, ErrInvalidColumnOffsets) {
+		t.Errorf("expected ErrInvalidColumnOffsets, got %v", err)
+	}
+}
+
+func TestItemRef(t *testing.T) {
+	for _, show := range []bool{false, true} {
+		doc := newTestDocument(t, &Options{ShowItemRef: show})
+		doc.AppendItem(&Item{Ref: "SKU-0042", Name: "Test", UnitCost: "10", Quantity: "1"})
+		doc.AppendItem(&Item{Name: "Without ref", UnitCost: "10", Quantity: "1"})
+
+		out := buildToString(t, doc)
+
+		for _, expected := range []string{"(SKU-0042)", "(Ref.)"} {
+			if strings.Contains(out, expected) != show {
+				t.Errorf("show %v: unexpected rendering of %q", show, expected)
+			}
+		}
+	}
+}
+
+func TestCurrency(t *testing.T) {
+	cases := []struct {
+		currency Currency
+		expected string
+	}{
+		{currency: CurrencyUSD, expected: "($1,234.56)"},
+		{currency: CurrencyEUR, expected: "(1 234,56 \x80)"},
+		{currency: CurrencyCHF, expected: "(CHF 1'234.56)"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{})
+		doc.SetCurrency(c.currency)
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "1234.56", Quantity: "1"})
+
+		out := buildToString(t, doc)
+
+		// Unit price, item total and totals
+		if count := strings.Count(out, c.expected); count < 4 {
+			t.Errorf("%s: expected %q at least 4 times, got %d", c.currency.Code, c.expected, count)
+		}
+	}
+}
+
+func TestDocumentTypes(t *testing.T) {
+	newDoc := func(docType string, options *Options) *Document {
+		doc := newTestDocument(t, options)
+		doc.SetType(docType)
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+		return doc
+	}
+
+	// Titles
+	for docType, title := range map[string]string{CreditNote: "(CREDIT NOTE)", ProForma: "(PRO FORMA INVOICE)"} {
+		if out := buildToString(t, newDoc(docType, &Options{})); !strings.Contains(out, title) {
+			t.Errorf("%s: expected title %q", docType, title)
+		}
+	}
+
+	// Delivery note without prices
+	out := buildToString(t, newDoc(DeliveryNote, &Options{HideDeliveryNotePrices: true}))
+	for _, unexpected := range []string{"(Unit price)", "(Total)", "(\x80 100.00)", "(TOTAL WITH TAX)"} {
+		if strings.Contains(out, unexpected) {
+			t.Errorf("delivery note: unexpected %q in output", unexpected)
+		}
+	}
+
+	// Credit note with negative totals
+	out = buildToString(t, newDoc(CreditNote, &Options{}))
+	for _, expected := range []string{"(-\x80 120.00)", "(-\x80 20.00)", "(-\x80 100.00)"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("credit note: expected %q in output", expected)
+		}
+	}
+}
+
+func TestTerms(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+	paragraph := strings.Repeat("Payment is due within 30 days, late payments bear interest. ", 20)
+	doc.SetTerms(strings.Repeat(paragraph+"\n", 12) + "Thank you for your business")
+
+	out := buildToString(t, doc)
+
+	if pages := doc.pdf.PageCount(); pages < 2 {
+		t.Fatalf("expected long terms to add a page, got %d page", pages)
+	}
+
+	if !strings.Contains(out, "(Thank you for your business)") {
+		t.Errorf("expected last paragraph in output")
+	}
+}
+
+func TestLogo(t *testing.T) {
+	logoBytes, err := os.ReadFile("./example_logo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := newTestDocument(t, &Options{Logo: &Logo{Bytes: logoBytes, MaxWidth: 40}})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+	if out := buildToString(t, doc); !strings.Contains(out, "/Subtype /Image") {
+		t.Errorf("expected logo image in output")
+	}
+
+	doc = newTestDocument(t, &Options{Logo: &Logo{Bytes: []byte("not an image")}})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+	if _, err := doc.Build(); !errors.Is(err, ErrInvalidLogo) {
+		t.Errorf("expected ErrInvalidLogo, got %v", err)
+	}
+
+	doc = newTestDocument(t, &Options{Logo: &Logo{Path: "./missing_logo.png"}})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+	if _, err := doc.Build(); !errors.Is(err, ErrInvalidLogo) {
+		t.Errorf("expected ErrInvalidLogo for missing file, got %v", err)
+	}
+}
+
+func TestEPCPayment(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.SetEPCPayment(&EPCPayment{
+		BeneficiaryName: "Red Cross",
+		IBAN:            "BE72 0000 0001 6116",
+		BIC:             "bpotbeb1",
+		Remittance:      "Invoice ref",
+	})
+
+	out := buildToString(t, doc)
+
+	payload, err := doc.EPCPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "BCD\n002\n1\nSCT\nBPOTBEB1\nRed Cross\nBE72000000016116\nEUR120.00\n\n\nInvoice ref"
+	if payload != expected {
+		t.Errorf("expected payload %q, got %q", expected, payload)
+	}
+
+	if !strings.Contains(out, "/Subtype /Image") {
+		t.Errorf("expected qr code image in output")
+	}
+
+	doc.EPCPayment.Reference = "RF18539007547034"
+	if _, err := doc.EPCPayload(); !errors.Is(err, ErrEPCReferenceAndRemittance) {
+		t.Errorf("expected ErrEPCReferenceAndRemittance, got %v", err)
+	}
+
+	doc.EPCPayment.Remittance = ""
+	doc.Options.CurrencyCode = "USD"
+	if _, err := doc.EPCPayload(); !errors.Is(err, ErrEPCInvalidCurrency) {
+		t.Errorf("expected ErrEPCInvalidCurrency, got %v", err)
+	}
+}
+
+func TestSwissQRBill(t *testing.T) {
+	if digit := qrReferenceCheckDigit("21000000000313947143000901"); digit != 7 {
+		t.Errorf("expected check digit 7, got %d", digit)
+	}
+
+	doc := newTestDocument(t, &Options{})
+	doc.SetCurrency(CurrencyCHF)
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "1949.75", Quantity: "1"})
+	doc.SetSwissQRBill(&SwissQRBill{
+		Account: "CH44 3199 9123 0008 8901 2",
+		Creditor: &SwissQRBillAddress{
+			Name:           "Robert Schneider AG",
+			Street:         "Rue du Lac",
+			BuildingNumber: "1268",
+			PostalCode:     "2501",
+			Town:           "Biel",
+			Country:        "CH",
+		},
+		Reference:      "21 00000 00003 13947 14300 09017",
+		AdditionalInfo: "Order of 15 June 2020",
+	})
+
+	out := buildToString(t, doc)
+
+	payload, err := doc.SwissQRBillPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"SPC", "0200", "1", "CH4431999123000889012",
+		"S", "Robert Schneider AG", "Rue du Lac", "1268", "2501", "Biel", "CH",
+		"", "", "", "", "", "", "",
+		"1949.75", "CHF",
+		"", "", "", "", "", "", "",
+		"QRR", "210000000003139471430009017",
+		"Order of 15 June 2020",
+		"EPD",
+	}, "\n")
+	if payload != expected {
+		t.Errorf("expected payload %q, got %q", expected, payload)
+	}
+
+	for _, text := range []string{"(Receipt)", "(Payment part)", "(21 00000 00003 13947 14300 09017)", "(1 949.75)"} {
+		if !strings.Contains(out, text) {
+			t.Errorf("expected %q in output", text)
+		}
+	}
+
+	// Invalid QR reference checksum
+	doc.SwissQRBill.Reference = "210000000003139471430009018"
+	if _, err := doc.SwissQRBillPayload(); !errors.Is(err, ErrSwissQRBillInvalidReference) {
+		t.Errorf("expected ErrSwissQRBillInvalidReference, got %v", err)
+	}
+
+	// Creditor reference with a regular IBAN
+	doc.SwissQRBill.Account = "CH93 0076 2011 6238 5295 7"
+	doc.SwissQRBill.Reference = "RF18 5390 0754 7034"
+	if _, err := doc.SwissQRBillPayload(); err != nil {
+		t.Errorf("expected valid creditor reference, got %v", err)
+	}
+
+	doc.SwissQRBill.Reference = "RF19 5390 0754 7034"
+	if _, err := doc.SwissQRBillPayload(); !errors.Is(err, ErrSwissQRBillInvalidReference) {
+		t.Errorf("expected ErrSwissQRBillInvalidReference, got %v", err)
+	}
+}
+
+func TestPrepareErrors(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "Widget", UnitCost: "12,50", Quantity: "1"})
+
+	_, err := doc.Build()
+	if !errors.Is(err, ErrInvalidDecimal) {
+		t.Fatalf("expected ErrInvalidDecimal, got %v", err)
+	}
+	if expected := `item 1 "Widget": field unit_cost: invalid decimal "12,50"`; err.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, err.Error())
+	}
+
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Widget", UnitCost: "10", Quantity: "1", Tax: &Tax{Percent: "20%"}})
+
+	_, err = doc.Build()
+	if expected := `item 0 "Widget": tax: field percent: invalid decimal "20%"`; err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+
+	doc.Items[0].Tax = nil
+	doc.SetDiscount(&Discount{Amount: "ten"})
+
+	_, err = doc.Build()
+	if expected := `discount: field amount: invalid decimal "ten"`; err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestItemJSON(t *testing.T) {
+	item := &Item{}
+	if err := json.Unmarshal([]byte(`{"name":"Widget","unit_cost":"12.50","quantity":"3"}`), item); err != nil {
+		t.Fatal(err)
+	}
+
+	if item.UnitCost != "12.50" || item.Quantity != "3" {
+		t.Fatalf("expected unit cost 12.50 and quantity 3, got %s and %s", item.UnitCost, item.Quantity)
+	}
+
+	if total := item.TotalWithoutTaxAndWithoutDiscount(); !total.Equal(decimal.RequireFromString("37.5")) {
+		t.Errorf("expected total 37.5, got %s", total)
+	}
+
+	out, err := json.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `{"name":"Widget","unit_cost":"12.50","quantity":"3"}`; string(out) != expected {
+		t.Errorf("expected %s, got %s", expected, out)
+	}
+}
+
+func TestDueDate(t *testing.T) {
+	doc := newTestDocument(t, &Options{DateLayout: "2006-01-02"})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.SetDate("2021-03-01").SetDueDate("2021-03-31")
+
+	now := time.Date(2021, 3, 31, 18, 30, 0, 0, time.UTC)
+	if doc.IsOverdue(now) {
+		t.Errorf("expected invoice due today not to be overdue")
+	}
+	if !doc.IsOverdue(now.AddDate(0, 0, 1)) {
+		t.Errorf("expected invoice due yesterday to be overdue")
+	}
+	if doc.SetPaid(true).IsOverdue(now.AddDate(0, 0, 1)) {
+		t.Errorf("expected paid invoice not to be overdue")
+	}
+
+	doc.SetPaid(false)
+	out := buildToString(t, doc)
+	for _, expected := range []string{"(Due date: 2021-03-31)", "(Payment term: Net 30)", "(OVERDUE)"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output", expected)
+		}
+	}
+
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.SetDueDate("2021-03-31")
+
+	if _, err := doc.Build(); !errors.Is(err, ErrInvalidDueDate) {
+		t.Errorf("expected ErrInvalidDueDate, got %v", err)
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	for language, titles := range map[string][]string{
+		LanguageFrench: {"(FACTURE)", "(D\xe9signation)", "(Prix unitaire)", "(Qt\xe9)", "(Remise)", "(Total TTC)"},
+		LanguageGerman: {"(RECHNUNG)", "(Bezeichnung)", "(Einzelpreis)", "(Menge)", "(Rabatt)", "(Gesamt)"},
+	} {
+		doc := newTestDocument(t, &Options{Language: language, TextItemsTaxTitle: "Custom tax"})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+		out := buildToString(t, doc)
+		for _, title := range append(titles, "(Custom tax)") {
+			if !strings.Contains(out, title) {
+				t.Errorf("%s: expected %q in output", language, title)
+			}
+		}
+	}
+
+	// Missing translations fallback to english
+	doc := newTestDocument(t, &Options{Language: LanguageSpanish})
+	if doc.Options.TextSwissQRBillReceiptTitle != "Receipt" {
+		t.Errorf("expected english fallback, got %q", doc.Options.TextSwissQRBillReceiptTitle)
+	}
+}
+
+func TestAmountPaid(t *testing.T) {
+	newDoc := func(amountPaid string) *Document {
+		doc := newTestDocument(t, &Options{})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+		doc.SetAmountPaid(amountPaid)
+		return doc
+	}
+
+	for _, tc := range []struct {
+		amountPaid string
+		balance    string
+		status     string
+		stamp      string
+	}{
+		{"120", "0", StatusPaid, "(PAID)"},
+		{"50", "70", StatusPartiallyPaid, "(PARTIALLY PAID)"},
+		{"0", "120", StatusUnpaid, "(UNPAID)"},
+	} {
+		doc := newDoc(tc.amountPaid)
+		out := buildToString(t, doc)
+
+		if balance := doc.BalanceDue(); !balance.Equal(decimal.RequireFromString(tc.balance)) {
+			t.Errorf("amount paid %s: expected balance %s, got %s", tc.amountPaid, tc.balance, balance)
+		}
+		if status := doc.Status(); status != tc.status {
+			t.Errorf("amount paid %s: expected status %s, got %s", tc.amountPaid, tc.status, status)
+		}
+		for _, expected := range []string{tc.stamp, "(BALANCE DUE)"} {
+			if !strings.Contains(out, expected) {
+				t.Errorf("amount paid %s: expected %q in output", tc.amountPaid, expected)
+			}
+		}
+	}
+
+	// Without amount paid, no status is rendered
+	if out := buildToString(t, newDoc("")); strings.Contains(out, "(UNPAID)") || strings.Contains(out, "(BALANCE DUE)") {
+		t.Errorf("expected no status without amount paid")
+	}
+}
+
+// utf16BE returns text as written by fpdf for UTF-8 fonts and attachments names
+func utf16BE(text string) string {
+	var utf16 strings.Builder
+	for _, r := range text {
+		utf16.WriteByte(byte(r >> 8))
+		utf16.WriteByte(byte(r))
+	}
+
+	return utf16.String()
+}
+
+func TestUTF8Font(t *testing.T) {
+	doc := newTestDocument(t, &Options{FontFile: "./testdata/DejaVuSansCondensed.ttf"})
+	doc.SetNotes("Zażółć gęślą jaźń")
+	doc.SetCustomer(&Contact{Name: "Иван Петров"})
+	doc.AppendItem(&Item{Name: "Zażółć gęślą jaźń", UnitCost: "10", Quantity: "1"})
+
+	out := buildToString(t, doc)
+
+	// UTF-8 fonts texts are written as UTF-16BE
+	for _, text := range []string{"Zażółć gęślą jaźń", "Иван Петров"} {
+		if !strings.Contains(out, "("+utf16BE(text)+")") {
+			t.Errorf("expected %q in output", text)
+		}
+	}
+
+	doc = newTestDocument(t, &Options{FontFile: "./testdata/missing.ttf"})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+	if _, err := doc.Build(); err == nil {
+		t.Errorf("expected error on missing font file")
+	}
+}
+
+func TestRTL(t *testing.T) {
+	// textX returns the x of text in pdf content
+	textX := func(t *testing.T, out string, text string) float64 {
+		t.Helper()
+
+		match := regexp.MustCompile(`BT ([\d.]+) [\d.]+ Td \(` + regexp.QuoteMeta(text) + `\)Tj`).FindStringSubmatch(out)
+		if match == nil {
+			t.Fatalf("text %q not found", text)
+		}
+
+		x, _ := strconv.ParseFloat(match[1], 64)
+		return x
+	}
+
+	for _, rtl := range []bool{false, true} {
+		doc := newTestDocument(t, &Options{RTL: rtl})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+		out := buildToString(t, doc)
+		nameX, unitCostX, totalX := textX(t, out, "Name"), textX(t, out, "Unit price"), textX(t, out, "Total")
+
+		if ltr := nameX < unitCostX && unitCostX < totalX; ltr == rtl {
+			t.Errorf("rtl %v: unexpected columns order, name %.2f, unit price %.2f, total %.2f", rtl, nameX, unitCostX, totalX)
+		}
+	}
+}
+
+func TestItemDiscountDisplay(t *testing.T) {
+	newDoc := func(discount *Discount) *Document {
+		doc := newTestDocument(t, &Options{})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "25", Quantity: "2", Discount: discount})
+		return doc
+	}
+
+	// Percent discount shows the percent and the discounted amount
+	out := buildToString(t, newDoc(&Discount{Percent: "10"}))
+	for _, expected := range []string{"(- 10 %)", "(- \x80 5.00)"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("percent discount: expected %q in output", expected)
+		}
+	}
+
+	// Amount discount shows only the amount
+	out = buildToString(t, newDoc(&Discount{Amount: "5"}))
+	if !strings.Contains(out, "(- \x80 5.00)") {
+		t.Errorf("amount discount: expected amount in output")
+	}
+	if strings.Contains(out, " %)") {
+		t.Errorf("amount discount: unexpected percent in output")
+	}
+}
+
+func TestShipping(t *testing.T) {
+	cases := []struct {
+		shipping        *Shipping
+		expectedTotal   string
+		expectedTax     string
+		expectedWithTax string
+		expectedLine    bool
+	}{
+		// Items: 100 at 20% with 10% document discount (90), shipping is not discounted
+		{shipping: nil, expectedTotal: "90", expectedTax: "18", expectedWithTax: "108"},
+		{shipping: &Shipping{Amount: "0"}, expectedTotal: "90", expectedTax: "18", expectedWithTax: "108"},
+		{shipping: &Shipping{Amount: "10"}, expectedTotal: "100", expectedTax: "18", expectedWithTax: "118", expectedLine: true},
+		{shipping: &Shipping{Amount: "10", Tax: &Tax{Percent: "5.5"}}, expectedTotal: "100", expectedTax: "18.55", expectedWithTax: "118.55", expectedLine: true},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+		doc.SetDiscount(&Discount{Percent: "10"})
+		doc.SetShipping(c.shipping)
+
+		out := buildToString(t, doc)
+
+		if total := doc.TotalWithoutTax(); total.String() != c.expectedTotal {
+			t.Errorf("expected total without tax %s, got %s", c.expectedTotal, total)
+		}
+
+		if tax := doc.Tax(); tax.String() != c.expectedTax {
+			t.Errorf("expected tax %s, got %s", c.expectedTax, tax)
+		}
+
+		if total := doc.TotalWithTax(); total.String() != c.expectedWithTax {
+			t.Errorf("expected total with tax %s, got %s", c.expectedWithTax, total)
+		}
+
+		if strings.Contains(out, "(SHIPPING)") != c.expectedLine {
+			t.Errorf("expected shipping line %t", c.expectedLine)
+		}
+
+		// Discounted total excludes shipping
+		if !strings.Contains(out, "(\x80 90.00)") {
+			t.Errorf("expected discounted total in output")
+		}
+
+		if _, err := doc.MarshalFacturX(); err != nil {
+			t.Errorf("got error %v", err)
+		}
+	}
+
+	// Shipping is grouped with items of the same rate in the tax summary
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.SetShipping(&Shipping{Amount: "10", Tax: &Tax{Percent: "20"}})
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if lines := doc.TaxSummary(); len(lines) != 1 || lines[0].Base.String() != "110" || lines[0].Tax.String() != "22" {
+		t.Errorf("unexpected tax summary %+v", lines)
+	}
+}
+
+func TestAttachments(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.Attach("timesheet.csv", []byte("day,hours\n1,8\n"), "text/csv")
+	doc.Attach("contract.txt", []byte("contract terms"), "text/plain")
+
+	out := buildToString(t, doc)
+
+	for _, expected := range []string{
+		"/EmbeddedFiles",
+		utf16BE("timesheet.csv"),
+		utf16BE("contract.txt"),
+		utf16BE("text/csv"),
+		"day,hours",
+		"contract terms",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output", expected)
+		}
+	}
+
+	// Factur-X keeps the document attachments
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.Attach("contract.txt", []byte("contract terms"), "text/plain")
+	doc.pdf.SetCompression(false)
+
+	pdf, err := doc.BuildFacturX()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := pdf.Output(buf); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if !strings.Contains(buf.String(), utf16BE("contract.txt")) || !strings.Contains(buf.String(), utf16BE(FacturXFileName)) {
+		t.Errorf("expected document and factur-x attachments in output")
+	}
+
+	// Attachments must have a name
+	doc = newTestDocument(t, &Options{})
+	doc.Attach("", []byte("data"), "")
+	if err := doc.Validate(); err == nil {
+		t.Errorf("expected validation error")
+	}
+}
+
+func TestWatermark(t *testing.T) {
+	for _, header := range []*HeaderFooter{nil, {Text: "Header"}} {
+		doc := newTestDocument(t, &Options{Watermark: "DRAFT"})
+		doc.SetHeader(header)
+
+		for i := 0; i < 30; i++ {
+			doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+		}
+
+		out := buildToString(t, doc)
+
+		if pages := doc.pdf.PageCount(); pages != 2 {
+			t.Fatalf("expected 2 pages, got %d", pages)
+		}
+
+		// Watermark is drawn on every page, before the page content
+		if count := strings.Count(out, "(DRAFT) Tj"); count != 2 {
+			t.Errorf("expected watermark 2 times, got %d", count)
+		}
+		if strings.Index(out, "(DRAFT) Tj") > strings.Index(out, "("+doc.Options.TextItemsUnitCostTitle+")") {
+			t.Errorf("expected watermark behind page content")
+		}
+		if !strings.Contains(out, "/ca 0.3") {
+			t.Errorf("expected watermark opacity in output")
+		}
+	}
+
+	doc := newTestDocument(t, &Options{Watermark: "DRAFT", WatermarkOpacity: 2})
+	if err := doc.Validate(); err == nil {
+		t.Errorf("expected validation error on opacity")
+	}
+
+	// A zero opacity is kept when set after the defaults
+	doc = newTestDocument(t, &Options{Watermark: "DRAFT"})
+	doc.SetWatermarkOpacity(0)
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	if out := buildToString(t, doc); !strings.Contains(out, "/ca 0.000") {
+		t.Errorf("expected transparent watermark in output")
+	}
+}
+
+func TestPageNumbers(t *testing.T) {
+	cases := []struct {
+		options  *Options
+		footer   *HeaderFooter
+		expected string
+	}{
+		{options: &Options{ShowPageNumbers: true}, expected: "Page %d of 3"},
+		{options: &Options{ShowPageNumbers: true, Language: LanguageFrench}, expected: "Page %d sur 3"},
+		{options: &Options{}, footer: &HeaderFooter{Text: "Footer", Pagination: true}, expected: "Page %d of 3"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, c.options)
+		doc.SetFooter(c.footer)
+
+		for i := 0; i < 60; i++ {
+			doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+		}
+
+		out := buildToString(t, doc)
+
+		if pages := doc.pdf.PageCount(); pages != 3 {
+			t.Fatalf("expected 3 pages, got %d", pages)
+		}
+
+		// Total pages alias is replaced on output
+		for page := 1; page <= 3; page++ {
+			if expected := fmt.Sprintf(c.expected, page); !strings.Contains(out, "("+expected+")") {
+				t.Errorf("expected %q in output", expected)
+			}
+		}
+		if strings.Contains(out, "{nb}") {
+			t.Errorf("unexpected total pages alias in output")
+		}
+	}
+}
+
+func TestItemColumns(t *testing.T) {
+	doc := newTestDocument(t, &Options{
+		ItemColumns: []*ItemColumn{
+			{Key: ItemColumnName},
+			{Key: "project", Title: "Project code", Width: 30},
+			{Key: "hours", Title: "Hours", Width: 20, Value: func(item *Item) string { return item.Quantity + " h" }},
+			{Key: ItemColumnTotal, Width: 30, Align: "R"},
+		},
+	})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "12", Fields: map[string]string{"project": "PRJ-7"}})
+
+	out := buildToString(t, doc)
+
+	// Custom cells are aligned with their header
+	positionX := func(text string) string {
+		match := regexp.MustCompile(`BT ([0-9.]+) [0-9.]+ Td \(` + regexp.QuoteMeta(text) + `\)Tj`).FindStringSubmatch(out)
+		if match == nil {
+			t.Fatalf("expected %q in output", text)
+		}
+		return match[1]
+	}
+
+	if header, cell := positionX("Project code"), positionX("PRJ-7"); header != cell {
+		t.Errorf("expected project cell at header x %s, got %s", header, cell)
+	}
+	if header, cell := positionX("Hours"), positionX("12 h"); header != cell {
+		t.Errorf("expected hours cell at header x %s, got %s", header, cell)
+	}
+
+	// Columns are laid out after the name column, the last one ends at ColumnOffsets.End
+	if x := positionX("Project code"); x != strconv.FormatFloat((190-80+1)*72/25.4, 'f', 2, 64) {
+		t.Errorf("unexpected project column x %s", x)
+	}
+
+	// Built-in columns not listed are not rendered
+	if strings.Contains(out, "("+doc.Options.TextItemsUnitCostTitle+")") {
+		t.Errorf("unexpected unit cost column")
+	}
+
+	// Invalid columns
+	doc = newTestDocument(t, &Options{ItemColumns: []*ItemColumn{{Key: "hours", Width: 20}}})
+	if err := doc.Validate(); !errors.Is(err, ErrInvalidItemColumns) {
+		t.Errorf("expected ErrInvalidItemColumns, got %v", err)
+	}
+}
+
+func TestSortItems(t *testing.T) {
+	cases := []struct {
+		sortBy   string
+		expected []string
+	}{
+		{sortBy: SortItemsByInsertion, expected: []string{"Charlie", "Alpha", "Bravo", "Delta"}},
+		{sortBy: SortItemsByOrder, expected: []string{"Bravo", "Delta", "Charlie", "Alpha"}},
+		{sortBy: SortItemsByName, expected: []string{"Alpha", "Bravo", "Charlie", "Delta"}},
+		{sortBy: SortItemsByRef, expected: []string{"Delta", "Charlie", "Bravo", "Alpha"}},
+		{sortBy: SortItemsByTotal, expected: []string{"Alpha", "Bravo", "Delta", "Charlie"}},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{SortItemsBy: c.sortBy})
+		doc.AppendItem(&Item{Name: "Charlie", Ref: "B", Order: 3, UnitCost: "30", Quantity: "1"})
+		doc.AppendItem(&Item{Name: "Alpha", Ref: "C", Order: 4, UnitCost: "10", Quantity: "1"})
+		doc.AppendItem(&Item{Name: "Bravo", Ref: "B", Order: 1, UnitCost: "20", Quantity: "1"})
+		doc.AppendItem(&Item{Name: "Delta", Ref: "A", Order: 2, UnitCost: "20", Quantity: "1"})
+
+		out := buildToString(t, doc)
+
+		last := -1
+		for _, name := range c.expected {
+			index := strings.Index(out, "("+name+")")
+			if index < last {
+				t.Errorf("sort by %q: expected %v order, %s is misplaced", c.sortBy, c.expected, name)
+			}
+			last = index
+		}
+	}
+
+	doc := newTestDocument(t, &Options{SortItemsBy: "price"})
+	if err := doc.Validate(); err == nil {
+		t.Errorf("expected validation error on sort mode")
+	}
+}
+
+func TestReturnLines(t *testing.T) {
+	cases := []struct {
+		items           []*Item
+		expectedTotal   string
+		expectedTax     string
+		expectedWithTax string
+	}{
+		{
+			items: []*Item{
+				{Name: "Sold", UnitCost: "100", Quantity: "2", Tax: &Tax{Percent: "20"}},
+				{Name: "Returned", UnitCost: "50", Quantity: "-1", Tax: &Tax{Percent: "20"}, Discount: &Discount{Amount: "5"}},
+				{Name: "Returned fee", UnitCost: "10", Quantity: "-1", Tax: &Tax{Amount: "2"}},
+			},
+			expectedTotal: "145", expectedTax: "29", expectedWithTax: "174",
+		},
+		{
+			items: []*Item{
+				{Name: "Sold", UnitCost: "10", Quantity: "1", Tax: &Tax{Percent: "20"}},
+				{Name: "Returned", UnitCost: "20", Quantity: "-3", Tax: &Tax{Percent: "20"}, Discount: &Discount{Percent: "10"}},
+			},
+			expectedTotal: "-44", expectedTax: "-8.8", expectedWithTax: "-52.8",
+		},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{})
+		for _, item := range c.items {
+			doc.AppendItem(item)
+		}
+
+		out := buildToString(t, doc)
+
+		if total := doc.TotalWithoutTax(); total.String() != c.expectedTotal {
+			t.Errorf("expected total without tax %s, got %s", c.expectedTotal, total)
+		}
+
+		if tax := doc.Tax(); tax.String() != c.expectedTax {
+			t.Errorf("expected tax %s, got %s", c.expectedTax, tax)
+		}
+
+		if total := doc.TotalWithTax(); total.String() != c.expectedWithTax {
+			t.Errorf("expected total with tax %s, got %s", c.expectedWithTax, total)
+		}
+
+		// Return lines totals are rendered with a leading minus, in NegativeTextColor
+		if !regexp.MustCompile(`0\.753 0\.000 0\.000 rg BT [0-9. ]+ Td \(-. [0-9.]+\)Tj`).MatchString(out) {
+			t.Errorf("expected negative amounts in color")
+		}
+	}
+}
+
+func TestTotals(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "2", Tax: &Tax{Percent: "20"}, Discount: &Discount{Percent: "10"}})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "20", Quantity: "1", Tax: &Tax{Percent: "10"}})
+	doc.SetDiscount(&Discount{Amount: "20"})
+	doc.SetShipping(&Shipping{Amount: "10", Tax: &Tax{Percent: "20"}})
+	doc.SetWithholdingTax(&Tax{Percent: "10"})
+	doc.SetAmountPaid("100")
+
+	totals, err := doc.Totals()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	// Items: 180 at 20% and 20 at 10%, document discount 10 % on each, shipping 10 at 20%
+	for name, c := range map[string]struct {
+		value    decimal.Decimal
+		expected string
+	}{
+		"subtotal":          {totals.Subtotal, "200"},
+		"items discount":    {totals.ItemsDiscount, "20"},
+		"discount":          {totals.Discount, "20"},
+		"shipping":          {totals.Shipping, "10"},
+		"total without tax": {totals.TotalWithoutTax, "190"},
+		"tax":               {totals.Tax, "36.2"},
+		"total with tax":    {totals.TotalWithTax, "226.2"},
+		"withholding":       {totals.Withholding, "19"},
+		"net payable":       {totals.NetPayable, "207.2"},
+		"amount paid":       {totals.AmountPaid, "100"},
+		"balance due":       {totals.BalanceDue, "107.2"},
+	} {
+		if c.value.String() != c.expected {
+			t.Errorf("expected %s %s, got %s", name, c.expected, c.value)
+		}
+	}
+
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "ten", Quantity: "1"})
+	if _, err := doc.Totals(); !errors.Is(err, ErrInvalidDecimal) {
+		t.Errorf("expected ErrInvalidDecimal, got %v", err)
+	}
+}
+
+func TestPaymentInfo(t *testing.T) {
+	// IBAN formatting
+	for iban, expected := range map[string]string{
+		"FR7630006000011234567890189":    "FR76 3000 6000 0112 3456 7890 189",
+		"de89 3704 0044 0532 0130 00":    "DE89 3704 0044 0532 0130 00",
+		"GB82WEST12345698765432":         "GB82 WEST 1234 5698 7654 32",
+		"CH93 0076 2011 6238 5295 7    ": "CH93 0076 2011 6238 5295 7",
+	} {
+		if formatted := FormatIBAN(iban); formatted != expected {
+			t.Errorf("expected %q, got %q", expected, formatted)
+		}
+	}
+
+	// IBAN checksum
+	for iban, valid := range map[string]bool{
+		"FR76 3000 6000 0112 3456 7890 189": true,
+		"GB82 WEST 1234 5698 7654 32":       true,
+		"FR77 3000 6000 0112 3456 7890 189": false,
+		"GB82 WEST 1234 5698 7654 33":       false,
+		"1234 5678 9012 3456":               false,
+		"FR76":                              false,
+	} {
+		if ValidIBAN(iban) != valid {
+			t.Errorf("%s: expected valid %t", iban, valid)
+		}
+	}
+
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.SetPaymentInfo(&PaymentInfo{
+		AccountHolder: "Test Company",
+		BankName:      "Test Bank",
+		IBAN:          "fr7630006000011234567890189",
+		BIC:           "agrifrpp",
+		Reference:     "INV-42",
+		ValidateIBAN:  true,
+	})
+
+	out := buildToString(t, doc)
+	for _, expected := range []string{"(Payment details)", "(FR76 3000 6000 0112 3456 7890 189)", "(AGRIFRPP)", "(INV-42)", "(Test Bank)"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output", expected)
+		}
+	}
+
+	// Invalid checksum only fails when validation is enabled
+	doc.PaymentInfo.IBAN = "FR77 3000 6000 0112 3456 7890 189"
+	if err := doc.Validate(); !errors.Is(err, ErrInvalidIBAN) {
+		t.Errorf("expected ErrInvalidIBAN, got %v", err)
+	}
+
+	doc.PaymentInfo.ValidateIBAN = false
+	if err := doc.Validate(); err != nil {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestContactDetails(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.SetCompany(&Contact{
+		Name:               "Test Company",
+		Address:            &Address{Address: "1 rue de la Paix", Address2: "Bât. B", PostalCode: "75002", City: "Paris", Country: "France"},
+		VATNumber:          "FR40303265045",
+		RegistrationNumber: "303 265 045",
+		Email:              "billing@example.com",
+		Phone:              "+33 1 23 45 67 89",
+	})
+	doc.SetCustomer(&Contact{
+		Name:      "Test Customer",
+		Address:   &Address{Address: "Hauptstraße 1", City: "Berlin"},
+		VATNumber: "DE123456789",
+	})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+	out := buildToString(t, doc)
+
+	for _, expected := range []string{
+		"(VAT number: FR40303265045)",
+		"(Registration number: 303 265 045)",
+		"(Email: billing@example.com)",
+		"(Phone: +33 1 23 45 67 89)",
+		"(VAT number: DE123456789)",
+		"(75002 Paris)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output", expected)
+		}
+	}
+
+	// Empty fields collapse
+	if strings.Count(out, "(Email: ") != 1 || strings.Contains(out, "(Phone: )") {
+		t.Errorf("unexpected empty contact details in output")
+	}
+
+	for address, expected := range map[*Address]string{
+		{Address: "Hauptstraße 1", City: "Berlin"}:                 "Hauptstraße 1\nBerlin",
+		{Address: "1 rue de la Paix", PostalCode: "75002"}:         "1 rue de la Paix\n75002",
+		{Address: "1 Main St", Address2: "Suite 2", Country: "US"}: "1 Main St\nSuite 2\nUS",
+	} {
+		if s := address.ToString(); s != expected {
+			t.Errorf("expected %q, got %q", expected, s)
+		}
+	}
+}
+
+func TestReverseCharge(t *testing.T) {
+	doc := newTestDocument(t, &Options{ShowTaxSummary: true})
+	doc.SetCompany(&Contact{Name: "Test Company", VATNumber: "FR40303265045"})
+	doc.SetCustomer(&Contact{Name: "Test Customer", VATNumber: "DE123456789"})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "50", Quantity: "1"})
+	doc.SetDefaultTax(&Tax{Percent: "10"})
+	doc.SetShipping(&Shipping{Amount: "10", Tax: &Tax{Percent: "20"}})
+	doc.SetReverseCharge(true)
+
+	out := buildToString(t, doc)
+
+	if !strings.Contains(out, "(Reverse charge \x97 VAT to be accounted for by the recipient)") {
+		t.Errorf("expected reverse charge mention in output")
+	}
+
+	if tax := doc.Tax(); !tax.IsZero() {
+		t.Errorf("expected zero tax, got %s", tax)
+	}
+
+	if total := doc.TotalWithTax(); total.String() != "160" {
+		t.Errorf("expected total with tax 160, got %s", total)
+	}
+
+	// Tax summary shows the base at 0%
+	if lines := doc.TaxSummary(); len(lines) != 1 || !lines[0].Percent.IsZero() || lines[0].Base.String() != "160" {
+		t.Errorf("unexpected tax summary %+v", lines)
+	}
+
+	// Items and shipping taxes are left as set, for exports and rebuilds
+	if doc.Items[0].Tax.Percent != "20" || doc.Items[1].Tax != nil || doc.Shipping.Tax.Percent != "20" {
+		t.Errorf("expected items and shipping taxes to be unchanged")
+	}
+	buf := &bytes.Buffer{}
+	if err := doc.WriteJSON(buf); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if !strings.Contains(buf.String(), `"percent":"20"`) {
+		t.Errorf("expected exported items taxes to be unchanged")
+	}
+
+	doc.SetReverseCharge(false)
+	rebuildToString(t, doc)
+	if total := doc.TotalWithTax(); total.String() != "187" {
+		t.Errorf("expected total with tax 187 without reverse charge, got %s", total)
+	}
+	doc.SetReverseCharge(true)
+	rebuildToString(t, doc)
+
+	xmlBytes, err := doc.MarshalFacturX()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	for _, expected := range []string{"<ram:CategoryCode>AE</ram:CategoryCode>", `<ram:ID schemeID="VA">DE123456789</ram:ID>`} {
+		if !strings.Contains(string(xmlBytes), expected) {
+			t.Errorf("expected %q in xml", expected)
+		}
+	}
+
+	// Without reverse charge, no mention
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	if out := buildToString(t, doc); strings.Contains(out, "(Reverse charge") {
+		t.Errorf("unexpected reverse charge mention in output")
+	}
+
+	// Additional taxes are not VAT, they are kept
+	ecoTaxes := []*Tax{{Name: "Eco", Amount: "5"}}
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}, Taxes: ecoTaxes})
+	doc.SetReverseCharge(true)
+	buildToString(t, doc)
+
+	if tax := doc.Tax(); tax.String() != "5" {
+		t.Errorf("expected eco tax 5 only, got %s", tax)
+	}
+	if len(doc.Items[0].Taxes) != 1 || doc.Items[0].Taxes[0] != ecoTaxes[0] {
+		t.Errorf("expected item additional taxes to be kept, got %+v", doc.Items[0].Taxes)
+	}
+}
+
+func TestCascadingDiscounts(t *testing.T) {
+	cascaded := &Item{Name: "Test", UnitCost: "100", Quantity: "1", Discount: &Discount{Percent: "10"}, Discounts: []*Discount{{Percent: "5"}}}
+	single := &Item{Name: "Test", UnitCost: "100", Quantity: "1", Discount: &Discount{Percent: "15"}}
+
+	// 5% applies on the total reduced by 10%: 100 - 10 - 4.5
+	if total := cascaded.TotalWithoutTaxAndWithDiscount(); total.String() != "85.5" {
+		t.Errorf("expected cascaded total 85.5, got %s", total)
+	}
+	if total := single.TotalWithoutTaxAndWithDiscount(); total.String() != "85" {
+		t.Errorf("expected single discount total 85, got %s", total)
+	}
+
+	// Amount discounts apply in order too: (100 - 10) * 0.9
+	mixed := &Item{Name: "Test", UnitCost: "100", Quantity: "1", Discounts: []*Discount{{Amount: "10"}, {Percent: "10"}}}
+	if total := mixed.TotalWithoutTaxAndWithDiscount(); total.String() != "81" {
+		t.Errorf("expected mixed total 81, got %s", total)
+	}
+
+	// Each discount is listed, with the discounted amount
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(cascaded)
+	out := buildToString(t, doc)
+	for _, expected := range []string{"(- 10 %)", "(- 5 %)", "(- \x80 14.50)"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output", expected)
+		}
+	}
+
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Discounts: []*Discount{{Percent: "ten"}}})
+	if err := doc.Validate(); !errors.Is(err, ErrInvalidDecimal) {
+		t.Errorf("expected ErrInvalidDecimal, got %v", err)
+	}
+}
+
+func TestItemMinHeight(t *testing.T) {
+	doc := newTestDocument(t, &Options{ItemMinHeight: 10})
+	doc.AppendItem(&Item{Name: "Short", UnitCost: "10", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "Next", UnitCost: "20", Quantity: "1"})
+
+	out := buildToString(t, doc)
+
+	if height := doc.Items[0].height(doc); height != 10 {
+		t.Errorf("expected line height 10, got %v", height)
+	}
+
+	position := func(text string) (float64, float64) {
+		match := regexp.MustCompile(`BT ([0-9.]+) ([0-9.]+) Td \(` + regexp.QuoteMeta(text) + `\)Tj`).FindStringSubmatch(out)
+		if match == nil {
+			t.Fatalf("expected %q in output", text)
+		}
+		x, _ := strconv.ParseFloat(match[1], 64)
+		y, _ := strconv.ParseFloat(match[2], 64)
+		return x, y
+	}
+
+	// Next line starts after the minimum height and the lines spacing
+	_, shortY := position("Short")
+	_, nextY := position("Next")
+	if gap := (shortY - nextY) * 25.4 / 72; gap < 15.99 || gap > 16.01 {
+		t.Errorf("expected 16mm between lines, got %v", gap)
+	}
+
+	// Name and numeric cells are vertically centered on the same baseline
+	if _, qtyY := position("1"); qtyY != shortY {
+		t.Errorf("expected qty at name y %v, got %v", shortY, qtyY)
+	}
+}
+
+func TestZebraRows(t *testing.T) {
+	for _, zebra := range []bool{false, true} {
+		doc := newTestDocument(t, &Options{ZebraRows: zebra})
+		for i := 0; i < 60; i++ {
+			doc.AppendItem(&Item{Name: "Test", Description: strings.Repeat("Long description ", i%3), UnitCost: "10", Quantity: "1"})
+		}
+
+		out := buildToString(t, doc)
+
+		if pages := doc.pdf.PageCount(); pages < 2 {
+			t.Fatalf("expected several pages, got %d", pages)
+		}
+
+		// Every even line is filled, across pages
+		expected := 0
+		if zebra {
+			expected = 30
+		}
+		count := len(regexp.MustCompile(`0\.961 g\n[0-9.]+ [0-9.]+ 538\.58 -[0-9.]+ re f`).FindAllString(out, -1))
+		if count != expected {
+			t.Errorf("zebra %t: expected %d filled lines, got %d", zebra, expected, count)
+		}
+	}
+
+	// Multi-line rows are filled on their whole height
+	doc := newTestDocument(t, &Options{ZebraRows: true})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "Test", Description: "Line 1\nLine 2\nLine 3", UnitCost: "10", Quantity: "1"})
+
+	out := buildToString(t, doc)
+	height := doc.Items[1].height(doc) + 3
+	if !strings.Contains(out, fmt.Sprintf("538.58 %.2f re f", -height*72/25.4)) {
+		t.Errorf("expected a %vmm filled rect", height)
+	}
+}
+
+func TestItemHeader(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "First", UnitCost: "10", Quantity: "1"})
+
+	out := buildToString(t, doc)
+
+	// Header separator lines, above and below titles
+	lines := regexp.MustCompile(`28\.35 ([0-9.]+) m 566\.93 [0-9.]+ l S`).FindAllStringSubmatch(out, -1)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 header separator lines, got %d", len(lines))
+	}
+	top, _ := strconv.ParseFloat(lines[0][1], 64)
+	bottom, _ := strconv.ParseFloat(lines[1][1], 64)
+	if height := (top - bottom) * 25.4 / 72; height < 5.99 || height > 6.01 {
+		t.Errorf("expected 6mm header, got %v", height)
+	}
+
+	// Titles are inside the header
+	title := regexp.MustCompile(`BT [0-9.]+ ([0-9.]+) Td \(` + doc.Options.TextItemsNameTitle + `\)Tj`).FindStringSubmatch(out)
+	if title == nil {
+		t.Fatalf("expected name title in output")
+	}
+	if titleY, _ := strconv.ParseFloat(title[1], 64); titleY > top || titleY < bottom {
+		t.Errorf("expected title between %v and %v, got %v", bottom, top, titleY)
+	}
+
+	// First item is below the header
+	first := regexp.MustCompile(`BT [0-9.]+ ([0-9.]+) Td \(First\)Tj`).FindStringSubmatch(out)
+	if first == nil {
+		t.Fatalf("expected first item in output")
+	}
+	if firstY, _ := strconv.ParseFloat(first[1], 64); firstY >= bottom {
+		t.Errorf("expected first item below header bottom %v, got %v", bottom, firstY)
+	}
+
+	// Rendered at a given y, returns the first item line y
+	doc = newTestDocument(t, &Options{})
+	doc.pdf.AddPage()
+	if y := doc.drawItemHeader(100); y != 108 {
+		t.Errorf("expected first item line at 108, got %v", y)
+	}
+	if err := doc.pdf.Error(); err != nil {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestDeposit(t *testing.T) {
+	cases := []struct {
+		deposit   *Deposit
+		title     string
+		tax       string
+		due       string
+		remaining string
+	}{
+		// 30% of the 120 net payable
+		{&Deposit{Percent: "30"}, "DEPOSIT INVOICE — 30 %", "0", "36", "84"},
+		// 30% of the 100 total without tax, taxed at 10%
+		{&Deposit{Percent: "30", Tax: &Tax{Percent: "10"}}, "DEPOSIT INVOICE — 30 %", "3", "33", "87"},
+		// Fixed amount
+		{&Deposit{Amount: "50"}, "DEPOSIT INVOICE", "0", "50", "70"},
+		{&Deposit{Amount: "50", Tax: &Tax{Percent: "20"}}, "DEPOSIT INVOICE", "10", "60", "60"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+		doc.SetDeposit(c.deposit)
+
+		out := buildToString(t, doc)
+
+		if tax := doc.DepositTax().String(); tax != c.tax {
+			t.Errorf("%+v: expected deposit tax %s, got %s", c.deposit, c.tax, tax)
+		}
+		if due := doc.DepositDue().String(); due != c.due {
+			t.Errorf("%+v: expected deposit due %s, got %s", c.deposit, c.due, due)
+		}
+		if remaining := doc.DepositRemaining().String(); remaining != c.remaining {
+			t.Errorf("%+v: expected remaining %s, got %s", c.deposit, c.remaining, remaining)
+		}
+
+		if !strings.Contains(out, "("+doc.encodeString(c.title)+")Tj") {
+			t.Errorf("%+v: expected title %q in output", c.deposit, c.title)
+		}
+		for _, text := range []string{doc.Options.TextTotalDeposit, doc.Options.TextTotalDepositRemaining} {
+			if !strings.Contains(out, "("+text+")Tj") {
+				t.Errorf("%+v: expected %q in output", c.deposit, text)
+			}
+		}
+		if hasTax := strings.Contains(out, "("+doc.Options.TextTotalDepositTax+")Tj"); hasTax != (c.deposit.Tax != nil) {
+			t.Errorf("%+v: expected deposit tax line %t, got %t", c.deposit, c.deposit.Tax != nil, hasTax)
+		}
+
+		totals, _ := doc.Totals()
+		if totals.Deposit.String() != c.due || totals.DepositRemaining.String() != c.remaining {
+			t.Errorf("%+v: expected totals deposit %s / %s, got %s / %s", c.deposit, c.due, c.remaining, totals.Deposit, totals.DepositRemaining)
+		}
+	}
+
+	doc := newTestDocument(t, &Options{})
+	doc.SetDeposit(&Deposit{})
+	if err := doc.Validate(); !errors.Is(err, ErrInvalidDeposit) {
+		t.Errorf("expected ErrInvalidDeposit, got %v", err)
+	}
+}
+
+func TestMergeDocuments(t *testing.T) {
+	newDocument := func(ref string, items int) *Document {
+		doc := newTestDocument(t, &Options{ShowPageNumbers: true})
+		doc.SetRef(ref)
+		doc.Pdf().SetCompression(false)
+		for i := 0; i < items; i++ {
+			doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+		}
+		return doc
+	}
+
+	sizes := []int{1, 40, 2}
+	expected := 0
+	docs := []*Document{}
+	for index, items := range sizes {
+		doc := newDocument(fmt.Sprintf("ref-%d", index), items)
+		if _, err := doc.Build(); err != nil {
+			t.Fatalf("got error %v", err)
+		}
+		expected += doc.Pdf().PageCount()
+
+		docs = append(docs, newDocument(fmt.Sprintf("ref-%d", index), items))
+	}
+
+	out, err := MergeDocuments(docs)
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if pages := len(regexp.MustCompile(`/Type /Page\n`).FindAll(out, -1)); pages != expected || expected < 4 {
+		t.Errorf("expected %d pages, got %d", expected, pages)
+	}
+
+	// Each document is numbered from its first page
+	for text, count := range map[string]int{"Page 1 of 1": 2, "Page 1 of 2": 1, "Page 2 of 2": 1} {
+		if found := strings.Count(string(out), "("+text+")Tj"); found != count {
+			t.Errorf("expected %d %q, got %d", count, text, found)
+		}
+	}
+
+	if _, err := MergeDocuments(nil); !errors.Is(err, ErrNoDocuments) {
+		t.Errorf("expected ErrNoDocuments, got %v", err)
+	}
+}
+
+// TestConcurrentBuild must pass with the race detector (go test -race)
+func TestConcurrentBuild(t *testing.T) {
+	for _, options := range []*Options{{ShowTaxSummary: true}, {FontFile: "./testdata/DejaVuSansCondensed.ttf"}} {
+		tax := &Tax{Percent: "20"}
+		discount := &Discount{Percent: "10"}
+
+		// Options, taxes and discounts are shared by documents
+		docs := make([]*Document, 8)
+		for index := range docs {
+			docs[index] = newTestDocument(t, options)
+			docs[index].SetDefaultTax(tax)
+			docs[index].SetDiscount(discount)
+			docs[index].AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "2", Discount: discount})
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, len(docs))
+		for index, doc := range docs {
+			wg.Add(1)
+			go func(index int, doc *Document) {
+				defer wg.Done()
+				errs[index] = doc.Write(&bytes.Buffer{})
+			}(index, doc)
+		}
+		wg.Wait()
+
+		for index, err := range errs {
+			if err != nil {
+				t.Errorf("document %d: got error %v", index, err)
+			}
+			if total := docs[index].TotalWithTax().String(); total != "19.44" {
+				t.Errorf("document %d: expected total 19.44, got %s", index, total)
+			}
+		}
+	}
+}
+
+func TestPriceIncludesTax(t *testing.T) {
+	newDoc := func(items ...*Item) *Document {
+		doc := newTestDocument(t, &Options{RoundingMode: RoundingModeHalfUp, ShowTaxSummary: true})
+		for _, item := range items {
+			doc.AppendItem(item)
+		}
+		return doc
+	}
+
+	// Same gross total from net and gross entries
+	exclusive := newDoc(
+		&Item{Name: "Test", UnitCost: "100", Quantity: "3", Tax: &Tax{Percent: "20"}},
+		&Item{Name: "Test", UnitCost: "50", Quantity: "1", Tax: &Tax{Percent: "10"}, Discount: &Discount{Percent: "10"}},
+	)
+	inclusive := newDoc(
+		&Item{Name: "Test", UnitCost: "120", Quantity: "3", Tax: &Tax{Percent: "20"}, PriceIncludesTax: true},
+		&Item{Name: "Test", UnitCost: "55", Quantity: "1", Tax: &Tax{Percent: "10"}, Discount: &Discount{Percent: "10"}, PriceIncludesTax: true},
+	)
+
+	exclusiveTotals, err := exclusive.Totals()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	inclusiveTotals, err := inclusive.Totals()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	expected, _ := json.Marshal(exclusiveTotals)
+	if got, _ := json.Marshal(inclusiveTotals); string(got) != string(expected) {
+		t.Errorf("expected totals %s, got %s", expected, got)
+	}
+	if total := inclusiveTotals.TotalWithTax.String(); total != "409.5" {
+		t.Errorf("expected total with tax 409.5, got %s", total)
+	}
+
+	// Net is rounded and tax is the difference, the gross total is kept
+	doc := newDoc(&Item{Name: "Test", UnitCost: "9.99", Quantity: "3", Tax: &Tax{Percent: "20"}, PriceIncludesTax: true})
+	out := buildToString(t, doc)
+
+	item := doc.Items[0]
+	if net := item.TotalWithoutTaxAndWithDiscount().String(); net != "24.98" {
+		t.Errorf("expected net 24.98, got %s", net)
+	}
+	if tax := item.TaxWithTotalDiscounted().String(); tax != "4.99" {
+		t.Errorf("expected tax 4.99, got %s", tax)
+	}
+	if total := doc.TotalWithTax().String(); total != "29.97" {
+		t.Errorf("expected total with tax 29.97, got %s", total)
+	}
+
+	// Unit cost is rendered without tax, tax summary is consistent with totals
+	for _, text := range []string{"8.33", "24.98", "4.99", "29.97"} {
+		if !strings.Contains(out, " "+text+")Tj") {
+			t.Errorf("expected %q in output", text)
+		}
+	}
+	if summary := doc.TaxSummary(); len(summary) != 1 || !summary[0].Tax.Equal(doc.Tax()) {
+		t.Errorf("expected tax summary of %s, got %+v", doc.Tax(), summary)
+	}
+}
+
+func TestCashRounding(t *testing.T) {
+	cases := []struct {
+		rounding   float64
+		unitCost   string
+		adjustment string
+		netPayable string
+	}{
+		{0.05, "12.03", "0.02", "12.05"},
+		{0.05, "12.02", "-0.02", "12"},
+		{0.05, "12.05", "0", "12.05"},
+		{1, "12.5", "0.5", "13"},
+		{0, "12.03", "0", "12.03"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{CashRounding: c.rounding})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: c.unitCost, Quantity: "1"})
+
+		out := buildToString(t, doc)
+
+		if adjustment := doc.CashRoundingAdjustment().String(); adjustment != c.adjustment {
+			t.Errorf("%v %s: expected adjustment %s, got %s", c.rounding, c.unitCost, c.adjustment, adjustment)
+		}
+		if netPayable := doc.NetPayable().String(); netPayable != c.netPayable {
+			t.Errorf("%v %s: expected net payable %s, got %s", c.rounding, c.unitCost, c.netPayable, netPayable)
+		}
+
+		// Rounding line is rendered with the option only
+		if rendered := strings.Contains(out, "("+doc.Options.TextTotalRounding+")Tj"); rendered != (c.rounding > 0) {
+			t.Errorf("%v %s: expected rounding line %t, got %t", c.rounding, c.unitCost, c.rounding > 0, rendered)
+		}
+	}
+
+	doc := newTestDocument(t, &Options{CashRounding: 0.05})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "12.03", Quantity: "1"})
+	out := buildToString(t, doc)
+	for _, text := range []string{"\x80 0.02)Tj", "\x80 12.05)Tj"} {
+		if !strings.Contains(out, text) {
+			t.Errorf("expected %q in output", text)
+		}
+	}
+}
+
+func TestHideEmptyColumns(t *testing.T) {
+	taxed := func() *Item { return &Item{Name: "Test", UnitCost: "10", Quantity: "1", Tax: &Tax{Percent: "20"}} }
+	untaxed := func() *Item { return &Item{Name: "Test", UnitCost: "10", Quantity: "1"} }
+	discounted := func() *Item {
+		return &Item{Name: "Test", UnitCost: "10", Quantity: "1", Discount: &Discount{Percent: "10"}}
+	}
+
+	cases := []struct {
+		name   string
+		items  []*Item
+		hidden []string
+	}{
+		{"all taxed", []*Item{taxed(), taxed()}, []string{ItemColumnDiscount}},
+		{"none taxed", []*Item{untaxed(), untaxed()}, []string{ItemColumnTax, ItemColumnDiscount}},
+		{"mixed", []*Item{taxed(), untaxed(), discounted()}, []string{}},
+		{"zero tax", []*Item{{Name: "Test", UnitCost: "10", Quantity: "1", Tax: &Tax{Percent: "0"}}}, []string{ItemColumnTax, ItemColumnDiscount}},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{HideEmptyColumns: true})
+		for _, item := range c.items {
+			doc.AppendItem(item)
+		}
+
+		out := buildToString(t, doc)
+
+		keys := map[string]bool{}
+		for _, column := range doc.itemColumns() {
+			keys[column.Key] = true
+		}
+
+		freed := 0.0
+		cols := doc.Options.ColumnOffsets
+		for _, key := range c.hidden {
+			if keys[key] {
+				t.Errorf("%s: expected %s column to be hidden", c.name, key)
+			}
+			if key == ItemColumnTax {
+				freed += cols.TotalTTC - cols.Tax
+			} else {
+				freed += cols.Tax - cols.Discount
+			}
+		}
+		if len(keys) != 7-len(c.hidden) {
+			t.Errorf("%s: expected %d columns, got %d", c.name, 7-len(c.hidden), len(keys))
+		}
+
+		// Freed width goes to the name column, the table keeps its width
+		if _, width := doc.nameColumn(); width != cols.HTPrice-cols.Name+freed {
+			t.Errorf("%s: expected name width %v, got %v", c.name, cols.HTPrice-cols.Name+freed, width)
+		}
+		columns := doc.itemColumns()
+		if last := columns[len(columns)-1]; last.X+last.Width != cols.End {
+			t.Errorf("%s: expected table end at %v, got %v", c.name, cols.End, last.X+last.Width)
+		}
+
+		if rendered := strings.Contains(out, "("+doc.Options.TextItemsTaxTitle+")Tj"); rendered != keys[ItemColumnTax] {
+			t.Errorf("%s: expected tax title %t, got %t", c.name, keys[ItemColumnTax], rendered)
+		}
+	}
+
+	// Columns are kept without the option
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(untaxed())
+	buildToString(t, doc)
+	if columns := doc.itemColumns(); len(columns) != 7 {
+		t.Errorf("expected 7 columns, got %d", len(columns))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.AppendItem(&Item{UnitCost: "10", Quantity: "1", Discounts: []*Discount{nil}})
+	doc.SetCustomer(&Contact{Name: "Test Customer", Address: &Address{City: "Paris"}})
+	doc.SetShipping(&Shipping{})
+
+	err := doc.Validate()
+
+	// All invalid fields are returned at once, named by their json keys
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+
+	fields := map[string]string{}
+	for _, fieldError := range validationErrors {
+		fields[fieldError.Namespace()] = fieldError.Tag()
+	}
+
+	expected := map[string]string{
+		"Document.items[1].name":            "required",
+		"Document.items[1].discounts[0]":    "required",
+		"Document.customer.address.address": "required",
+		"Document.shipping.amount":          "required",
+	}
+	if len(fields) != len(expected) {
+		t.Errorf("expected %d errors, got %v", len(expected), fields)
+	}
+	for namespace, tag := range expected {
+		if fields[namespace] != tag {
+			t.Errorf("expected %s to fail on %q, got %q", namespace, tag, fields[namespace])
+		}
+	}
+
+	doc = newTestDocument(t, &Options{})
+	doc.Items = append(doc.Items, nil)
+	if err := doc.Validate(); !errors.As(err, &validationErrors) {
+		t.Errorf("expected validation errors on nil item, got %v", err)
+	}
+}
+
+func TestValidateColors(t *testing.T) {
+	for _, c := range []struct {
+		options *Options
+		field   string
+	}{
+		{options: &Options{ZebraRowColor: []int{1}}, field: "zebra_row_color"},
+		{options: &Options{ItemSeparatorColor: []int{1, 2}}, field: "item_separator_color"},
+		{options: &Options{AmountDueBoxColor: []int{1}}, field: "amount_due_box_color"},
+		{options: &Options{AmountDueBorderColor: []int{1, 2, 3, 4}}, field: "amount_due_border_color"},
+		{options: &Options{HeaderBandColor: []int{1}}, field: "header_band_color"},
+		{options: &Options{HeaderBandTextColor: []int{1, 2}}, field: "header_band_text_color"},
+	} {
+		doc := newTestDocument(t, c.options)
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+		var validationErrors validator.ValidationErrors
+		if err := doc.Validate(); !errors.As(err, &validationErrors) || validationErrors[0].Field() != c.field {
+			t.Errorf("expected %s validation error, got %v", c.field, err)
+		}
+	}
+}
+
+func TestPartFonts(t *testing.T) {
+	doc := newTestDocument(t, &Options{
+		ShowPageNumbers: true,
+		TitleFont:       &TextFont{Family: "Times", Style: "B", Size: 20},
+		HeaderFont:      &TextFont{Family: "Courier", Size: 9},
+		ItemFont:        &TextFont{Family: "Times", Style: "I", Size: 9},
+		FooterFont:      &TextFont{Family: "Courier", Style: "R"},
+	})
+	doc.AppendItem(&Item{Name: "Widget", Description: "Blue", UnitCost: "10", Quantity: "1"})
+
+	out := buildToString(t, doc)
+
+	// Fonts resources, by name then by object
+	objects := map[string]string{}
+	for _, match := range regexp.MustCompile(`(\d+) 0 obj\n<</Type /Font\n/BaseFont /([\w-]+)`).FindAllStringSubmatch(out, -1) {
+		objects[match[1]] = match[2]
+	}
+	fonts := map[string]string{}
+	for _, match := range regexp.MustCompile(`/F(\w+) (\d+) 0 R`).FindAllStringSubmatch(out, -1) {
+		fonts[match[1]] = objects[match[2]]
+	}
+
+	// fontOf returns the font and size selected before text
+	fontOf := func(text string) string {
+		index := strings.Index(out, "("+text+")Tj")
+		if index < 0 {
+			t.Fatalf("expected %q in output", text)
+		}
+		matches := regexp.MustCompile(`BT /F(\w+) ([0-9.]+) Tf ET`).FindAllStringSubmatch(out[:index], -1)
+		last := matches[len(matches)-1]
+		return fonts[last[1]] + " " + last[2]
+	}
+
+	for text, expected := range map[string]string{
+		"INVOICE":                          "Times-Bold 20.00",
+		doc.Options.TextItemsNameTitle:     "Courier-Bold 9.00",
+		"Widget":                           "Times-Italic 9.00",
+		"Blue":                             "Times-Italic 7.88",
+		"Page 1 of 1":                      "Courier 7.00",
+		doc.Options.TextItemsUnitCostTitle: "Courier-Bold 9.00",
+	} {
+		if font := fontOf(text); font != expected {
+			t.Errorf("expected %q in %s, got %s", text, expected, font)
+		}
+	}
+
+	// Single font by default
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Widget", UnitCost: "10", Quantity: "1"})
+	out = buildToString(t, doc)
+	if regexp.MustCompile(`/BaseFont /(Times|Courier)`).MatchString(out) {
+		t.Errorf("expected only the default font")
+	}
+}
+
+func TestTotalsDetails(t *testing.T) {
+	doc := newTestDocument(t, &Options{ShowTotalsDetails: true, ShowGrandTotalBox: true})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}, Discount: &Discount{Percent: "10"}})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "50", Quantity: "2", Tax: &Tax{Percent: "5.5"}})
+	doc.SetDiscount(&Discount{Percent: "10"})
+
+	out := buildToString(t, doc)
+
+	// Totals block texts, in rendering order
+	start := strings.Index(out, "("+doc.Options.TextTotalDiscount+")Tj")
+	if start < 0 {
+		t.Fatalf("expected %q in output", doc.Options.TextTotalDiscount)
+	}
+	texts := []string{}
+	for _, match := range regexp.MustCompile(`BT [0-9.]+ [0-9.]+ Td \((.*?)\)Tj`).FindAllStringSubmatch(out[start-40:], -1) {
+		texts = append(texts, match[1])
+	}
+
+	expected := []string{
+		"TOTAL DISCOUNT", "-\x80 29.00",
+		"TOTAL", "\x80 190.00",
+		"TOTAL DISCOUNTED", "-10 % / -\x80 19.00", "\x80 171.00",
+		"TAX 20 %", "\x80 16.20",
+		"TAX 5.5 %", "\x80 4.95",
+		"TOTAL WITH TAX", "\x80 192.15",
+	}
+	if len(texts) < len(expected) {
+		t.Fatalf("expected %q, got %q", expected, texts)
+	}
+	for i, text := range expected {
+		if texts[i] != text {
+			t.Errorf("expected %q, got %q", expected, texts[:len(expected)])
+			break
+		}
+	}
+
+	// Values reconcile with totals
+	totals, err := doc.Totals()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if !totals.ItemsDiscount.Add(totals.Discount).Equal(decimal.NewFromFloat(29)) {
+		t.Errorf("expected total discount 29, got %s", totals.ItemsDiscount.Add(totals.Discount))
+	}
+	if !totals.Tax.Equal(decimal.NewFromFloat(21.15)) || !totals.TotalWithTax.Equal(decimal.NewFromFloat(192.15)) {
+		t.Errorf("expected tax 21.15 and total 192.15, got %s and %s", totals.Tax, totals.TotalWithTax)
+	}
+
+	// Grand total in bold, boxed
+	if !strings.Contains(out, "/BaseFont /Helvetica-Bold") {
+		t.Errorf("expected bold font in output")
+	}
+	if !regexp.MustCompile(`340\.16 [0-9.]+ 226\.77 -28\.35 re S`).MatchString(out) {
+		t.Errorf("expected grand total box in output")
+	}
+
+	// Single tax line by default
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "50", Quantity: "2", Tax: &Tax{Percent: "5.5"}})
+	out = buildToString(t, doc)
+	if strings.Contains(out, "(TAX 20 %)Tj") || !strings.Contains(out, "(TAX)Tj") {
+		t.Errorf("expected a single tax line without details")
+	}
+}
+
+func TestItemImage(t *testing.T) {
+	thumbnail := &bytes.Buffer{}
+	if err := png.Encode(thumbnail, image.NewRGBA(image.Rect(0, 0, 10, 10))); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Pictured", Description: "Blue", UnitCost: "10", Quantity: "1", Image: &ItemImage{Bytes: thumbnail.Bytes()}})
+	doc.AppendItem(&Item{Name: "Plain", UnitCost: "10", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "Last", UnitCost: "10", Quantity: "1"})
+
+	out := buildToString(t, doc)
+
+	// Image line is as tall as the image, plain line as its name
+	if height := doc.Items[0].height(doc); height != 12 {
+		t.Errorf("expected image line height 12, got %f", height)
+	}
+	if height := doc.Items[1].height(doc); height != 3 {
+		t.Errorf("expected plain line height 3, got %f", height)
+	}
+	if !strings.Contains(out, "q 34.01575 0 0 34.01575 ") {
+		t.Errorf("expected 12mm image in output")
+	}
+
+	// position returns the x and y (mm from top) of text
+	position := func(text string) (float64, float64) {
+		match := regexp.MustCompile(`BT ([0-9.]+) ([0-9.]+) Td \(` + text + `\)Tj`).FindStringSubmatch(out)
+		if match == nil {
+			t.Fatalf("expected %q in output", text)
+		}
+		x, _ := strconv.ParseFloat(match[1], 64)
+		y, _ := strconv.ParseFloat(match[2], 64)
+		return x / doc.pdf.GetConversionRatio(), 297 - y/doc.pdf.GetConversionRatio()
+	}
+
+	// Pictured text is shifted after the image and centered beside it: 7mm of text, 2.5mm below
+	// the top of a 12mm line, so the next line starts 9.5mm after it instead of 3mm
+	picturedX, picturedY := position("Pictured")
+	plainX, plainY := position("Plain")
+	_, lastY := position("Last")
+	if shift := picturedX - plainX; shift < 13.99 || shift > 14.01 {
+		t.Errorf("expected name shifted by 14mm, got %f", shift)
+	}
+	if diff := (plainY - picturedY) - (lastY - plainY); diff < 6.49 || diff > 6.51 {
+		t.Errorf("expected 6.5mm more spacing after image line, got %f", diff)
+	}
+
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1", Image: &ItemImage{Path: "./missing_image.png"}})
+	if _, err := doc.Build(); !errors.Is(err, ErrInvalidItemImage) {
+		t.Errorf("expected ErrInvalidItemImage, got %v", err)
+	}
+}
+
+func TestHeaderBand(t *testing.T) {
+	doc := newTestDocument(t, &Options{ShowHeaderBand: true, HeaderBandColor: []int{0, 102, 204}})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+	out := buildToString(t, doc)
+
+	// Full width band filled at the top of the page
+	if !strings.Contains(out, "0.000 0.400 0.800 rg\n0.00 841.89 595.28 -70.87 re f") {
+		t.Errorf("expected header band rect in output")
+	}
+
+	// Title and ref in the band, in contrasting text
+	for _, text := range []string{"INVOICE", doc.refString()} {
+		match := regexp.MustCompile(`q 1\.000 g BT [0-9.]+ ([0-9.]+) Td \(` + regexp.QuoteMeta(text) + `\)Tj`).FindStringSubmatch(out)
+		if match == nil {
+			t.Errorf("expected %q in white in output", text)
+			continue
+		}
+		if y, _ := strconv.ParseFloat(match[1], 64); y < 841.89-70.87 {
+			t.Errorf("expected %q in header band, got y %f", text, y)
+		}
+	}
+
+	// Contacts below the band
+	match := regexp.MustCompile(`BT [0-9.]+ ([0-9.]+) Td \(Test Company\)Tj`).FindStringSubmatch(out)
+	if match == nil {
+		t.Fatalf("expected company in output")
+	}
+	if y, _ := strconv.ParseFloat(match[1], 64); y > 841.89-70.87 {
+		t.Errorf("expected company below header band, got y %f", y)
+	}
+}
+
+func TestItemPeriod(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Hosting", Description: "Pro plan", UnitCost: "10", Quantity: "1", PeriodStart: "01/01/2024", PeriodEnd: "01/31/2024"})
+	doc.AppendItem(&Item{Name: "Support", UnitCost: "10", Quantity: "1", PeriodStart: "02/01/2024"})
+	doc.AppendItem(&Item{Name: "Setup", UnitCost: "10", Quantity: "1"})
+
+	out := buildToString(t, doc)
+
+	for _, text := range []string{"(01/01/2024 to 01/31/2024)Tj", "(From 02/01/2024)Tj"} {
+		if !strings.Contains(out, text) {
+			t.Errorf("expected %q in output", text)
+		}
+	}
+
+	// Period below the description, lines heights include it
+	if strings.Index(out, "(Pro plan)Tj") > strings.Index(out, "(01/01/2024 to 01/31/2024)Tj") {
+		t.Errorf("expected period below description")
+	}
+	for index, expected := range []float64{11, 7, 3} {
+		if height := doc.Items[index].height(doc); height != expected {
+			t.Errorf("expected item %d height %f, got %f", index, expected, height)
+		}
+	}
+
+	// Invalid periods
+	for _, item := range []*Item{
+		{Name: "Test", UnitCost: "10", Quantity: "1", PeriodStart: "2024-01-01"},
+		{Name: "Test", UnitCost: "10", Quantity: "1", PeriodStart: "01/31/2024", PeriodEnd: "01/01/2024"},
+	} {
+		doc = newTestDocument(t, &Options{})
+		doc.AppendItem(item)
+		if _, err := doc.Build(); !errors.Is(err, ErrInvalidItemPeriod) {
+			t.Errorf("expected ErrInvalidItemPeriod, got %v", err)
+		}
+	}
+}
+
+func TestEarlyPaymentDiscount(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.SetEarlyPaymentDiscount(&EarlyPaymentDiscount{Percent: "2", Days: 10})
+
+	out := buildToString(t, doc)
+
+	if amount := doc.EarlyPaymentDiscountAmount(); !amount.Equal(decimal.NewFromFloat(2.4)) {
+		t.Errorf("expected discount 2.40, got %s", amount)
+	}
+	if total := doc.EarlyPaymentTotal(); !total.Equal(decimal.NewFromFloat(117.6)) {
+		t.Errorf("expected discounted total 117.60, got %s", total)
+	}
+	if !strings.Contains(out, "(2 % discount if paid within 10 days: \x80 117.60)Tj") {
+		t.Errorf("expected early payment discount mention in output")
+	}
+
+	// Grand total is unchanged
+	if total := doc.NetPayable(); !total.Equal(decimal.NewFromFloat(120)) {
+		t.Errorf("expected net payable 120, got %s", total)
+	}
+	if !strings.Contains(out, "(\x80 120.00)Tj") {
+		t.Errorf("expected undiscounted total in output")
+	}
+
+	// Translated wording
+	doc = newTestDocument(t, &Options{Language: "de"})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.SetEarlyPaymentDiscount(&EarlyPaymentDiscount{Percent: "2", Days: 10})
+	if out := buildToString(t, doc); !strings.Contains(out, "(2 % Skonto bei Zahlung innerhalb von 10 Tagen: \x80 117.60)Tj") {
+		t.Errorf("expected german early payment discount mention in output")
+	}
+
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1"})
+	doc.SetEarlyPaymentDiscount(&EarlyPaymentDiscount{Percent: "two", Days: 10})
+	if _, err := doc.Build(); err == nil {
+		t.Errorf("expected error on invalid early payment discount percent")
+	}
+}
+
+func TestMarshalUBL(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.SetDate("02/03/2021")
+	doc.SetDueDate("03/05/2021")
+	doc.SetCompany(&Contact{Name: "Test Company", VATNumber: "FR123", Address: &Address{Address: "1 rue de Paris", PostalCode: "75001", City: "Paris", Country: "FR"}})
+	doc.AppendItem(&Item{Ref: "SKU-1", Name: "Test", UnitCost: "50", Quantity: "2", Tax: &Tax{Percent: "20"}, Discount: &Discount{Percent: "10"}})
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	xmlBytes, err := doc.MarshalUBL()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	// Elements by path, and root children in order
+	values := map[string]string{}
+	children := []string{}
+	path := []string{}
+	decoder := xml.NewDecoder(bytes.NewReader(xmlBytes))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			path = append(path, element.Name.Local)
+			if len(path) == 2 && (len(children) == 0 || children[len(children)-1] != element.Name.Local) {
+				children = append(children, element.Name.Local)
+			}
+		case xml.CharData:
+			if text := strings.TrimSpace(string(element)); len(text) > 0 {
+				values[strings.Join(path, "/")] = text
+			}
+		case xml.EndElement:
+			path = path[:len(path)-1]
+		}
+	}
+
+	expectedChildren := []string{
+		"CustomizationID", "ProfileID", "ID", "IssueDate", "DueDate", "InvoiceTypeCode", "DocumentCurrencyCode",
+		"AccountingSupplierParty", "AccountingCustomerParty", "TaxTotal", "LegalMonetaryTotal", "InvoiceLine",
+	}
+	if strings.Join(children, ",") != strings.Join(expectedChildren, ",") {
+		t.Errorf("expected elements %v, got %v", expectedChildren, children)
+	}
+
+	for key, expected := range map[string]string{
+		"Invoice/CustomizationID":      "urn:cen.eu:en16931:2017#compliant#urn:fdc:peppol.eu:2017:poacc:billing:3.0",
+		"Invoice/ID":                   "ref",
+		"Invoice/IssueDate":            "2021-02-03",
+		"Invoice/DueDate":              "2021-03-05",
+		"Invoice/InvoiceTypeCode":      "380",
+		"Invoice/DocumentCurrencyCode": "EUR",
+		"Invoice/AccountingSupplierParty/Party/PostalAddress/Country/IdentificationCode": "FR",
+		"Invoice/AccountingSupplierParty/Party/PartyTaxScheme/CompanyID":                 "FR123",
+		"Invoice/AccountingSupplierParty/Party/PartyLegalEntity/RegistrationName":        "Test Company",
+		"Invoice/AccountingCustomerParty/Party/PartyLegalEntity/RegistrationName":        "Test Customer",
+		"Invoice/TaxTotal/TaxAmount":                             "18.00",
+		"Invoice/TaxTotal/TaxSubtotal/TaxableAmount":             "90.00",
+		"Invoice/TaxTotal/TaxSubtotal/TaxCategory/ID":            "S",
+		"Invoice/TaxTotal/TaxSubtotal/TaxCategory/Percent":       "20.00",
+		"Invoice/LegalMonetaryTotal/LineExtensionAmount":         "90.00",
+		"Invoice/LegalMonetaryTotal/TaxExclusiveAmount":          "90.00",
+		"Invoice/LegalMonetaryTotal/TaxInclusiveAmount":          "108.00",
+		"Invoice/LegalMonetaryTotal/PayableAmount":               "108.00",
+		"Invoice/InvoiceLine/ID":                                 "1",
+		"Invoice/InvoiceLine/InvoicedQuantity":                   "2",
+		"Invoice/InvoiceLine/LineExtensionAmount":                "90.00",
+		"Invoice/InvoiceLine/AllowanceCharge/Amount":             "10.00",
+		"Invoice/InvoiceLine/Item/Name":                          "Test",
+		"Invoice/InvoiceLine/Item/SellersItemIdentification/ID":  "SKU-1",
+		"Invoice/InvoiceLine/Item/ClassifiedTaxCategory/Percent": "20.00",
+		"Invoice/InvoiceLine/Price/PriceAmount":                  "50.00",
+	} {
+		if values[key] != expected {
+			t.Errorf("expected %s to be %q, got %q", key, expected, values[key])
+		}
+	}
+	if !strings.Contains(string(xmlBytes), `<cbc:PayableAmount currencyID="EUR">108.00</cbc:PayableAmount>`) {
+		t.Errorf("expected payable amount with currency in xml")
+	}
+
+	doc.SetType(CreditNote)
+	if _, err := doc.MarshalUBL(); !errors.Is(err, ErrUBLInvalidType) {
+		t.Errorf("expected ErrUBLInvalidType, got %v", err)
+	}
+}
+
+func TestReadItemsCSV(t *testing.T) {
+	items, err := ReadItemsCSV(strings.NewReader("Name,Unit cost,Quantity,Tax %,Discount\n" +
+		"Widget,12.50,3,20,10%\n" +
+		"\"Gadget, large\",100,1,5.5 %,15.00\n" +
+		"Service,40,2,,\n"))
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	if item := items[0]; item.Name != "Widget" || item.UnitCost != "12.50" || item.Quantity != "3" || item.Tax.Percent != "20" || item.Discount.Percent != "10" {
+		t.Errorf("unexpected first item %+v", item)
+	}
+	if item := items[1]; item.Name != "Gadget, large" || item.Tax.Percent != "5.5" || item.Discount.Amount != "15.00" {
+		t.Errorf("unexpected second item %+v", item)
+	}
+	if item := items[2]; item.Tax != nil || item.Discount != nil {
+		t.Errorf("expected third item without tax and discount, got %+v", item)
+	}
+
+	// Items build like json ones
+	doc := newTestDocument(t, &Options{})
+	for _, item := range items {
+		doc.AppendItem(item)
+	}
+	if _, err := doc.Build(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if total := doc.TotalWithoutTax(); !total.Equal(decimal.NewFromFloat(198.75)) {
+		t.Errorf("expected total 198.75, got %s", total)
+	}
+
+	// Errors report the csv line
+	for input, expected := range map[string]string{
+		"Name,Unit cost,Quantity\nWidget,12.50,3\nGadget,abc,1\n": "line 3: field unit_cost",
+		"Name,Unit cost,Quantity,Tax %\nWidget,12.50,3,twenty\n":  "line 2: field tax",
+		"Name,Unit cost,Quantity\n,12.50,3\n":                     "line 2: field name",
+		"Name,Price,Quantity\nWidget,12.50,3\n":                   `unknown column "Price"`,
+		"Name,Quantity\nWidget,3\n":                               "missing column unit_cost",
+	} {
+		_, err := ReadItemsCSV(strings.NewReader(input))
+		if !errors.Is(err, ErrInvalidCSV) || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected ErrInvalidCSV with %q, got %v", expected, err)
+		}
+	}
+}
+
+func TestSetCurrencySymbol(t *testing.T) {
+	options := &Options{}
+	doc := newTestDocument(t, options)
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "1234.56", Quantity: "1"})
+
+	// Default options symbol
+	if out := buildToString(t, doc); !strings.Contains(out, "(\x80 1 234.56)Tj") {
+		t.Errorf("expected default symbol in output")
+	}
+
+	// Symbol of this document, built again from a new pdf
+	doc.SetCurrencySymbol(" EUR", CurrencyPositionAfter)
+	out := rebuildToString(t, doc)
+	if !strings.Contains(out, "(1 234.56 EUR)Tj") || strings.Contains(out, "(\x80 1 234.56)Tj") {
+		t.Errorf("expected EUR symbol after amounts in output")
+	}
+	if count := doc.pdf.PageCount(); count != 1 {
+		t.Errorf("expected a single page once built again, got %d", count)
+	}
+
+	// Position defaults to options one, and options are untouched
+	doc.SetCurrencySymbol("EUR ", "")
+	if out := rebuildToString(t, doc); !strings.Contains(out, "(EUR 1 234.56)Tj") {
+		t.Errorf("expected EUR symbol before amounts in output")
+	}
+	if options.CurrencySymbol != "€ " {
+		t.Errorf("expected options symbol unchanged, got %q", options.CurrencySymbol)
+	}
+
+	other := newTestDocument(t, options)
+	other.AppendItem(&Item{Name: "Test", UnitCost: "1234.56", Quantity: "1"})
+	if out := buildToString(t, other); !strings.Contains(out, "(\x80 1 234.56)Tj") {
+		t.Errorf("expected default symbol in document sharing options")
+	}
+}
+
+func TestTheme(t *testing.T) {
+	doc := newTestDocument(t, &Options{Theme: ThemeModernBlue, DarkBgColor: []int{1, 2, 3}})
+
+	for name, expected := range map[string][]int{
+		"BaseTextColor":   {33, 43, 54},
+		"GreyBgColor":     {227, 236, 248},
+		"HeaderBandColor": {21, 101, 192},
+		"DarkBgColor":     {1, 2, 3},
+	} {
+		color := reflect.ValueOf(doc.Options).Elem().FieldByName(name).Interface().([]int)
+		if fmt.Sprint(color) != fmt.Sprint(expected) {
+			t.Errorf("expected %s %v, got %v", name, expected, color)
+		}
+	}
+
+	// Overridden once New returned
+	doc.Options.GreyTextColor = []int{4, 5, 6}
+	doc.AppendItem(&Item{Name: "Test", Description: "Grey", UnitCost: "10", Quantity: "1"})
+	if out := buildToString(t, doc); !strings.Contains(out, "0.016 0.020 0.024 rg BT") {
+		t.Errorf("expected overridden grey text color in output")
+	}
+
+	// Classic is the defaults
+	classic, defaults := newTestDocument(t, &Options{Theme: ThemeClassic}), newTestDocument(t, &Options{})
+	for name := range themes[ThemeClassic] {
+		field := func(doc *Document) interface{} {
+			return reflect.ValueOf(doc.Options).Elem().FieldByName(name).Interface()
+		}
+		if fmt.Sprint(field(classic)) != fmt.Sprint(field(defaults)) {
+			t.Errorf("expected classic %s %v to match default %v", name, field(classic), field(defaults))
+		}
+	}
+
+	doc = newTestDocument(t, &Options{Theme: "unknown"})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	if _, err := doc.Build(); err == nil {
+		t.Errorf("expected error on unknown theme")
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	signatureImage := &bytes.Buffer{}
+	if err := png.Encode(signatureImage, image.NewRGBA(image.Rect(0, 0, 40, 10))); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := newTestDocument(t, &Options{})
+	doc.SetType(Quotation)
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.AppendSignature(&Signature{Label: "For the company", Image: signatureImage.Bytes()})
+	doc.AppendSignature(&Signature{Label: "Customer acceptance"})
+
+	out := buildToString(t, doc)
+
+	for _, text := range []string{"(For the company)Tj", "(Customer acceptance)Tj", "(Date and signature)Tj"} {
+		if !strings.Contains(out, text) {
+			t.Errorf("expected %q in output", text)
+		}
+	}
+
+	// Lines at the bottom of the page, left and right
+	for _, line := range []string{"28.35 121.89 m 255.12 121.89 l S", "340.16 121.89 m 566.93 121.89 l S"} {
+		if !strings.Contains(out, line) {
+			t.Errorf("expected signature line %q in output", line)
+		}
+	}
+	if count := strings.Count(out, " Do Q"); count != 1 {
+		t.Errorf("expected signature image in output, got %d images", count)
+	}
+
+	// No signature by default
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	if out := buildToString(t, doc); strings.Contains(out, "(Date and signature)Tj") {
+		t.Errorf("expected no signature by default")
+	}
+
+	doc.AppendSignature(&Signature{Label: "A"}).AppendSignature(&Signature{Label: "B"}).AppendSignature(&Signature{Label: "C"})
+	if _, err := doc.Build(); err == nil {
+		t.Errorf("expected error with more than two signatures")
+	}
+
+	doc = newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.AppendSignature(&Signature{Label: "A", ImagePath: "./missing_signature.png"})
+	if _, err := doc.Build(); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestLocale(t *testing.T) {
+	cases := []struct {
+		locale   string
+		currency Currency
+		expected string
+	}{
+		{locale: "fr-FR", currency: CurrencyEUR, expected: "(1 234,56 \x80)Tj"},
+		{locale: "en-US", currency: CurrencyUSD, expected: "($1,234.56)Tj"},
+		{locale: "de-DE", currency: CurrencyEUR, expected: "(1.234,56 \x80)Tj"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{Locale: c.locale})
+		doc.SetCurrency(c.currency)
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "1234.56", Quantity: "1"})
+
+		out := buildToString(t, doc)
+
+		// Unit price, item total and totals
+		if count := strings.Count(out, c.expected); count < 4 {
+			t.Errorf("%s: expected %q at least 4 times, got %d", c.locale, c.expected, count)
+		}
+	}
+
+	doc := newTestDocument(t, &Options{Locale: "xx-XX"})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	if _, err := doc.Build(); err == nil {
+		t.Errorf("expected error on unknown locale")
+	}
+}
+
+func TestItemFootnote(t *testing.T) {
+	doc := newTestDocument(t, &Options{SortItemsBy: SortItemsByName})
+	doc.AppendItem(&Item{Name: "Zinc sheet", UnitCost: "10", Quantity: "5", Footnote: "Price subject to final measurement"})
+	doc.AppendItem(&Item{Name: "Labour", UnitCost: "20", Quantity: "5"})
+	doc.AppendItem(&Item{Name: "Brass rod", UnitCost: "30", Quantity: "5", Footnote: "Delivered next month"})
+
+	// Markers follow render order: Brass rod, Labour, Zinc sheet
+	order := []string{
+		"(Brass rod)Tj", "(1)Tj",
+		"(Zinc sheet)Tj", "(2)Tj",
+		"(1)Tj", "(Delivered next month)Tj",
+		"(2)Tj", "(Price subject to final measurement)Tj",
+	}
+	expectOrder := func(out string) {
+		t.Helper()
+
+		index := 0
+		for _, text := range order {
+			next := strings.Index(out[index:], text)
+			if next < 0 {
+				t.Fatalf("expected %q after offset %d", text, index)
+			}
+			index += next + len(text)
+		}
+
+		if strings.Contains(out, "(3)Tj") {
+			t.Errorf("expected two footnotes markers")
+		}
+	}
+
+	expectOrder(buildToString(t, doc))
+
+	// Markers are numbered again on rebuild
+	expectOrder(rebuildToString(t, doc))
+}
+
+func TestCompact(t *testing.T) {
+	pages := func(compact bool) int {
+		doc := newTestDocument(t, &Options{Compact: compact})
+		for index := 0; index < 60; index++ {
+			doc.AppendItem(&Item{Name: fmt.Sprintf("Item %d", index), UnitCost: "10", Quantity: "2"})
+		}
+
+		pdf, err := doc.Build()
+		if err != nil {
+			t.Fatalf("got error %v", err)
+		}
+
+		return pdf.PageCount()
+	}
+
+	regular, compact := pages(false), pages(true)
+	if compact >= regular {
+		t.Errorf("expected compact layout to fit more items per page, got %d pages, %d without compact", compact, regular)
+	}
+}
+
+func TestShowDiscountAmount(t *testing.T) {
+	doc := newTestDocument(t, &Options{ShowDiscountAmount: true})
+	doc.AppendItem(&Item{Name: "Percent", UnitCost: "500", Quantity: "1", Discount: &Discount{Percent: "10"}})
+	doc.AppendItem(&Item{Name: "Amount", UnitCost: "50", Quantity: "1", Discount: &Discount{Amount: "5"}})
+	doc.AppendItem(&Item{
+		Name:      "Cascading",
+		UnitCost:  "500",
+		Quantity:  "1",
+		Discount:  &Discount{Percent: "10"},
+		Discounts: []*Discount{{Percent: "5"}},
+	})
+
+	out := buildToString(t, doc)
+
+	for _, expected := range []string{
+		"(10 % \\(- \x80 50.00\\))Tj",
+		"(- \x80 5.00)Tj",
+		"(5 % \\(- \x80 22.50\\))Tj",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output", expected)
+		}
+	}
+
+	// The discounted amount is not repeated below the percent
+	if strings.Contains(out, "(- \x80 50.00)Tj") || strings.Contains(out, "(- \x80 72.50)Tj") {
+		t.Errorf("expected no separate discounted amount")
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	textRegexp := regexp.MustCompile(`BT ([0-9.]+) ([0-9.]+) Td`)
+	rectRegexp := regexp.MustCompile(`(-?[0-9.]+) (-?[0-9.]+) ([0-9.]+) (-?[0-9.]+) re`)
+
+	cases := []struct {
+		size        string
+		orientation string
+		mediaBox    string
+		width       float64 // pt
+		height      float64 // pt
+	}{
+		{size: PageSizeA4, orientation: OrientationPortrait, mediaBox: "/MediaBox [0 0 595.28 841.89]", width: 595.28, height: 841.89},
+		{size: PageSizeLetter, orientation: OrientationLandscape, mediaBox: "/MediaBox [0 0 792.00 612.00]", width: 792, height: 612},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{PageSize: c.size, Orientation: c.orientation, ShowPageNumbers: true})
+		doc.SetCustomer(&Contact{Name: "Test Customer", Address: &Address{Address: "1 Main Street", City: "Paris", PostalCode: "75000"}})
+		doc.SetNotes("Thanks for your business")
+		doc.SetDefaultTax(&Tax{Percent: "20"})
+		doc.SetDiscount(&Discount{Percent: "5"})
+		for index := 0; index < 40; index++ {
+			doc.AppendItem(&Item{Name: fmt.Sprintf("Item %d", index), Description: "Description", UnitCost: "12.50", Quantity: "3"})
+		}
+
+		out := buildToString(t, doc)
+		if !strings.Contains(out, c.mediaBox) {
+			t.Errorf("%s %s: expected %s", c.size, c.orientation, c.mediaBox)
+		}
+
+		for _, match := range textRegexp.FindAllStringSubmatch(out, -1) {
+			x, _ := strconv.ParseFloat(match[1], 64)
+			y, _ := strconv.ParseFloat(match[2], 64)
+			if x < 0 || x >= c.width || y < 0 || y >= c.height {
+				t.Errorf("%s %s: text at %.2f %.2f overflows the page", c.size, c.orientation, x, y)
+			}
+		}
+
+		for _, match := range rectRegexp.FindAllStringSubmatch(out, -1) {
+			x, _ := strconv.ParseFloat(match[1], 64)
+			w, _ := strconv.ParseFloat(match[3], 64)
+			if x < 0 || x+w > c.width+0.01 {
+				t.Errorf("%s %s: rect at %.2f, %.2f wide overflows the page", c.size, c.orientation, x, w)
+			}
+		}
+
+		// Totals are anchored to the page right margin
+		total := regexp.MustCompile(`BT ([0-9.]+) [0-9.]+ Td \(TOTAL WITH TAX\)Tj`).FindStringSubmatch(out)
+		if total == nil {
+			t.Fatalf("%s %s: expected total with tax", c.size, c.orientation)
+		}
+		if x, _ := strconv.ParseFloat(total[1], 64); x < c.width/2 || x > c.width-100 {
+			t.Errorf("%s %s: expected totals on the right of the page, got x %.2f", c.size, c.orientation, x)
+		}
+	}
+
+	doc := newTestDocument(t, &Options{PageSize: "A3"})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	if _, err := doc.Build(); err == nil {
+		t.Errorf("expected error on unsupported page size")
+	}
+}
+
+func TestGenerateBatch(t *testing.T) {
+	docs := make([]*Document, 100)
+	for index := range docs {
+		doc := newTestDocument(t, &Options{})
+		doc.pdf.SetCompression(false)
+		doc.SetRef(fmt.Sprintf("batch-%d", index))
+
+		unitCost := "10"
+		if index%25 == 7 {
+			unitCost = "invalid"
+		}
+		doc.AppendItem(&Item{Name: "Test", UnitCost: unitCost, Quantity: "1"})
+		docs[index] = doc
+	}
+
+	results, err := GenerateBatch(docs, 8)
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected batch error, got %v", err)
+	}
+	if len(batchErr.Errors) != 4 {
+		t.Errorf("expected 4 failed documents, got %d", len(batchErr.Errors))
+	}
+	if !errors.Is(err, ErrInvalidDecimal) {
+		t.Errorf("expected first document error to be an invalid decimal, got %v", err)
+	}
+
+	if len(results) != len(docs) {
+		t.Fatalf("expected %d results, got %d", len(docs), len(results))
+	}
+	for index, result := range results {
+		if index%25 == 7 {
+			if result != nil || batchErr.Errors[index] == nil {
+				t.Errorf("document %d: expected error and no pdf", index)
+			}
+			continue
+		}
+
+		if !strings.Contains(string(result), fmt.Sprintf("(Ref.: batch-%d)Tj", index)) {
+			t.Errorf("document %d: expected its own pdf", index)
+		}
+	}
+
+	// Without errors
+	results, err = GenerateBatch(docs[:5], 0)
+	if err != nil {
+		t.Errorf("got error %v", err)
+	}
+	if len(results) != 5 {
+		t.Errorf("expected 5 results, got %d", len(results))
+	}
+}
+
+func TestShowLineNumbers(t *testing.T) {
+	doc := newTestDocument(t, &Options{ShowLineNumbers: true, ShowItemRef: true})
+	for index := 0; index < 70; index++ {
+		section := "Hardware"
+		if index >= 40 {
+			section = "Services"
+		}
+		doc.AppendItem(&Item{Ref: "REF", Name: fmt.Sprintf("Item %d", index), UnitCost: "10", Quantity: "2", Section: section})
+	}
+
+	out := buildToString(t, doc)
+	if doc.pdf.PageCount() < 2 {
+		t.Fatalf("expected a multi-page invoice, got %d pages", doc.pdf.PageCount())
+	}
+
+	if !strings.Contains(out, "(#)Tj") {
+		t.Errorf("expected line numbers column title")
+	}
+
+	// Numbers of the line numbers column, at the table left edge
+	numbers := []string{}
+	for _, match := range regexp.MustCompile(`BT 31\.18 [0-9.]+ Td \((\d+)\)Tj`).FindAllStringSubmatch(out, -1) {
+		numbers = append(numbers, match[1])
+	}
+
+	if len(numbers) != 70 {
+		t.Fatalf("expected 70 line numbers, got %d", len(numbers))
+	}
+	for index, number := range numbers {
+		if number != strconv.Itoa(index+1) {
+			t.Fatalf("expected line %d to be numbered %d, got %s", index, index+1, number)
+		}
+	}
+}
+
+func TestShowGrossUnitCost(t *testing.T) {
+	cases := []struct {
+		gross            bool
+		priceIncludesTax bool
+		unitCost         string
+		expected         string
+		title            string
+	}{
+		{gross: false, unitCost: "100", expected: "(\x80 100.00)Tj", title: "(Unit price)Tj"},
+		{gross: true, unitCost: "100", expected: "(\x80 120.00)Tj", title: "(Gross price)Tj"},
+		{gross: false, priceIncludesTax: true, unitCost: "120", expected: "(\x80 100.00)Tj", title: "(Unit price)Tj"},
+		{gross: true, priceIncludesTax: true, unitCost: "120", expected: "(\x80 120.00)Tj", title: "(Gross price)Tj"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{ShowGrossUnitCost: c.gross})
+		doc.AppendItem(&Item{
+			Name:             "Test",
+			UnitCost:         c.unitCost,
+			Quantity:         "3",
+			Tax:              &Tax{Percent: "20"},
+			PriceIncludesTax: c.priceIncludesTax,
+		})
+
+		out := buildToString(t, doc)
+		if !strings.Contains(out, c.title) {
+			t.Errorf("gross %t, price includes tax %t: expected title %q", c.gross, c.priceIncludesTax, c.title)
+		}
+		if !strings.Contains(out, c.expected) {
+			t.Errorf("gross %t, price includes tax %t: expected unit price %q", c.gross, c.priceIncludesTax, c.expected)
+		}
+
+		// Totals are the same
+		if !strings.Contains(out, "(\x80 360.00)Tj") {
+			t.Errorf("gross %t, price includes tax %t: expected total with tax 360.00", c.gross, c.priceIncludesTax)
+		}
+	}
+}
+
+func TestZeroDiscount(t *testing.T) {
+	cases := []struct {
+		discount *Discount
+		expected string
+	}{
+		{discount: &Discount{Amount: "0"}, expected: "(--)Tj"},
+		{discount: &Discount{Amount: "0.0"}, expected: "(--)Tj"},
+		{discount: &Discount{Amount: "0.00"}, expected: "(--)Tj"},
+		{discount: &Discount{Percent: "0"}, expected: "(--)Tj"},
+		{discount: &Discount{Percent: "10"}, expected: "(- 10 %)Tj"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}, Discount: c.discount})
+
+		out := buildToString(t, doc)
+		if !strings.Contains(out, c.expected) {
+			t.Errorf("discount %+v: expected %q", *c.discount, c.expected)
+		}
+		if c.expected == "(--)Tj" && strings.Contains(out, "(- \x80 0.00)Tj") {
+			t.Errorf("discount %+v: expected no zero discount amount", *c.discount)
+		}
+	}
+}
+
+func TestShipTo(t *testing.T) {
+	billing := &Contact{Name: "Test Customer", Address: &Address{Address: "1 Billing Street", PostalCode: "75001", City: "Paris"}}
+
+	cases := []struct {
+		name     string
+		shipTo   *Contact
+		expected bool
+	}{
+		{name: "billing only", shipTo: nil, expected: false},
+		{name: "same address", shipTo: &Contact{Name: "Test Customer", Address: &Address{Address: "1 Billing Street", PostalCode: "75001", City: "Paris"}}, expected: false},
+		{name: "different address", shipTo: &Contact{Name: "Test Warehouse", Address: &Address{Address: "9 Dock Road", PostalCode: "13002", City: "Marseille"}}, expected: true},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{})
+		doc.SetCustomer(billing)
+		doc.SetShipTo(c.shipTo)
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+		out := buildToString(t, doc)
+
+		if !strings.Contains(out, "(1 Billing Street)Tj") {
+			t.Errorf("%s: expected billing address", c.name)
+		}
+
+		for _, text := range []string{"(Bill to)Tj", "(Ship to)Tj", "(9 Dock Road)Tj"} {
+			if strings.Contains(out, text) != c.expected {
+				t.Errorf("%s: expected %s rendered %t", c.name, text, c.expected)
+			}
+		}
+
+		// Shipping recipient is rendered below the billing one
+		if c.expected && strings.Index(out, "(Ship to)Tj") < strings.Index(out, "(1 Billing Street)Tj") {
+			t.Errorf("%s: expected shipping recipient after billing one", c.name)
+		}
+	}
+}
+
+func TestPDFA(t *testing.T) {
+	doc := newTestDocument(t, &Options{PDFA: true, FontFile: "./testdata/DejaVuSansCondensed.ttf", Watermark: "DRAFT"})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "2"})
+
+	buf := &bytes.Buffer{}
+	if err := doc.Write(buf); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	out := buf.String()
+
+	for _, marker := range []string{
+		"<pdfaid:part>1</pdfaid:part>",
+		"<pdfaid:conformance>B</pdfaid:conformance>",
+		"/Type /Metadata /Subtype /XML",
+		"/OutputIntents [",
+		"/S /GTS_PDFA1",
+		"/DestOutputProfile",
+		"/Producer (go-invoice-generator)",
+		"/ID [<",
+	} {
+		if !strings.Contains(out, marker) {
+			t.Errorf("expected %q in pdf/a output", marker)
+		}
+	}
+
+	// XMP dates carry their time zone offset
+	if !regexp.MustCompile(`<xmp:CreateDate>\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(Z|[+-]\d{2}:\d{2})</xmp:CreateDate>`).MatchString(out) {
+		t.Errorf("expected RFC 3339 xmp create date")
+	}
+
+	// The updated catalog references the metadata stream
+	if !regexp.MustCompile(`/Metadata \d+ 0 R\n/OutputIntents`).MatchString(out) {
+		t.Errorf("expected catalog to reference metadata")
+	}
+
+	// Watermark is drawn without transparency
+	if strings.Contains(out, "/ExtGState") {
+		t.Errorf("expected no transparency in pdf/a output")
+	}
+
+	// Core fonts cannot be embedded
+	doc = newTestDocument(t, &Options{PDFA: true})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "2"})
+	if err := doc.Write(&bytes.Buffer{}); !errors.Is(err, ErrPDFAFont) {
+		t.Errorf("expected pdf/a font error, got %v", err)
+	}
+}
+
+func TestTaxTotalsByName(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "A", UnitCost: "100", Quantity: "2", Tax: &Tax{Name: "GST", Percent: "5"}})
+	doc.AppendItem(&Item{Name: "B", UnitCost: "50", Quantity: "4", Tax: &Tax{Name: "PST", Percent: "7"}})
+
+	out := buildToString(t, doc)
+
+	expected := []struct {
+		name string
+		tax  string
+	}{
+		{name: "GST", tax: "10"},
+		{name: "PST", tax: "14"},
+	}
+
+	lines := doc.TaxTotalsByName()
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d", len(expected), len(lines))
+	}
+	for n, e := range expected {
+		if lines[n].Name != e.name || lines[n].Tax.String() != e.tax {
+			t.Errorf("line %d: expected %+v, got %s %s", n, e, lines[n].Name, lines[n].Tax)
+		}
+	}
+
+	// Each name is rendered as a totals line, without the single tax line
+	for _, text := range []string{"(GST 5 %)Tj", "(\x80 10.00)Tj", "(PST 7 %)Tj", "(\x80 14.00)Tj"} {
+		if !strings.Contains(out, text) {
+			t.Errorf("expected %s in totals", text)
+		}
+	}
+	if strings.Contains(out, "(TAX)Tj") {
+		t.Errorf("expected no single tax line")
+	}
+}
+
+func TestQuantityFormat(t *testing.T) {
+	cases := []struct {
+		options  *Options
+		quantity string
+		expected string
+	}{
+		{options: &Options{CurrencySymbol: "$"}, quantity: "2.5", expected: "(2.5 hrs)Tj"},
+		{options: &Options{CurrencySymbol: "$"}, quantity: "1200", expected: "(1 200 hrs)Tj"},
+		{options: &Options{QuantityPrecision: 1}, quantity: "0.25", expected: "(0.3 hrs)Tj"},
+		{options: &Options{Locale: "fr-FR"}, quantity: "1.75", expected: "(1,75 hrs)Tj"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, c.options)
+		doc.AppendItem(&Item{Name: "Consulting", UnitCost: "80", Quantity: c.quantity, Unit: "hrs"})
+
+		out := buildToString(t, doc)
+		if !strings.Contains(out, c.expected) {
+			t.Errorf("quantity %s: expected %s in output", c.quantity, c.expected)
+		}
+		if strings.Contains(out, "($2.50 hrs)") {
+			t.Errorf("quantity %s: expected no currency symbol", c.quantity)
+		}
+	}
+
+	// Quantities rounded to integers
+	for quantity, expected := range map[string]string{"2.5": "(3 hrs)Tj", "1200": "(1 200 hrs)Tj"} {
+		doc := newTestDocument(t, &Options{})
+		doc.SetQuantityPrecision(0)
+		doc.AppendItem(&Item{Name: "Consulting", UnitCost: "80", Quantity: quantity, Unit: "hrs"})
+
+		if out := buildToString(t, doc); !strings.Contains(out, expected) {
+			t.Errorf("quantity %s with precision 0: expected %s in output", quantity, expected)
+		}
+	}
+}
+
+func TestShowFooterTotal(t *testing.T) {
+	doc := newTestDocument(t, &Options{ShowFooterTotal: true})
+	for index := 0; index < 30; index++ {
+		doc.AppendItem(&Item{Name: fmt.Sprintf("Item %d", index), UnitCost: "10", Quantity: "2"})
+	}
+
+	out := buildToString(t, doc)
+	if doc.pdf.PageCount() != 2 {
+		t.Fatalf("expected 2 pages, got %d", doc.pdf.PageCount())
+	}
+
+	// First page footer holds the total of its items, the last one the document total
+	matches := regexp.MustCompile(`\(Carried forward: [^ ]+ (\d+)\.00\)Tj`).FindAllStringSubmatch(out, -1)
+	if len(matches) != 1 {
+		t.Fatalf("expected a carried forward total on the first page, got %d", len(matches))
+	}
+	if total, _ := strconv.Atoi(matches[0][1]); total <= 0 || total >= 600 || total%20 != 0 {
+		t.Errorf("expected first page items total, got %d", total)
+	}
+
+	if strings.Count(out, "(TOTAL WITH TAX: \x80 600.00)Tj") != 1 {
+		t.Errorf("expected the document total on the last page footer")
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	date := time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		template string
+		sequence int
+		expected string
+	}{
+		{template: "INV-{YYYY}-{SEQ:0000}", sequence: 42, expected: "INV-2024-0042"},
+		{template: "{YY}{MM}{DD}-{SEQ}", sequence: 7, expected: "240307-7"},
+		{template: "FAC/{SEQ:000}", sequence: 12345, expected: "FAC/12345"},
+		{template: "ACME-{MM}/{YYYY}-{SEQ:00}", sequence: 3, expected: "ACME-03/2024-03"},
+	}
+
+	for _, c := range cases {
+		number, err := FormatNumber(c.template, date, c.sequence)
+		if err != nil {
+			t.Errorf("%s: got error %v", c.template, err)
+			continue
+		}
+		if number != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.template, c.expected, number)
+		}
+	}
+
+	for _, template := range []string{"INV-{SEQ:00x0}", "INV-{YEAR}", "INV-{SEQ"} {
+		if _, err := FormatNumber(template, date, 1); !errors.Is(err, ErrInvalidNumber) {
+			t.Errorf("%s: expected invalid number error, got %v", template, err)
+		}
+	}
+}
+
+func TestDocumentNumber(t *testing.T) {
+	doc := newTestDocument(t, &Options{DateLayout: "2006-01-02"})
+	doc.SetRef("")
+	doc.SetDate("2024-03-07")
+	doc.SetNumber("INV-{YYYY}-{SEQ:0000}", 42)
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "2"})
+
+	out := buildToString(t, doc)
+	if doc.Ref != "INV-2024-0042" {
+		t.Errorf("expected number expanded to ref, got %s", doc.Ref)
+	}
+	if !strings.Contains(out, "INV-2024-0042)Tj") {
+		t.Errorf("expected number rendered in header")
+	}
+
+	// Expanded numbers are valid refs
+	doc.SetNumber("INV-{YYYY}-{SEQ:0000000000000000000000000000}", 1)
+	if err := doc.Validate(); !errors.Is(err, ErrInvalidNumber) {
+		t.Errorf("expected invalid number error, got %v", err)
+	}
+}
+
+func TestItemDescriptionMaxLines(t *testing.T) {
+	description := strings.TrimSpace(strings.Repeat("lorem ipsum dolor sit amet ", 30))
+	linesRegexp := regexp.MustCompile(`\((lorem|ipsum|dolor|sit|amet)[^)]*\)Tj`)
+
+	for _, maxLines := range []int{0, 2} {
+		doc := newTestDocument(t, &Options{ItemDescriptionMaxLines: maxLines})
+		doc.AppendItem(&Item{Name: "Test", Description: description, UnitCost: "10", Quantity: "2"})
+
+		out := buildToString(t, doc)
+		lines := linesRegexp.FindAllString(out, -1)
+
+		if maxLines == 0 {
+			if len(lines) < 10 || strings.Contains(out, "\x85)Tj") {
+				t.Errorf("expected full description wrapping, got %d lines", len(lines))
+			}
+			continue
+		}
+
+		if len(lines) != maxLines {
+			t.Fatalf("expected %d description lines, got %d", maxLines, len(lines))
+		}
+		if !strings.HasSuffix(lines[maxLines-1], "\x85)Tj") {
+			t.Errorf("expected truncated description to end with an ellipsis, got %s", lines[maxLines-1])
+		}
+	}
+}
+
+func TestTaxBeforeDiscount(t *testing.T) {
+	cases := []struct {
+		taxBeforeDiscount bool
+		expectedItemTax   string
+		expectedTax       string
+	}{
+		{taxBeforeDiscount: false, expectedItemTax: "36", expectedTax: "32.4"},
+		{taxBeforeDiscount: true, expectedItemTax: "40", expectedTax: "40"},
+	}
+
+	for _, c := range cases {
+		doc := newTestDocument(t, &Options{TaxBeforeDiscount: c.taxBeforeDiscount})
+		doc.AppendItem(&Item{
+			Name:     "Test",
+			UnitCost: "100",
+			Quantity: "2",
+			Tax:      &Tax{Percent: "20"},
+			Discount: &Discount{Percent: "10"},
+		})
+		doc.SetDiscount(&Discount{Percent: "10"})
+
+		if err := doc.Validate(); err != nil {
+			t.Fatalf("got error %v", err)
+		}
+
+		if tax := doc.Items[0].TaxWithTotalDiscounted(); tax.String() != c.expectedItemTax {
+			t.Errorf("tax before discount %t: expected item tax %s, got %s", c.taxBeforeDiscount, c.expectedItemTax, tax)
+		}
+		if tax := doc.Tax(); tax.String() != c.expectedTax {
+			t.Errorf("tax before discount %t: expected document tax %s, got %s", c.taxBeforeDiscount, c.expectedTax, tax)
+		}
+	}
+}
+
+func TestAmountInWords(t *testing.T) {
+	cases := []struct {
+		amount   string
+		language string
+		code     string
+		expected string
+	}{
+		{amount: "1234.56", language: LanguageEnglish, code: "EUR", expected: "one thousand two hundred thirty-four euros and fifty-six cents"},
+		{amount: "1", language: LanguageEnglish, code: "USD", expected: "one dollar"},
+		{amount: "2000000.01", language: LanguageEnglish, code: "GBP", expected: "two million pounds and one penny"},
+		{amount: "-15", language: LanguageEnglish, code: "XYZ", expected: "minus fifteen XYZ"},
+		{amount: "1234.56", language: LanguageFrench, code: "EUR", expected: "mille deux cent trente-quatre euros et cinquante-six centimes"},
+		{amount: "71.80", language: LanguageFrench, code: "EUR", expected: "soixante et onze euros et quatre-vingts centimes"},
+		{amount: "280000", language: LanguageFrench, code: "CHF", expected: "deux cent quatre-vingt mille francs suisses"},
+		{amount: "1000000", language: LanguageFrench, code: "EUR", expected: "un million d'euros"},
+		{amount: "0.99", language: LanguageFrench, code: "EUR", expected: "zéro euro et quatre-vingt-dix-neuf centimes"},
+		{amount: "21", language: LanguageFrench, code: "GBP", expected: "vingt et une livres sterling"},
+	}
+
+	for _, c := range cases {
+		words := AmountInWords(decimal.RequireFromString(c.amount), c.language, c.code, 2)
+		if words != c.expected {
+			t.Errorf("%s %s: expected %q, got %q", c.amount, c.language, c.expected, words)
+		}
+	}
+
+	// Amounts are rounded to the currency precision before being spelled out
+	for _, c := range []struct {
+		amount    string
+		language  string
+		code      string
+		precision int
+		expected  string
+	}{
+		{amount: "1.996", language: LanguageEnglish, code: "EUR", precision: 2, expected: "two euros"},
+		{amount: "1.996", language: LanguageFrench, code: "EUR", precision: 2, expected: "deux euros"},
+		{amount: "1234.7", language: LanguageEnglish, code: "JPY", precision: 0, expected: "one thousand two hundred thirty-five JPY"},
+		{amount: "1.2345", language: LanguageEnglish, code: "KWD", precision: 3, expected: "one KWD and two hundred thirty-five cents"},
+		{amount: "1e20", language: LanguageEnglish, code: "EUR", precision: 2, expected: ""},
+		{amount: "-1e20", language: LanguageFrench, code: "EUR", precision: 2, expected: ""},
+	} {
+		words := AmountInWords(decimal.RequireFromString(c.amount), c.language, c.code, c.precision)
+		if words != c.expected {
+			t.Errorf("%s %s precision %d: expected %q, got %q", c.amount, c.language, c.precision, c.expected, words)
+		}
+	}
+
+	// Total rendered below totals
+	doc := newTestDocument(t, &Options{ShowTotalInWords: true})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "617.28", Quantity: "2"})
+
+	out := buildToString(t, doc)
+	for _, text := range []string{"(Amount in words: one thousand two hundred thirty-four euros and)Tj", "(fifty-six cents)Tj"} {
+		if !strings.Contains(out, text) {
+			t.Errorf("expected %s below totals", text)
+		}
+	}
+
+	// Not spelled in unsupported languages
+	doc = newTestDocument(t, &Options{ShowTotalInWords: true, Language: LanguageGerman})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "617.28", Quantity: "2"})
+	if out := buildToString(t, doc); strings.Contains(out, "(Betrag in Worten") || strings.Contains(out, "euros") {
+		t.Errorf("unexpected total in words in german document")
+	}
+
+	// Skipped for amounts too large to be spelled out
+	doc = newTestDocument(t, &Options{ShowTotalInWords: true})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100000000000000000000", Quantity: "1"})
+	if out := buildToString(t, doc); strings.Contains(out, "(Amount in words") {
+		t.Errorf("unexpected total in words for a too large amount")
+	}
+}
+
+func TestRowHook(t *testing.T) {
+	calls := []string{}
+	ys := []float64{}
+
+	doc := newTestDocument(t, &Options{
+		BeforeRowHook: func(doc *Document, item *Item, y float64) {
+			calls = append(calls, "before "+item.Name)
+		},
+		RowHook: func(doc *Document, item *Item, y float64) {
+			calls = append(calls, "after "+item.Name)
+			ys = append(ys, y)
+
+			doc.Pdf().SetFillColor(255, 0, 255)
+			doc.Pdf().Rect(BaseMargin-2, y, 1, 4, "F")
+		},
+	})
+	for _, name := range []string{"A", "B", "C"} {
+		doc.AppendItem(&Item{Name: name, UnitCost: "10", Quantity: "2"})
+	}
+
+	out := buildToString(t, doc)
+
+	expected := []string{"before A", "after A", "before B", "after B", "before C", "after C"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected hooks calls %v, got %v", expected, calls)
+	}
+	if len(ys) != 3 || ys[0] >= ys[1] || ys[1] >= ys[2] {
+		t.Errorf("expected lines top y in order, got %v", ys)
+	}
+
+	if strings.Count(out, "1.000 0.000 1.000 rg") != 3 {
+		t.Errorf("expected a hook rectangle per item")
+	}
+}
+
+func TestMargins(t *testing.T) {
+	margins := &Margins{Left: 40, Top: 50, Right: 30, Bottom: 30}
+	doc := newTestDocument(t, &Options{Margins: margins, ShowPageNumbers: true})
+	doc.SetCustomer(&Contact{Name: "Test Customer", Address: &Address{Address: "1 Main Street", City: "Paris", PostalCode: "75000"}})
+	doc.SetNotes("Thanks for your business")
+	doc.SetDefaultTax(&Tax{Percent: "20"})
+	doc.SetDiscount(&Discount{Percent: "5"})
+	for index := 0; index < 40; index++ {
+		doc.AppendItem(&Item{Name: fmt.Sprintf("Item %d", index), Description: "Description", UnitCost: "12.50", Quantity: "3"})
+	}
+
+	out := buildToString(t, doc)
+	if doc.pdf.PageCount() < 2 {
+		t.Fatalf("expected several pages, got %d", doc.pdf.PageCount())
+	}
+
+	// Positions in pt, pdf y from the page bottom
+	pt := 72 / 25.4
+	pageWidth, pageHeight := 595.28, 841.89
+	left, right := margins.Left*pt, pageWidth-margins.Right*pt
+	top, bottom := pageHeight-margins.Top*pt, margins.Bottom*pt
+
+	for _, match := range regexp.MustCompile(`BT ([0-9.]+) ([0-9.]+) Td`).FindAllStringSubmatch(out, -1) {
+		x, _ := strconv.ParseFloat(match[1], 64)
+		y, _ := strconv.ParseFloat(match[2], 64)
+		if x < left || x >= right || y > top || y < bottom {
+			t.Errorf("text at %.2f %.2f outside margins", x, y)
+		}
+	}
+
+	for _, match := range regexp.MustCompile(`([0-9.]+) ([0-9.]+) ([0-9.]+) (-?[0-9.]+) re`).FindAllStringSubmatch(out, -1) {
+		x, _ := strconv.ParseFloat(match[1], 64)
+		w, _ := strconv.ParseFloat(match[3], 64)
+		if x < left-0.01 || x+w > right+0.01 {
+			t.Errorf("rect at %.2f, %.2f wide outside margins", x, w)
+		}
+	}
+
+	// Margins leaving no room for content
+	for _, m := range []*Margins{
+		{Left: 110, Top: 20, Right: 100, Bottom: 10},
+		{Left: 10, Top: 150, Right: 10, Bottom: 150},
+	} {
+		doc := newTestDocument(t, &Options{Margins: m})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+		if err := doc.Validate(); !errors.Is(err, ErrInvalidMargins) {
+			t.Errorf("margins %+v: expected ErrInvalidMargins, got %v", m, err)
+		}
+	}
+}
+
+func TestItemTaxes(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{
+		Name:     "Fridge",
+		UnitCost: "500",
+		Quantity: "2",
+		Tax:      &Tax{Name: "VAT", Percent: "20"},
+		Taxes:    []*Tax{{Name: "Eco", Amount: "13"}},
+	})
+
+	out := buildToString(t, doc)
+
+	// 20% of 1000 and the eco-tax amount
+	item := doc.Items[0]
+	if tax := item.TaxWithTotalDiscounted(); tax.String() != "213" {
+		t.Errorf("expected item tax 213, got %s", tax)
+	}
+	if total := doc.TotalWithTax(); total.String() != "1213" {
+		t.Errorf("expected total 1213, got %s", total)
+	}
+
+	lines := doc.TaxTotalsByName()
+	if len(lines) != 2 || lines[0].Tax.String() != "200" || lines[1].Tax.String() != "13" {
+		t.Errorf("expected VAT 200 and Eco 13 tax lines, got %+v", lines)
+	}
+
+	// Taxes are stacked in the tax column
+	for _, text := range []string{"(VAT 20 %)Tj", "(Eco \x80 13.00)Tj"} {
+		if !strings.Contains(out, text) {
+			t.Errorf("expected %s in tax column", text)
+		}
+	}
+
+	// The single tax JSON is unchanged
+	var decoded Item
+	if err := json.Unmarshal([]byte(`{"name":"A","tax":{"percent":"20"}}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Tax == nil || decoded.Tax.Percent != "20" || len(decoded.Taxes) != 0 {
+		t.Errorf("expected single tax from json, got %+v", decoded)
+	}
+}
+
+func TestEstimateHeight(t *testing.T) {
+	for _, count := range []int{3, 60} {
+		doc := newTestDocument(t, &Options{})
+		for n := 0; n < count; n++ {
+			doc.AppendItem(&Item{Name: fmt.Sprintf("Item %d", n), UnitCost: "10", Quantity: "1"})
+		}
+
+		height, pages, err := doc.EstimateHeight()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := []byte(buildToString(t, doc))
+		rendered := len(regexp.MustCompile(`/Type /Page\n`).FindAll(out, -1))
+		if pages != rendered {
+			t.Errorf("%d items: expected %d estimated pages, got %d", count, rendered, pages)
+		}
+
+		// The items table grows with its lines, beyond a page height once split
+		if height <= 0 || (count == 3 && height > doc.maxPageHeight()) || (count == 60 && height < doc.maxPageHeight()) {
+			t.Errorf("%d items: unexpected items height %.1f", count, height)
+		}
+	}
+}
+
+func TestValidityDate(t *testing.T) {
+	doc, err := New(Quotation, &Options{DateLayout: "2006-01-02"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.SetRef("ref").SetCompany(&Contact{Name: "Test Company"}).SetCustomer(&Contact{Name: "Test Customer"})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.SetDate("2021-03-01").SetValidityDate("2021-03-31")
+
+	now := time.Date(2021, 3, 31, 18, 30, 0, 0, time.UTC)
+	if doc.IsExpired(now) {
+		t.Errorf("expected quotation valid until today not to be expired")
+	}
+	if !doc.IsExpired(now.AddDate(0, 0, 1)) {
+		t.Errorf("expected quotation valid until yesterday to be expired")
+	}
+
+	// Past validity date, rendered with the expired label
+	out := buildToString(t, doc)
+	for _, expected := range []string{"(Valid until: 2021-03-31)", "(EXPIRED)"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output", expected)
+		}
+	}
+
+	// Future validity date
+	doc.SetValidityDate(time.Now().AddDate(0, 1, 0).Format("2006-01-02"))
+	out = rebuildToString(t, doc)
+	if !strings.Contains(out, "(Valid until: ") || strings.Contains(out, "(EXPIRED)") {
+		t.Errorf("expected validity date without expired label")
+	}
+
+	// Invoices are never expired
+	doc.Type = Invoice
+	if doc.IsExpired(now.AddDate(1, 0, 0)) {
+		t.Errorf("expected invoice not to be expired")
+	}
+
+	doc.SetValidityDate("31/03/2021")
+	if _, err := doc.Build(); !errors.Is(err, ErrInvalidValidityDate) {
+		t.Errorf("expected ErrInvalidValidityDate, got %v", err)
+	}
+}
+
+func TestPassword(t *testing.T) {
+	doc := newTestDocument(t, &Options{Password: &Password{User: "user", Owner: "owner", AllowPrint: true}})
+	doc.AppendItem(&Item{Name: "Confidential", UnitCost: "10", Quantity: "1"})
+
+	out := buildToString(t, doc)
+
+	// Encryption dictionary, printing allowed only
+	for _, expected := range []string{"/Encrypt ", "/Filter /Standard", "/P -60\n"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output", expected)
+		}
+	}
+	if strings.Contains(out, "(Confidential)Tj") {
+		t.Errorf("expected encrypted texts")
+	}
+
+	doc.Options.Password = nil
+	out = rebuildToString(t, doc)
+	if strings.Contains(out, "/Encrypt ") || !strings.Contains(out, "(Confidential)Tj") {
+		t.Errorf("expected unencrypted document without password")
+	}
+}
+
+func TestShowContinuedMarkers(t *testing.T) {
+	doc := newTestDocument(t, &Options{ShowContinuedMarkers: true})
+	for n := 0; n < 40; n++ {
+		doc.AppendItem(&Item{Name: fmt.Sprintf("Item %d", n), UnitCost: "10", Quantity: "1"})
+	}
+
+	out := buildToString(t, doc)
+
+	// Once below the first page table, once below the second page header
+	for _, marker := range []string{"(continued\x85)Tj", "(\x85continued)Tj"} {
+		if count := strings.Count(out, marker); count != 1 {
+			t.Errorf("expected %s once, got %d", marker, count)
+		}
+	}
+
+	doc.Options.ShowContinuedMarkers = false
+	if out := rebuildToString(t, doc); strings.Contains(out, "continued") {
+		t.Errorf("expected no continued markers")
+	}
+}
+
+func TestTotalsAllocate(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1"})
+
+	totals, err := doc.Totals()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	third := decimal.RequireFromString("33.33")
+	allocations, err := totals.Allocate([]decimal.Decimal{third, third, third})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The last allocation absorbs the rounding
+	sum := decimal.Zero
+	for index, expected := range []string{"33.33", "33.33", "33.34"} {
+		if !allocations[index].Equal(decimal.RequireFromString(expected)) {
+			t.Errorf("allocation %d: expected %s, got %s", index, expected, allocations[index])
+		}
+		sum = sum.Add(allocations[index])
+	}
+	if !sum.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("expected allocations to sum to 100.00, got %s", sum)
+	}
+
+	for _, percentages := range [][]decimal.Decimal{
+		{},
+		{decimal.NewFromInt(50), decimal.NewFromInt(20)},
+		{decimal.NewFromInt(110), decimal.NewFromInt(-10)},
+	} {
+		if _, err := totals.Allocate(percentages); !errors.Is(err, ErrInvalidAllocation) {
+			t.Errorf("%v: expected ErrInvalidAllocation, got %v", percentages, err)
+		}
+	}
+}
+
+func TestShowAmountDueBox(t *testing.T) {
+	doc := newTestDocument(t, &Options{ShowAmountDueBox: true, AmountDueBoxColor: []int{255, 0, 0}})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.SetAmountPaid("20")
+
+	out := buildToString(t, doc)
+
+	// Filled and bordered box, with the balance due in large bold text
+	box := regexp.MustCompile(`1\.000 0\.000 0\.000 rg\n.*\n.*\n[\d.]+ [\d.]+ 226\.77 -39\.69 re B\n` +
+		`BT /\w+ 14\.00 Tf ET\nq [\d.]+ g BT [\d.]+ [\d.]+ Td \(AMOUNT DUE\)Tj ET Q\nq [\d.]+ g BT [\d.]+ [\d.]+ Td \([^ ]+ 100\.00\)Tj ET Q`)
+	if !box.MatchString(out) {
+		t.Errorf("expected amount due box with balance due")
+	}
+
+	doc.Options.ShowAmountDueBox = false
+	if out := rebuildToString(t, doc); strings.Contains(out, "(AMOUNT DUE)Tj") {
+		t.Errorf("expected no amount due box")
+	}
+}
+
+func TestDocumentJSON(t *testing.T) {
+	doc := newTestDocument(t, &Options{Language: LanguageFrench, DateLayout: "2006-01-02", ShowTaxSummary: true, ZebraRows: true})
+	doc.SetCurrency(Currency{Symbol: "¥", Code: "JPY", Precision: 0, Thousand: ",", Decimal: ".", Position: CurrencyPositionBefore})
+	doc.SetDate("2021-03-01").SetDueDate("2021-03-31").SetNotes("Merci")
+	doc.SetCompany(&Contact{Name: "Test Company", Address: &Address{Address: "1 rue du Test", City: "Paris"}, VATNumber: "FR123"})
+	doc.SetDefaultTax(&Tax{Percent: "20"})
+	doc.SetDiscount(&Discount{Percent: "10"})
+	doc.Shipping = &Shipping{Amount: "500", Tax: &Tax{Percent: "10"}}
+	doc.AppendItem(&Item{Name: "A", UnitCost: "1200", Quantity: "2", Discount: &Discount{Amount: "100"}})
+	doc.AppendItem(&Item{Name: "B", UnitCost: "300", Quantity: "1.5", Unit: "kg", Taxes: []*Tax{{Name: "Eco", Amount: "20"}}})
+
+	creationDate := regexp.MustCompile(`/CreationDate \(D:[^)]*\)`)
+	out := creationDate.ReplaceAllString(buildToString(t, doc), "")
+
+	buf := &bytes.Buffer{}
+	if err := doc.WriteJSON(buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.String()
+
+	read, err := ReadDocumentJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Items without tax are exported without the default tax, which applies again once changed
+	if read.Items[0].Tax != nil {
+		t.Errorf("expected item without tax, got %+v", read.Items[0].Tax)
+	}
+
+	// Zero values are kept over defaults
+	if read.Options.CurrencyPrecision != 0 {
+		t.Errorf("expected currency precision 0, got %d", read.Options.CurrencyPrecision)
+	}
+
+	// Same pdf once rendered again, and same json
+	if readOut := creationDate.ReplaceAllString(buildToString(t, read), ""); readOut != out {
+		t.Errorf("expected the read document to render the same pdf")
+	}
+
+	buf.Reset()
+	if err := read.WriteJSON(buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != data {
+		t.Errorf("expected the same json, got %s\ninstead of %s", buf.String(), data)
+	}
+
+	read.SetDefaultTax(&Tax{Percent: "10"})
+	rebuildToString(t, read)
+	if tax := read.Items[0].effectiveTax(); tax == nil || tax.Percent != "10" {
+		t.Errorf("expected the new default tax after a rebuild, got %+v", tax)
+	}
+
+	if _, err := ReadDocumentJSON(strings.NewReader(`{"type":`)); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON, got %v", err)
+	}
+	if _, err := ReadDocumentJSON(strings.NewReader(`{"type":"BILL"}`)); !errors.Is(err, ErrInvalidDocumentType) {
+		t.Errorf("expected ErrInvalidDocumentType, got %v", err)
+	}
+}
+
+func TestItemCurrency(t *testing.T) {
+	doc := newTestDocument(t, &Options{CurrencyCode: "EUR"})
+	doc.AppendItem(&Item{
+		Name:     "Imported",
+		UnitCost: "100",
+		Quantity: "2",
+		Currency: &ItemCurrency{Code: "USD", Symbol: "$", Rate: "0.92", ShowOriginal: true},
+		Tax:      &Tax{Percent: "10"},
+	})
+	doc.AppendItem(&Item{Name: "Local", UnitCost: "50", Quantity: "1"})
+
+	out := buildToString(t, doc)
+
+	// USD line converted to 184 EUR for totals
+	if total := doc.TotalWithoutTax(); total.String() != "234" {
+		t.Errorf("expected total without tax 234, got %s", total)
+	}
+	if tax := doc.Tax(); tax.String() != "18.4" {
+		t.Errorf("expected tax 18.4, got %s", tax)
+	}
+
+	// Converted unit cost, with the original total below the name
+	for _, text := range []string{"(\x80 92.00)Tj", "($200.00 \\(1 USD = 0.92 EUR\\))Tj"} {
+		if !strings.Contains(out, text) {
+			t.Errorf("expected %s in output", text)
+		}
+	}
+
+	// Decimal items show their original total from the decimals, not the export strings
+	doc = newTestDocument(t, &Options{CurrencyCode: "EUR"})
+	item := NewItem("Imported", decimal.NewFromInt(100), decimal.NewFromInt(2))
+	item.UnitCost, item.Quantity = "", ""
+	item.Currency = &ItemCurrency{Code: "USD", Symbol: "$", Rate: "0.92", ShowOriginal: true}
+	doc.AppendItem(item)
+	if out := buildToString(t, doc); !strings.Contains(out, "($200.00 \\(1 USD = 0.92 EUR\\))Tj") {
+		t.Errorf("expected original total of decimal item in output")
+	}
+
+	doc.Items[0].Currency.Rate = "0"
+	if _, err := doc.Build(); !errors.Is(err, ErrInvalidExchangeRate) {
+		t.Errorf("expected ErrInvalidExchangeRate, got %v", err)
+	}
+}
+
+func TestTextColorContrast(t *testing.T) {
+	for _, c := range []struct {
+		color []int
+		dark  bool
+	}{
+		{color: []int{0, 0, 0}, dark: true},
+		{color: []int{41, 65, 122}, dark: true},
+		{color: []int{255, 255, 255}, dark: false},
+		{color: []int{255, 204, 0}, dark: false},
+		{color: []int{128, 128, 128}, dark: false},
+	} {
+		if dark := isDark(c.color); dark != c.dark {
+			t.Errorf("%v: expected dark %t, got %t", c.color, c.dark, dark)
+		}
+	}
+
+	// White title on a dark band, base text color on a light band
+	for _, c := range []struct {
+		band     []int
+		expected string
+	}{
+		{band: []int{20, 20, 60}, expected: "q 1.000 g BT "},
+		{band: []int{250, 230, 120}, expected: "q 0.137 g BT "},
+	} {
+		doc := newTestDocument(t, &Options{ShowHeaderBand: true, HeaderBandColor: c.band})
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+		out := buildToString(t, doc)
+		title := regexp.MustCompile(`q [\d.]+ g BT [\d.]+ [\d.]+ Td \(INVOICE\)Tj`).FindString(out)
+		if !strings.HasPrefix(title, c.expected) {
+			t.Errorf("%v band: expected title starting with %q, got %q", c.band, c.expected, title)
+		}
+	}
+}
+
+func TestRemittanceSlip(t *testing.T) {
+	doc := newTestDocument(t, &Options{DateLayout: "2006-01-02"})
+	doc.SetCompany(&Contact{Name: "Test Company", Address: &Address{Address: "1 Test street", City: "Paris"}})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.SetAmountPaid("20").SetDueDate("2021-03-31")
+
+	out := buildToString(t, doc)
+	amountDue := regexp.MustCompile(`\([^ ]+ 100\.00\)Tj`)
+	totalsCount := len(amountDue.FindAllString(out, -1))
+
+	doc.SetRemittanceSlip(&RemittanceSlip{AccountNumber: "C-42"})
+	out = rebuildToString(t, doc)
+
+	for _, text := range []string{
+		"(Please detach and return this portion with your payment)Tj",
+		"(REMITTANCE)Tj",
+		"(Make payments payable to)Tj",
+		"(Test Company)Tj",
+		"(1 Test street)Tj",
+		"(C-42)Tj",
+		"(2021-03-31)Tj",
+		"(AMOUNT DUE)Tj",
+		"(Amount enclosed)Tj",
+	} {
+		if !strings.Contains(out, text) {
+			t.Errorf("expected remittance slip text %s", text)
+		}
+	}
+
+	// Dashed cut line across the page
+	if !regexp.MustCompile(`\[[\d.]+ [\d.]+\] 0\.00 d\n0\.00 [\d.]+ m 595\.28 [\d.]+ l S`).MatchString(out) {
+		t.Errorf("expected dashed cut line")
+	}
+
+	// Balance due repeated on the slip
+	if count := len(amountDue.FindAllString(out, -1)); count != totalsCount+1 {
+		t.Errorf("expected balance due repeated on the slip, got %d occurrences, %d without slip", count, totalsCount)
+	}
+
+	doc.SetRemittanceSlip(nil)
+	if out := rebuildToString(t, doc); strings.Contains(out, "(REMITTANCE)Tj") {
+		t.Errorf("expected no remittance slip")
+	}
+}
+
+func TestTaxRateFormat(t *testing.T) {
+	doc := newTestDocument(t, &Options{TaxRateFormat: "VAT %s%%"})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+
+	out := buildToString(t, doc)
+	if !strings.Contains(out, "(VAT 20%)Tj") {
+		t.Errorf("expected tax rate rendered as VAT 20%%")
+	}
+	if strings.Contains(out, "(20 %)Tj") {
+		t.Errorf("expected no default tax rate")
+	}
+
+	doc.Options.TaxRateFormat = "@ %.2[2]f"
+	out = rebuildToString(t, doc)
+	if !strings.Contains(out, "(@ 0.20)Tj") {
+		t.Errorf("expected tax rate rendered as a fraction")
+	}
+}
+
+func TestNewItem(t *testing.T) {
+	item := NewItem("Test", decimal.RequireFromString("12.5"), decimal.NewFromInt(3)).
+		SetTaxPercent(decimal.NewFromInt(20)).
+		SetDiscountPercent(decimal.NewFromInt(10))
+	if item.UnitCost != "12.5" || item.Quantity != "3" || item.Tax.Percent != "20" || item.Discount.Percent != "10" {
+		t.Errorf("expected decimals strings set for exports, got %q %q", item.UnitCost, item.Quantity)
+	}
+
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(item)
+	doc.AppendItem(NewItem("Other", decimal.NewFromInt(100), decimal.NewFromInt(1)).SetTaxAmount(decimal.NewFromInt(5)))
+
+	out := buildToString(t, doc)
+	for _, text := range []string{"(Test)Tj", "(Other)Tj", "(20 %)Tj"} {
+		if !strings.Contains(out, text) {
+			t.Errorf("expected decimal items rendered with %s", text)
+		}
+	}
+
+	// 12.5 × 3 - 10 % = 33.75, plus 20 % tax, then 100 plus 5
+	if total := doc.TotalWithTax(); !total.Equal(decimal.RequireFromString("145.5")) {
+		t.Errorf("expected total with tax 145.5, got %s", total)
+	}
+
+	// Decimals are not parsed from their strings
+	item.UnitCost = "invalid"
+	if err := item.Prepare(); err != nil {
+		t.Errorf("expected decimal unit cost, got error %v", err)
+	}
+	if total := item.TotalWithoutTaxAndWithDiscount(); !total.Equal(decimal.RequireFromString("33.75")) {
+		t.Errorf("expected total 33.75, got %s", total)
+	}
+}
+
+func TestTitleAndSubtitle(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.SetTitle("TAX INVOICE").SetSubtitle("Monthly subscription, March 2024")
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1"})
+
+	out := buildToString(t, doc)
+	title := regexp.MustCompile(`BT /\w+ 14\.00 Tf ET\n(q [\d.]+ g )?BT [\d.]+ [\d.]+ Td \(TAX INVOICE\)Tj`)
+	subtitle := regexp.MustCompile(`BT /\w+ 7\.00 Tf ET\n(q [\d.]+ g )?BT [\d.]+ [\d.]+ Td \(Monthly subscription, March 2024\)Tj`)
+	if !title.MatchString(out) || !subtitle.MatchString(out) {
+		t.Errorf("expected custom title and smaller subtitle")
+	}
+	if strings.Contains(out, "(INVOICE)Tj") {
+		t.Errorf("expected type title replaced")
+	}
+
+	// Header band
+	doc.Options.ShowHeaderBand = true
+	out = rebuildToString(t, doc)
+	if !strings.Contains(out, "(TAX INVOICE)Tj") || !strings.Contains(out, "(Monthly subscription, March 2024)Tj") {
+		t.Errorf("expected title and subtitle in header band")
+	}
+
+	doc.SetTitle("").SetSubtitle("")
+	if out := rebuildToString(t, doc); !strings.Contains(out, "(INVOICE)Tj") || strings.Contains(out, "Monthly subscription") {
+		t.Errorf("expected type title without subtitle")
+	}
+}
+
+func TestPrepareAll(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Good", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	if err := doc.PrepareAll(); err != nil {
+		t.Fatalf("expected prepared document, got error %v", err)
+	}
+
+	doc.AppendItem(&Item{Name: "Bad", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "twenty"}})
+	doc.AppendItem(&Item{Name: "Worse", UnitCost: "abc", Quantity: "1"})
+
+	err := doc.PrepareAll()
+	if !errors.Is(err, ErrInvalidDecimal) {
+		t.Fatalf("expected invalid decimal error, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), `item 1 "Bad": tax:`) {
+		t.Errorf("expected first bad item error with context, got %v", err)
+	}
+
+	if _, err := doc.Build(); !errors.Is(err, ErrInvalidDecimal) {
+		t.Errorf("expected build error, got %v", err)
+	}
+
+	// Nil items fail without panicking, reverse charge included
+	doc = newTestDocument(t, &Options{})
+	doc.ReverseCharge = true
+	doc.AppendItem(&Item{Name: "Good", UnitCost: "100", Quantity: "1"})
+	doc.Items = append(doc.Items, nil)
+	if err := doc.PrepareAll(); !errors.Is(err, ErrNilItem) || err.Error() != "item 1: nil" {
+		t.Errorf("expected nil item error, got %v", err)
+	}
+}
+
+func TestItemSeparator(t *testing.T) {
+	doc := newTestDocument(t, &Options{ItemSeparator: ItemSeparatorThin, ItemSeparatorColor: []int{255, 0, 0}})
+	doc.AppendItem(&Item{Name: "First", UnitCost: "100", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "Second line with a long description", Description: "Line 1\nLine 2\nLine 3", UnitCost: "100", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "Third", UnitCost: "100", Quantity: "1"})
+
+	// Red rules across the table, below each line
+	separator := regexp.MustCompile(`1\.000 0\.000 0\.000 RG\n0\.28 w\n[\d.]+ ([\d.]+) m [\d.]+ [\d.]+ l S`)
+	matches := separator.FindAllStringSubmatch(buildToString(t, doc), -1)
+	if len(matches) != 3 {
+		t.Fatalf("expected 3 separators, got %d", len(matches))
+	}
+
+	// The second line is taller, so are the gaps between separators
+	ys := []float64{}
+	for _, match := range matches {
+		y, _ := strconv.ParseFloat(match[1], 64)
+		ys = append(ys, y)
+	}
+	if ys[0]-ys[1] <= ys[1]-ys[2] {
+		t.Errorf("expected separators below multi-line heights, got %v", ys)
+	}
+
+	doc.Options.ItemSeparator = ItemSeparatorDashed
+	if out := rebuildToString(t, doc); !regexp.MustCompile(`\[[\d.]+ [\d.]+\] 0\.00 d\n[\d.]+ [\d.]+ m [\d.]+ [\d.]+ l S`).MatchString(out) {
+		t.Errorf("expected dashed separators")
+	}
+
+	doc.Options.ItemSeparator = ""
+	if out := rebuildToString(t, doc); separator.MatchString(out) {
+		t.Errorf("expected no separator by default")
+	}
+}
+
+func TestProgressBilling(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Milestone 2", UnitCost: "2500", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.SetProgressBilling(&ProgressBilling{ContractValue: "10000", PreviouslyInvoiced: "4000"})
+
+	out := buildToString(t, doc)
+	if remaining := doc.ProgressRemaining(); !remaining.Equal(decimal.NewFromInt(3000)) {
+		t.Errorf("expected 3000 remaining, got %s", remaining)
+	}
+
+	for _, row := range [][]string{
+		{"Contract value", "10 000.00", "100.00 %"},
+		{"Previously invoiced", "4 000.00", "40.00 %"},
+		{"This invoice", "3 000.00", "30.00 %"},
+		{"Remaining to invoice", "3 000.00", "30.00 %"},
+	} {
+		line := regexp.MustCompile(`\(` + row[0] + `\)Tj ET Q\nq [\d.]+ g BT [\d.]+ [\d.]+ Td \([^ ]+ ` + row[1] + `\)Tj ET Q\nq [\d.]+ g BT [\d.]+ [\d.]+ Td \(` + row[2] + `\)Tj`)
+		if !line.MatchString(out) {
+			t.Errorf("expected progress billing row %v", row)
+		}
+	}
+
+	doc.ProgressBilling.ContractValue = "0"
+	if _, err := doc.Build(); !errors.Is(err, ErrInvalidContractValue) {
+		t.Errorf("expected invalid contract value error, got %v", err)
+	}
+}
+
+func TestShowRefBarcode(t *testing.T) {
+	doc := newTestDocument(t, &Options{ShowRefBarcode: true})
+	doc.SetRef("INV-2024-001")
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1"})
+
+	// Barcode image drawn below the metas
+	image := regexp.MustCompile(`q 141\.73\d* 0 0 28\.34\d* [\d.]+ [\d.]+ cm /I\w+ Do Q`)
+	out := buildToString(t, doc)
+	if !strings.Contains(out, "/Subtype /Image") || !image.MatchString(out) {
+		t.Errorf("expected ref barcode image")
+	}
+
+	doc.Options.ShowRefBarcode = false
+	if out := rebuildToString(t, doc); strings.Contains(out, "/Subtype /Image") {
+		t.Errorf("expected no ref barcode image")
+	}
+}
+
+func TestShowGeneratedAt(t *testing.T) {
+	doc := newTestDocument(t, &Options{
+		ShowGeneratedAt:     true,
+		GeneratedAtTimezone: "Asia/Kolkata",
+		GeneratedAtLayout:   "2006-01-02 15:04 -07:00",
+	})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1"})
+
+	match := regexp.MustCompile(`\(Generated on (\d{4}-\d{2}-\d{2} \d{2}:\d{2} \+05:30)\)Tj`).FindStringSubmatch(buildToString(t, doc))
+	if match == nil {
+		t.Fatalf("expected generation timestamp in the configured timezone")
+	}
+
+	generatedAt, err := time.Parse("2006-01-02 15:04 -07:00", match[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(generatedAt); elapsed < 0 || elapsed > 2*time.Minute {
+		t.Errorf("expected build time, got %s", match[1])
+	}
+
+	doc.Options.GeneratedAtTimezone = "Nowhere/Invalid"
+	if _, err := doc.Build(); !errors.Is(err, ErrInvalidTimezone) {
+		t.Errorf("expected invalid timezone error, got %v", err)
+	}
+}
+
+func TestItemHighlightColor(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "First", UnitCost: "100", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "Backordered", UnitCost: "100", Quantity: "1", HighlightColor: []int{255, 0, 0}})
+	doc.AppendItem(&Item{Name: "Third", UnitCost: "100", Quantity: "1"})
+
+	// A single tinted rect, behind the highlighted line name
+	highlight := regexp.MustCompile(`1\.000 0\.000 0\.000 rg\n[\d.]+ ([\d.]+) [\d.]+ (-[\d.]+) re f`)
+	out := buildToString(t, doc)
+	matches := highlight.FindAllStringSubmatch(out, -1)
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 highlighted line, got %d", len(matches))
+	}
+
+	top, _ := strconv.ParseFloat(matches[0][1], 64)
+	height, _ := strconv.ParseFloat(matches[0][2], 64)
+	name := regexp.MustCompile(`BT [\d.]+ ([\d.]+) Td \(Backordered\)Tj`).FindStringSubmatch(out)
+	if name == nil {
+		t.Fatalf("expected highlighted line name")
+	}
+	if y, _ := strconv.ParseFloat(name[1], 64); y > top || y < top+height {
+		t.Errorf("expected highlighted line name in its rect, got %.2f outside %.2f to %.2f", y, top, top+height)
+	}
+
+	// Highlight wins over the zebra stripe of the same line
+	doc.Options.ZebraRows = true
+	out = rebuildToString(t, doc)
+	if len(highlight.FindAllString(out, -1)) != 1 || strings.Contains(out, "0.961 0.961 0.961 rg") {
+		t.Errorf("expected highlight instead of zebra stripe")
+	}
+}
+
+func TestEInvoiceMultipleTaxes(t *testing.T) {
+	doc := newTestDocument(t, &Options{})
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}, Taxes: []*Tax{{Name: "Eco", Percent: "5"}}})
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := doc.MarshalFacturX(); !errors.Is(err, ErrFacturXMultipleTaxes) {
+		t.Errorf("expected ErrFacturXMultipleTaxes, got %v", err)
+	}
+	if _, err := doc.MarshalUBL(); !errors.Is(err, ErrUBLMultipleTaxes) {
+		t.Errorf("expected ErrUBLMultipleTaxes, got %v", err)
+	}
+}
+
+func TestEInvoiceCurrencyPrecision(t *testing.T) {
+	doc := newTestDocument(t, &Options{CurrencyCode: "JPY"})
+	doc.SetCurrencyPrecision(0)
+	doc.AppendItem(&Item{Name: "Test", UnitCost: "1234", Quantity: "1", Tax: &Tax{Percent: "10"}})
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Amounts are rounded as in the pdf, 123.4 tax to 123
+	facturX, err := doc.MarshalFacturX()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	for _, expected := range []string{"<ram:LineTotalAmount>1234</ram:LineTotalAmount>", `<ram:TaxTotalAmount currencyID="JPY">123</ram:TaxTotalAmount>`, "<ram:GrandTotalAmount>1357</ram:GrandTotalAmount>"} {
+		if !strings.Contains(string(facturX), expected) {
+			t.Errorf("expected %q in factur-x xml", expected)
+		}
+	}
+
+	ubl, err := doc.MarshalUBL()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	for _, expected := range []string{`<cbc:TaxAmount currencyID="JPY">123</cbc:TaxAmount>`, `<cbc:PayableAmount currencyID="JPY">1357</cbc:PayableAmount>`} {
+		if !strings.Contains(string(ubl), expected) {
+			t.Errorf("expected %q in ubl xml", expected)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package generator
+
+import "fmt"
+
+// contentTop returns the top y of the first page contacts and metas, below the header band when shown
+func (doc *Document) contentTop() float64 {
+	if doc.Options.ShowHeaderBand {
+		return HeaderBandHeight + 5
+	}
+
+	return doc.margins().Top
+}
+
+// refString returns the document ref with its title
+func (doc *Document) refString() string {
+	return fmt.Sprintf("%s: %s", doc.Options.TextRefTitle, doc.Ref)
+}
+
+// appendHeaderBand to document, a full page width band with the document title, subtitle and ref
+func (doc *Document) appendHeaderBand() {
+	pageWidth, _ := doc.pdf.GetPageSize()
+
+	// Draw band
+	doc.pdf.SetFillColor(doc.Options.HeaderBandColor[0], doc.Options.HeaderBandColor[1], doc.Options.HeaderBandColor[2])
+	doc.pdf.Rect(0, 0, pageWidth, HeaderBandHeight, "F")
+
+	// Texts contrast with the band unless HeaderBandTextColor is set
+	textColor := doc.Options.HeaderBandTextColor
+	if len(textColor) == 0 {
+		textColor = doc.textColorOn(doc.Options.HeaderBandColor)
+	}
+	doc.pdf.SetTextColor(textColor[0], textColor[1], textColor[2])
+
+	// Draw title
+	width := doc.contentWidth() / 2
+	doc.pdf.SetXY(doc.rtlX(doc.margins().Left, width), 0)
+	doc.setFont(doc.Options.TitleFont, doc.Options.BoldFont, "B", 16)
+	if len(doc.Subtitle) == 0 {
+		doc.pdf.CellFormat(width, HeaderBandHeight, doc.encodeString(doc.title()), "0", 0, doc.rtlAlign("L"), false, 0, "")
+	} else {
+		// Title and subtitle centered together in the band
+		doc.pdf.SetXY(doc.rtlX(doc.margins().Left, width), HeaderBandHeight/2-6)
+		doc.pdf.CellFormat(width, 8, doc.encodeString(doc.title()), "0", 0, doc.rtlAlign("L"), false, 0, "")
+		doc.pdf.SetXY(doc.rtlX(doc.margins().Left, width), HeaderBandHeight/2+2)
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		doc.pdf.CellFormat(width, 4, doc.encodeString(doc.Subtitle), "0", 0, doc.rtlAlign("L"), false, 0, "")
+	}
+
+	// Draw ref
+	doc.pdf.SetXY(doc.rtlX(doc.margins().Left+width, width), 0)
+	doc.pdf.SetFont(doc.Options.Font, "", LargeTextFontSize)
+	doc.pdf.CellFormat(width, HeaderBandHeight, doc.encodeString(doc.refString()), "0", 0, doc.rtlAlign("R"), false, 0, "")
+
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+}
@@ -29,15 +29,15 @@ func (hf *HeaderFooter) applyHeader(doc *Document) error {
 	}
 
 	if !hf.UseCustomFunc {
-		doc.pdf.SetHeaderFunc(func() {
+		doc.pdf.SetHeaderFunc(doc.withWatermark(func() {
 			currentY := doc.pdf.GetY()
 			currentX := doc.pdf.GetX()
 
 			doc.pdf.SetTopMargin(HeaderMarginTop)
 			doc.pdf.SetY(HeaderMarginTop)
 
-			doc.pdf.SetLeftMargin(BaseMargin)
-			doc.pdf.SetRightMargin(BaseMargin)
+			doc.pdf.SetLeftMargin(doc.margins().Left)
+			doc.pdf.SetRightMargin(doc.margins().Right)
 
 			// Parse Text as html (simple)
 			doc.pdf.SetFont(doc.Options.Font, "", hf.FontSize)
@@ -47,26 +47,13 @@ func (hf *HeaderFooter) applyHeader(doc *Document) error {
 
 			// Apply pagination
 			if !hf.Pagination {
-				doc.pdf.AliasNbPages("") // Will replace {nb} with total page count
-				doc.pdf.SetY(HeaderMarginTop + 8)
-				doc.pdf.SetX(195)
-				doc.pdf.CellFormat(
-					10,
-					5,
-					doc.encodeString(fmt.Sprintf("Page %d/{nb}", doc.pdf.PageNo())),
-					"0",
-					0,
-					"R",
-					false,
-					0,
-					"",
-				)
+				doc.appendPagination(HeaderMarginTop + 8)
 			}
 
 			doc.pdf.SetY(currentY)
 			doc.pdf.SetX(currentX)
-			doc.pdf.SetMargins(BaseMargin, BaseMarginTop, BaseMargin)
-		})
+			doc.setMargins()
+		}))
 	}
 
 	return nil
@@ -84,37 +71,58 @@ func (hf *HeaderFooter) applyFooter(doc *Document) error {
 			currentX := doc.pdf.GetX()
 
 			doc.pdf.SetTopMargin(HeaderMarginTop)
-			doc.pdf.SetY(287 - HeaderMarginTop)
+			doc.pdf.SetY(doc.footerY() - HeaderMarginTop)
 
 			// Parse Text as html (simple)
-			doc.pdf.SetFont(doc.Options.Font, "", hf.FontSize)
+			doc.setFont(doc.Options.FooterFont, doc.Options.Font, "", hf.FontSize)
 			_, lineHt := doc.pdf.GetFontSize()
 			html := doc.pdf.HTMLBasicNew()
 			html.Write(lineHt, doc.encodeString(hf.Text))
 
 			// Apply pagination
-			if hf.Pagination {
-				doc.pdf.AliasNbPages("") // Will replace {nb} with total page count
-				doc.pdf.SetY(287 - HeaderMarginTop - 8)
-				doc.pdf.SetX(195)
-				doc.pdf.CellFormat(
-					10,
-					5,
-					doc.encodeString(fmt.Sprintf("Page %d/{nb}", doc.pdf.PageNo())),
-					"0",
-					0,
-					"R",
-					false,
-					0,
-					"",
-				)
+			if hf.Pagination || doc.Options.ShowPageNumbers {
+				doc.appendPagination(doc.footerY() - HeaderMarginTop - 8)
+			}
+
+			// Apply running total
+			if doc.Options.ShowFooterTotal && !doc.hidePrices() {
+				doc.appendFooterTotal(doc.footerY() - HeaderMarginTop - 13)
+			}
+
+			// Apply generation timestamp
+			if doc.Options.ShowGeneratedAt {
+				doc.appendGeneratedAt(doc.footerY() - HeaderMarginTop - 8)
 			}
 
 			doc.pdf.SetY(currentY)
 			doc.pdf.SetX(currentX)
-			doc.pdf.SetMargins(BaseMargin, BaseMarginTop, BaseMargin)
+			doc.setMargins()
 		})
 	}
 
 	return nil
 }
+
+// appendPagination draw the localized page number at y, right aligned on the page margin.
+// Pages are numbered from the document first page, see MergeDocuments.
+func (doc *Document) appendPagination(y float64) {
+	pagesAlias := doc._pagesAlias
+	if len(pagesAlias) == 0 {
+		doc.pdf.AliasNbPages("") // Will replace {nb} with total page count
+		pagesAlias = "{nb}"
+	}
+
+	doc.pdf.SetY(y)
+	doc.pdf.SetX(doc.rtlX(doc.rightX(190), 10))
+	doc.pdf.CellFormat(
+		10,
+		5,
+		doc.encodeString(fmt.Sprintf(doc.Options.TextPagination, doc.pdf.PageNo()-doc._pageOffset, pagesAlias)),
+		"0",
+		0,
+		doc.rtlAlign("R"),
+		false,
+		0,
+		"",
+	)
+}
@@ -0,0 +1,363 @@
+package generator
+
+import "reflect"
+
+// Languages with embedded translations, see Options.Language
+const (
+	LanguageEnglish string = "en"
+	LanguageFrench  string = "fr"
+	LanguageGerman  string = "de"
+	LanguageSpanish string = "es"
+)
+
+// translations of Options text fields by language, missing keys fallback to english defaults
+var translations = map[string]map[string]string{
+	LanguageEnglish: {},
+	LanguageFrench: {
+		"TextTypeInvoice":        "FACTURE",
+		"TextTypeQuotation":      "DEVIS",
+		"TextTypeDeliveryNote":   "BON DE LIVRAISON",
+		"TextTypeCreditNote":     "AVOIR",
+		"TextTypeProForma":       "FACTURE PRO FORMA",
+		"TextTypeDepositInvoice": "FACTURE D'ACOMPTE",
+
+		"TextRefTitle":          "Réf.",
+		"TextVersionTitle":      "Version",
+		"TextDateTitle":         "Date",
+		"TextPaymentTermTitle":  "Conditions de paiement",
+		"TextPaymentTermNet":    "%d jours net",
+		"TextDueDateTitle":      "Échéance",
+		"TextOverdue":           "EN RETARD",
+		"TextValidityDateTitle": "Valable jusqu'au",
+		"TextExpired":           "EXPIRÉ",
+
+		"TextEarlyPaymentDiscount": "%s %% d'escompte pour paiement sous %d jours : %s",
+
+		"TextStatusPaid":          "PAYÉE",
+		"TextStatusPartiallyPaid": "PARTIELLEMENT PAYÉE",
+		"TextStatusUnpaid":        "NON PAYÉE",
+
+		"TextContactVATNumberTitle":          "N° TVA",
+		"TextContactRegistrationNumberTitle": "SIRET",
+		"TextContactEmailTitle":              "E-mail",
+		"TextContactPhoneTitle":              "Tél.",
+
+		"TextBillToTitle": "Facturer à",
+		"TextShipToTitle": "Livrer à",
+
+		"TextItemsRefTitle":           "Réf.",
+		"TextItemsLineNumberTitle":    "N°",
+		"TextItemsNameTitle":          "Désignation",
+		"TextItemsUnitCostTitle":      "Prix unitaire",
+		"TextItemsGrossUnitCostTitle": "Prix TTC",
+		"TextItemsQuantityTitle":      "Qté",
+		"TextItemsTotalHTTitle":       "Total HT",
+		"TextItemsTaxTitle":           "TVA",
+		"TextItemsDiscountTitle":      "Remise",
+		"TextItemsTotalTTCTitle":      "Total TTC",
+		"TextItemsSubtotalTitle":      "Sous-total",
+
+		"TextItemPeriod":      "Du %s au %s",
+		"TextItemPeriodFrom":  "À partir du %s",
+		"TextItemPeriodUntil": "Jusqu'au %s",
+
+		"TextTotalDiscount":   "REMISE TOTALE",
+		"TextTotalTotal":      "TOTAL HT",
+		"TextTotalDiscounted": "TOTAL REMISÉ",
+		"TextTotalTax":        "TVA",
+		"TextTotalShipping":   "FRAIS DE PORT",
+		"TextTotalWithTax":    "TOTAL TTC",
+
+		"TextReverseCharge": "Autoliquidation — TVA due par le preneur",
+
+		"TextTaxSummaryRateTitle":  "Taux",
+		"TextTaxSummaryBaseTitle":  "Base",
+		"TextTaxSummaryTaxTitle":   "TVA",
+		"TextTaxSummaryTotalTitle": "Total",
+
+		"TextProgressBillingTitle":          "Avancement du marché",
+		"TextProgressBillingContractTitle":  "Montant du marché",
+		"TextProgressBillingPreviousTitle":  "Déjà facturé",
+		"TextProgressBillingCurrentTitle":   "Cette facture",
+		"TextProgressBillingRemainingTitle": "Reste à facturer",
+
+		"TextTotalWithholdingTax": "RETENUE À LA SOURCE",
+		"TextTotalNetPayable":     "NET À PAYER",
+		"TextTotalAmountPaid":     "DÉJÀ PAYÉ",
+		"TextTotalBalanceDue":     "RESTE À PAYER",
+		"TextAmountDue":           "MONTANT DÛ",
+		"TextTotalRounding":       "ARRONDI",
+
+		"TextTotalInWords": "Montant en lettres : %s",
+
+		"TextTotalDepositTax":       "TVA SUR ACOMPTE",
+		"TextTotalDeposit":          "ACOMPTE DÛ",
+		"TextTotalDepositRemaining": "SOLDE RESTANT",
+
+		"TextEPCQRCodeTitle": "Scanner pour payer",
+
+		"TextPaymentInfoTitle":              "Coordonnées bancaires",
+		"TextPaymentInfoAccountHolderTitle": "Titulaire du compte",
+		"TextPaymentInfoBankTitle":          "Banque",
+		"TextPaymentInfoReferenceTitle":     "Référence",
+
+		"TextRemittanceSlipTitle":               "TALON DE PAIEMENT",
+		"TextRemittanceSlipDetach":              "Veuillez détacher et retourner ce talon avec votre règlement",
+		"TextRemittanceSlipPayableToTitle":      "Paiement à l'ordre de",
+		"TextRemittanceSlipAccountTitle":        "Numéro de client",
+		"TextRemittanceSlipAmountEnclosedTitle": "Montant joint",
+
+		"TextSwissQRBillReceiptTitle":         "Récépissé",
+		"TextSwissQRBillPaymentPartTitle":     "Section paiement",
+		"TextSwissQRBillAccountTitle":         "Compte / Payable à",
+		"TextSwissQRBillReferenceTitle":       "Référence",
+		"TextSwissQRBillAdditionalInfoTitle":  "Informations supplémentaires",
+		"TextSwissQRBillPayableByTitle":       "Payable par",
+		"TextSwissQRBillPayableByNameTitle":   "Payable par (nom/adresse)",
+		"TextSwissQRBillCurrencyTitle":        "Monnaie",
+		"TextSwissQRBillAmountTitle":          "Montant",
+		"TextSwissQRBillAcceptancePointTitle": "Point de dépôt",
+
+		"TextPagination": "Page %d sur %s",
+
+		"TextGeneratedAt": "Généré le %s",
+
+		"TextFooterCarriedForward":  "Report",
+		"TextContinuedNextPage":     "suite page suivante…",
+		"TextContinuedPreviousPage": "…suite",
+
+		"TextSignatureDate": "Date et signature",
+	},
+	LanguageGerman: {
+		"TextTypeInvoice":        "RECHNUNG",
+		"TextTypeQuotation":      "ANGEBOT",
+		"TextTypeDeliveryNote":   "LIEFERSCHEIN",
+		"TextTypeCreditNote":     "GUTSCHRIFT",
+		"TextTypeProForma":       "PROFORMA-RECHNUNG",
+		"TextTypeDepositInvoice": "ANZAHLUNGSRECHNUNG",
+
+		"TextRefTitle":          "Nr.",
+		"TextVersionTitle":      "Version",
+		"TextDateTitle":         "Datum",
+		"TextPaymentTermTitle":  "Zahlungsbedingungen",
+		"TextPaymentTermNet":    "%d Tage netto",
+		"TextDueDateTitle":      "Fällig am",
+		"TextOverdue":           "ÜBERFÄLLIG",
+		"TextValidityDateTitle": "Gültig bis",
+		"TextExpired":           "ABGELAUFEN",
+
+		"TextEarlyPaymentDiscount": "%s %% Skonto bei Zahlung innerhalb von %d Tagen: %s",
+
+		"TextStatusPaid":          "BEZAHLT",
+		"TextStatusPartiallyPaid": "TEILWEISE BEZAHLT",
+		"TextStatusUnpaid":        "UNBEZAHLT",
+
+		"TextContactVATNumberTitle":          "USt-IdNr.",
+		"TextContactRegistrationNumberTitle": "Handelsregisternummer",
+		"TextContactEmailTitle":              "E-Mail",
+		"TextContactPhoneTitle":              "Tel.",
+
+		"TextBillToTitle": "Rechnungsadresse",
+		"TextShipToTitle": "Lieferadresse",
+
+		"TextItemsRefTitle":           "Art.-Nr.",
+		"TextItemsLineNumberTitle":    "Pos.",
+		"TextItemsNameTitle":          "Bezeichnung",
+		"TextItemsUnitCostTitle":      "Einzelpreis",
+		"TextItemsGrossUnitCostTitle": "Bruttopreis",
+		"TextItemsQuantityTitle":      "Menge",
+		"TextItemsTotalHTTitle":       "Netto",
+		"TextItemsTaxTitle":           "MwSt.",
+		"TextItemsDiscountTitle":      "Rabatt",
+		"TextItemsTotalTTCTitle":      "Gesamt",
+		"TextItemsSubtotalTitle":      "Zwischensumme",
+
+		"TextItemPeriod":      "%s bis %s",
+		"TextItemPeriodFrom":  "Ab %s",
+		"TextItemPeriodUntil": "Bis %s",
+
+		"TextTotalDiscount":   "RABATT GESAMT",
+		"TextTotalTotal":      "NETTOBETRAG",
+		"TextTotalDiscounted": "NETTO NACH RABATT",
+		"TextTotalTax":        "MWST.",
+		"TextTotalShipping":   "VERSANDKOSTEN",
+		"TextTotalWithTax":    "GESAMTBETRAG",
+
+		"TextReverseCharge": "Steuerschuldnerschaft des Leistungsempfängers",
+
+		"TextTaxSummaryRateTitle":  "Steuersatz",
+		"TextTaxSummaryBaseTitle":  "Netto",
+		"TextTaxSummaryTaxTitle":   "MwSt.",
+		"TextTaxSummaryTotalTitle": "Brutto",
+
+		"TextProgressBillingTitle":          "Abschlagsrechnung",
+		"TextProgressBillingContractTitle":  "Auftragswert",
+		"TextProgressBillingPreviousTitle":  "Bereits berechnet",
+		"TextProgressBillingCurrentTitle":   "Diese Rechnung",
+		"TextProgressBillingRemainingTitle": "Noch zu berechnen",
+
+		"TextTotalWithholdingTax": "QUELLENSTEUER",
+		"TextTotalNetPayable":     "ZAHLBETRAG",
+		"TextTotalAmountPaid":     "BEREITS BEZAHLT",
+		"TextTotalBalanceDue":     "OFFENER BETRAG",
+		"TextAmountDue":           "FÄLLIGER BETRAG",
+		"TextTotalRounding":       "RUNDUNG",
+
+		"TextTotalInWords": "Betrag in Worten: %s",
+
+		"TextTotalDepositTax":       "MWST. ANZAHLUNG",
+		"TextTotalDeposit":          "ANZAHLUNG",
+		"TextTotalDepositRemaining": "RESTBETRAG",
+
+		"TextEPCQRCodeTitle": "Scannen und zahlen",
+
+		"TextPaymentInfoTitle":              "Bankverbindung",
+		"TextPaymentInfoAccountHolderTitle": "Kontoinhaber",
+		"TextPaymentInfoBankTitle":          "Bank",
+		"TextPaymentInfoReferenceTitle":     "Verwendungszweck",
+
+		"TextRemittanceSlipTitle":               "ZAHLUNGSABSCHNITT",
+		"TextRemittanceSlipDetach":              "Bitte abtrennen und mit Ihrer Zahlung zurücksenden",
+		"TextRemittanceSlipPayableToTitle":      "Zahlbar an",
+		"TextRemittanceSlipAccountTitle":        "Kundennummer",
+		"TextRemittanceSlipAmountEnclosedTitle": "Beigefügter Betrag",
+
+		"TextSwissQRBillReceiptTitle":         "Empfangsschein",
+		"TextSwissQRBillPaymentPartTitle":     "Zahlteil",
+		"TextSwissQRBillAccountTitle":         "Konto / Zahlbar an",
+		"TextSwissQRBillReferenceTitle":       "Referenz",
+		"TextSwissQRBillAdditionalInfoTitle":  "Zusätzliche Informationen",
+		"TextSwissQRBillPayableByTitle":       "Zahlbar durch",
+		"TextSwissQRBillPayableByNameTitle":   "Zahlbar durch (Name/Adresse)",
+		"TextSwissQRBillCurrencyTitle":        "Währung",
+		"TextSwissQRBillAmountTitle":          "Betrag",
+		"TextSwissQRBillAcceptancePointTitle": "Annahmestelle",
+
+		"TextPagination": "Seite %d von %s",
+
+		"TextGeneratedAt": "Erstellt am %s",
+
+		"TextFooterCarriedForward":  "Übertrag",
+		"TextContinuedNextPage":     "Fortsetzung folgt…",
+		"TextContinuedPreviousPage": "…Fortsetzung",
+
+		"TextSignatureDate": "Datum und Unterschrift",
+	},
+	LanguageSpanish: {
+		"TextTypeInvoice":        "FACTURA",
+		"TextTypeQuotation":      "PRESUPUESTO",
+		"TextTypeDeliveryNote":   "ALBARÁN",
+		"TextTypeCreditNote":     "FACTURA RECTIFICATIVA",
+		"TextTypeProForma":       "FACTURA PROFORMA",
+		"TextTypeDepositInvoice": "FACTURA DE ANTICIPO",
+
+		"TextRefTitle":          "Ref.",
+		"TextVersionTitle":      "Versión",
+		"TextDateTitle":         "Fecha",
+		"TextPaymentTermTitle":  "Condiciones de pago",
+		"TextPaymentTermNet":    "%d días netos",
+		"TextDueDateTitle":      "Vencimiento",
+		"TextOverdue":           "VENCIDA",
+		"TextValidityDateTitle": "Válido hasta",
+		"TextExpired":           "CADUCADO",
+
+		"TextEarlyPaymentDiscount": "%s %% de descuento por pronto pago en %d días: %s",
+
+		"TextStatusPaid":          "PAGADA",
+		"TextStatusPartiallyPaid": "PAGADA PARCIALMENTE",
+		"TextStatusUnpaid":        "PENDIENTE",
+
+		"TextContactVATNumberTitle":          "NIF-IVA",
+		"TextContactRegistrationNumberTitle": "Número de registro",
+		"TextContactEmailTitle":              "Correo electrónico",
+		"TextContactPhoneTitle":              "Tel.",
+
+		"TextBillToTitle": "Facturar a",
+		"TextShipToTitle": "Enviar a",
+
+		"TextItemsRefTitle":           "Ref.",
+		"TextItemsLineNumberTitle":    "N.º",
+		"TextItemsNameTitle":          "Concepto",
+		"TextItemsUnitCostTitle":      "Precio unitario",
+		"TextItemsGrossUnitCostTitle": "Precio bruto",
+		"TextItemsQuantityTitle":      "Cant.",
+		"TextItemsTotalHTTitle":       "Base",
+		"TextItemsTaxTitle":           "IVA",
+		"TextItemsDiscountTitle":      "Descuento",
+		"TextItemsTotalTTCTitle":      "Total",
+		"TextItemsSubtotalTitle":      "Subtotal",
+
+		"TextItemPeriod":      "Del %s al %s",
+		"TextItemPeriodFrom":  "Desde el %s",
+		"TextItemPeriodUntil": "Hasta el %s",
+
+		"TextTotalDiscount":   "DESCUENTO TOTAL",
+		"TextTotalTotal":      "BASE IMPONIBLE",
+		"TextTotalDiscounted": "BASE CON DESCUENTO",
+		"TextTotalTax":        "IVA",
+		"TextTotalShipping":   "GASTOS DE ENVÍO",
+		"TextTotalWithTax":    "TOTAL",
+
+		"TextReverseCharge": "Inversión del sujeto pasivo — IVA a cargo del destinatario",
+
+		"TextTaxSummaryRateTitle":  "Tipo",
+		"TextTaxSummaryBaseTitle":  "Base",
+		"TextTaxSummaryTaxTitle":   "IVA",
+		"TextTaxSummaryTotalTitle": "Total",
+
+		"TextProgressBillingTitle":          "Facturación parcial",
+		"TextProgressBillingContractTitle":  "Importe del contrato",
+		"TextProgressBillingPreviousTitle":  "Facturado anteriormente",
+		"TextProgressBillingCurrentTitle":   "Esta factura",
+		"TextProgressBillingRemainingTitle": "Pendiente de facturar",
+
+		"TextTotalWithholdingTax": "RETENCIÓN IRPF",
+		"TextTotalNetPayable":     "TOTAL A PAGAR",
+		"TextTotalAmountPaid":     "IMPORTE PAGADO",
+		"TextTotalBalanceDue":     "SALDO PENDIENTE",
+		"TextAmountDue":           "IMPORTE A PAGAR",
+		"TextTotalRounding":       "REDONDEO",
+
+		"TextRemittanceSlipTitle":               "TALÓN DE PAGO",
+		"TextRemittanceSlipDetach":              "Por favor, separe y devuelva esta parte con su pago",
+		"TextRemittanceSlipPayableToTitle":      "Pagadero a",
+		"TextRemittanceSlipAccountTitle":        "Número de cliente",
+		"TextRemittanceSlipAmountEnclosedTitle": "Importe adjunto",
+
+		"TextTotalInWords": "Importe en letras: %s",
+
+		"TextTotalDepositTax":       "IVA DEL ANTICIPO",
+		"TextTotalDeposit":          "ANTICIPO",
+		"TextTotalDepositRemaining": "IMPORTE RESTANTE",
+
+		"TextEPCQRCodeTitle": "Escanear para pagar",
+
+		"TextPaymentInfoTitle":              "Datos bancarios",
+		"TextPaymentInfoAccountHolderTitle": "Titular de la cuenta",
+		"TextPaymentInfoBankTitle":          "Banco",
+		"TextPaymentInfoReferenceTitle":     "Referencia",
+
+		"TextPagination": "Página %d de %s",
+
+		"TextGeneratedAt": "Generado el %s",
+
+		"TextFooterCarriedForward":  "Suma y sigue",
+		"TextContinuedNextPage":     "continúa…",
+		"TextContinuedPreviousPage": "…continuación",
+
+		"TextSignatureDate": "Fecha y firma",
+	},
+}
+
+// applyLanguage set empty Options text fields with the Language translations.
+// It must run before defaults are set, so user defined texts are kept and missing keys fallback to english.
+func (o *Options) applyLanguage() {
+	options := reflect.ValueOf(o).Elem()
+
+	for field, text := range translations[o.Language] {
+		value := options.FieldByName(field)
+		if value.IsValid() && value.Kind() == reflect.String && len(value.String()) == 0 {
+			value.SetString(text)
+		}
+	}
+}
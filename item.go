@@ -2,52 +2,139 @@ package generator
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/shopspring/decimal"
 )
 
 // Item represent a 'product' or a 'service'
 type Item struct {
+	Ref               string    `json:"ref,omitempty"` // Product reference or SKU, see Options.ShowItemRef
 	Name              string    `json:"name,omitempty" validate:"required"`
 	Description       string    `json:"description,omitempty"`
-	PriceExclVAT      string    `json:"unit_cost,omitempty"`
-	PriceInclVAT      string    `json:"quantity,omitempty"`
-	PayedPriceInclVAT string    `json:"payed_price_incl_vat,omitempty"`
-	PayedPriceExclVAT string    `json:"payed_price_excl_vat,omitempty"`
+	Section           string    `json:"section,omitempty"`              // Items sharing a section are grouped with a subtotal
+	Order             int       `json:"order,omitempty"`                // Rendering position when Options.SortItemsBy is SortItemsByOrder
+	UnitCost          string    `json:"unit_cost,omitempty"`            // In Currency when set, converted to the document currency
+	Quantity          string    `json:"quantity,omitempty"`             // Negative for return lines
+	Unit              string    `json:"unit,omitempty"`                 // Unit of measure ex kg, hrs, pcs
+	PeriodStart       string    `json:"period_start,omitempty"`         // Service period start, formatted with Options.DateLayout
+	PeriodEnd         string    `json:"period_end,omitempty"`           // Service period end, formatted with Options.DateLayout
+	PayedPriceInclVAT string    `json:"payed_price_incl_vat,omitempty"` // Overrides the computed line total with tax, not a payment (see Document.AmountPaid)
+	PayedPriceExclVAT string    `json:"payed_price_excl_vat,omitempty"` // Overrides the computed line total without tax
 	Tax               *Tax      `json:"tax,omitempty"`
 	Discount          *Discount `json:"discount,omitempty"`
 
-	_unitCost decimal.Decimal
-	_quantity decimal.Decimal
+	// Taxes are charged in addition to Tax, each on the same base ex VAT and an eco-tax.
+	// They are stacked in the tax column, and summed in line and document taxes.
+	Taxes []*Tax `json:"taxes,omitempty" validate:"dive,required"`
+
+	// PriceIncludesTax define UnitCost and amount discounts as tax inclusive: the line net total is derived
+	// from the discounted gross total (net = gross / (1 + rate)) and the tax is the difference (tax = gross - net).
+	PriceIncludesTax bool `json:"price_includes_tax,omitempty"`
+
+	// Discounts are cascaded after Discount, in order: each percent discount applies on the total
+	// reduced by the previous ones, so 10% then 5% is a 14.5% discount, not 15%.
+	Discounts []*Discount `json:"discounts,omitempty" validate:"dive,required"`
+
+	// Footnote is rendered in the numbered footnotes list below the document, its number marks the item name
+	Footnote string `json:"footnote,omitempty"`
+
+	// Currency of an item priced in another currency than the document, see ItemCurrency
+	Currency *ItemCurrency `json:"currency,omitempty"`
+
+	// Image is a product thumbnail rendered before the name, see ItemImage
+	Image *ItemImage `json:"image,omitempty"`
+
+	// HighlightColor fill the line background ex promotional or backordered items, over zebra stripes
+	HighlightColor []int `json:"highlight_color,omitempty" validate:"omitempty,len=3"`
+
+	// Fields are the values of custom items table columns, by ItemColumn.Key
+	Fields map[string]string `json:"fields,omitempty"`
+
+	_unitCostValue     decimal.NullDecimal // Set by SetUnitCost
+	_quantityValue     decimal.NullDecimal // Set by SetQuantity
+	_unitCost          decimal.Decimal
+	_quantity          decimal.Decimal
+	_payedPriceInclVAT decimal.Decimal
+	_payedPriceExclVAT decimal.Decimal
+	_options           *Options
+	_defaultTax        *Tax // Document.DefaultTax, set by Document.PrepareAll
+	_reverseCharge     bool // Document.ReverseCharge, set by Document.PrepareAll
 }
 
 // Prepare convert strings to decimal
 func (i *Item) Prepare() error {
+	// Currency
+	if i.Currency != nil {
+		if err := i.Currency.Prepare(); err != nil {
+			return fmt.Errorf("currency: %w", err)
+		}
+	}
+
 	// Unit cost
-	unitCost, err := decimal.NewFromString(i.PriceExclVAT)
-	if err != nil {
-		return err
+	if !i._unitCostValue.Valid {
+		if _, err := parseDecimal("unit_cost", i.UnitCost); err != nil {
+			return err
+		}
 	}
-	i._unitCost = unitCost
+	i._unitCost = i.unitCost()
 
-	// PriceInclVAT
-	quantity, err := decimal.NewFromString(i.PriceInclVAT)
-	if err != nil {
-		return err
+	// Quantity
+	if !i._quantityValue.Valid {
+		if _, err := parseDecimal("quantity", i.Quantity); err != nil {
+			return err
+		}
 	}
-	i._quantity = quantity
+	i._quantity = i.quantity()
 
-	// Tax
-	if i.Tax != nil {
-		if err := i.Tax.Prepare(); err != nil {
+	// PayedPriceInclVAT
+	if len(i.PayedPriceInclVAT) > 0 {
+		payedPriceInclVAT, err := parseDecimal("payed_price_incl_vat", i.PayedPriceInclVAT)
+		if err != nil {
 			return err
 		}
+		i._payedPriceInclVAT = payedPriceInclVAT
+	} else {
+		i._payedPriceInclVAT = i.TotalWithTaxAndDiscount()
+	}
+
+	// PayedPriceExclVAT
+	if len(i.PayedPriceExclVAT) > 0 {
+		payedPriceExclVAT, err := parseDecimal("payed_price_excl_vat", i.PayedPriceExclVAT)
+		if err != nil {
+			return err
+		}
+		i._payedPriceExclVAT = payedPriceExclVAT
+	} else {
+		i._payedPriceExclVAT = i.TotalWithoutTaxAndWithDiscount()
+	}
+
+	// Tax
+	if tax := i.effectiveTax(); tax != nil {
+		if err := tax.Prepare(); err != nil {
+			return fmt.Errorf("tax: %w", err)
+		}
+	}
+
+	// Additional taxes
+	for index, tax := range i.Taxes {
+		if err := tax.Prepare(); err != nil {
+			return fmt.Errorf("taxes %d: %w", index, err)
+		}
 	}
 
 	// Discount
 	if i.Discount != nil {
 		if err := i.Discount.Prepare(); err != nil {
-			return err
+			return fmt.Errorf("discount: %w", err)
+		}
+	}
+
+	// Cascading discounts
+	for index, discount := range i.Discounts {
+		if err := discount.Prepare(); err != nil {
+			return fmt.Errorf("discounts %d: %w", index, err)
 		}
 	}
 
@@ -56,31 +143,177 @@ func (i *Item) Prepare() error {
 
 // TotalWithoutTaxAndWithoutDiscount returns the total without tax and without discount
 func (i *Item) TotalWithoutTaxAndWithoutDiscount() decimal.Decimal {
-	quantity, _ := decimal.NewFromString(i.PriceInclVAT)
-	price, _ := decimal.NewFromString(i.PriceExclVAT)
+	quantity := i.quantity()
+	price := i.unitCost()
 	total := price.Mul(quantity)
 
+	if i.PriceIncludesTax {
+		return i.round(i.withoutTax(total))
+	}
+
+	// Converted totals are rounded to the document currency
+	if i.Currency != nil {
+		return i.round(total)
+	}
+
 	return total
 }
 
 // TotalWithoutTaxAndWithDiscount returns the total without tax and with discount
 func (i *Item) TotalWithoutTaxAndWithDiscount() decimal.Decimal {
-	total := i.TotalWithoutTaxAndWithoutDiscount()
+	return i.round(i.withoutTax(i.totalWithDiscount()))
+}
 
-	// Check discount
-	if i.Discount != nil {
-		dType, dNum := i.Discount.getDiscount()
+// totalWithDiscount returns the unit cost × quantity total with discounts, including tax when PriceIncludesTax
+func (i *Item) totalWithDiscount() decimal.Decimal {
+	quantity := i.quantity()
+	price := i.unitCost()
+	total := price.Mul(quantity)
+
+	// Apply discounts in order, on the running total
+	for _, discount := range i.discounts() {
+		total = total.Sub(discount.AmountOf(total))
+	}
+
+	return total
+}
 
-		if dType == DiscountTypeAmount {
-			total = total.Sub(dNum)
+// discountsAmounts returns the amount without tax taken by each discount, in application order
+func (i *Item) discountsAmounts() []decimal.Decimal {
+	quantity := i.quantity()
+	price := i.unitCost()
+	total := price.Mul(quantity)
+
+	amounts := []decimal.Decimal{}
+	for _, discount := range i.discounts() {
+		discounted := total.Sub(discount.AmountOf(total))
+		amounts = append(amounts, i.withoutTax(total).Sub(i.withoutTax(discounted)))
+		total = discounted
+	}
+
+	return amounts
+}
+
+// withoutTax returns the net of a tax inclusive total, total is returned as is unless PriceIncludesTax
+func (i *Item) withoutTax(total decimal.Decimal) decimal.Decimal {
+	taxes := i.taxes()
+	if !i.PriceIncludesTax || len(taxes) == 0 {
+		return total
+	}
+
+	// Amount taxes are deducted first (refunded with return lines), then percent taxes rates are summed
+	percent := decimal.Zero
+	for _, tax := range taxes {
+		taxType, taxAmount := tax.getTax()
+		if taxType == TaxTypePercent {
+			percent = percent.Add(taxAmount)
+			continue
+		}
+
+		if total.IsNegative() {
+			total = total.Add(taxAmount)
 		} else {
-			// Percent
-			toSub := total.Mul(dNum.Div(decimal.NewFromFloat(100)))
-			total = total.Sub(toSub)
+			total = total.Sub(taxAmount)
 		}
 	}
 
-	return total
+	if percent.IsZero() {
+		return total
+	}
+
+	return total.Mul(decimal.NewFromFloat(100)).Div(percent.Add(decimal.NewFromFloat(100)))
+}
+
+// unitCostWithoutTax returns the unit cost, without tax for tax inclusive items
+func (i *Item) unitCostWithoutTax() decimal.Decimal {
+	if !i.PriceIncludesTax || i._quantity.IsZero() {
+		return i._unitCost
+	}
+
+	return i.TotalWithoutTaxAndWithoutDiscount().Div(i._quantity)
+}
+
+// unitCostWithTax returns the unit cost with tax, the unit cost as is for tax inclusive items.
+// Amount taxes are charged by line, they are spread over the quantity.
+func (i *Item) unitCostWithTax() decimal.Decimal {
+	if i.PriceIncludesTax || i._quantity.IsZero() {
+		return i._unitCost
+	}
+
+	unitCost := i._unitCost
+	for _, tax := range i.taxes() {
+		taxType, taxAmount := tax.getTax()
+		if taxType == TaxTypeAmount {
+			unitCost = unitCost.Add(taxAmount.Div(i._quantity.Abs()))
+		} else {
+			unitCost = unitCost.Add(i._unitCost.Mul(taxAmount).Div(decimal.NewFromFloat(100)))
+		}
+	}
+
+	return unitCost
+}
+
+// effectiveTax returns the tax applied to the item: 0% under reverse charge, the document default tax
+// when the item has no taxes, Tax otherwise. Tax is left as set, so documents can be rebuilt and exported.
+func (i *Item) effectiveTax() *Tax {
+	if i._reverseCharge {
+		return reverseChargeTax
+	}
+
+	if i.Tax == nil && len(i.Taxes) == 0 {
+		return i._defaultTax
+	}
+
+	return i.Tax
+}
+
+// taxes returns the effective Tax followed by the additional Taxes, in rendering order
+func (i *Item) taxes() []*Tax {
+	taxes := []*Tax{}
+
+	for _, tax := range append([]*Tax{i.effectiveTax()}, i.Taxes...) {
+		if tax != nil {
+			taxes = append(taxes, tax)
+		}
+	}
+
+	return taxes
+}
+
+// discounts returns Discount followed by the cascading Discounts, in application order.
+// Zero discounts ("0", "0.00" amount or percent) are left out, they are not rendered.
+func (i *Item) discounts() []*Discount {
+	discounts := []*Discount{}
+
+	for _, discount := range append([]*Discount{i.Discount}, i.Discounts...) {
+		if discount == nil {
+			continue
+		}
+
+		if _, value := discount.getDiscount(); !value.IsZero() {
+			discounts = append(discounts, discount)
+		}
+	}
+
+	return discounts
+}
+
+// discountsHeight returns the height of the discount cell listing cascading discounts, 0 for a single discount
+func (i *Item) discountsHeight(doc *Document) float64 {
+	if count := len(i.discounts()); count > 1 {
+		return doc.itemsLineHeight() * float64(count+1)
+	}
+
+	return 0
+}
+
+// taxesHeight returns the height of the tax cell listing several taxes, 0 for a single tax
+func (i *Item) taxesHeight(doc *Document) float64 {
+	if count := len(i.taxes()); count > 1 {
+		return doc.itemsLineHeight() * float64(count)
+	}
+
+	return 0
 }
 
 // TotalWithTaxAndDiscount returns the total with tax and discount
@@ -88,49 +321,284 @@ func (i *Item) TotalWithTaxAndDiscount() decimal.Decimal {
 	return i.TotalWithoutTaxAndWithDiscount().Add(i.TaxWithTotalDiscounted())
 }
 
-// TaxWithTotalDiscounted returns the tax with total discounted
+// TaxWithTotalDiscounted returns the tax with total discounted, all item taxes summed
 func (i *Item) TaxWithTotalDiscounted() decimal.Decimal {
 	result := decimal.NewFromFloat(0)
 
-	if i.Tax == nil {
-		return result
+	// Tax inclusive lines keep their gross total
+	if i.PriceIncludesTax && len(i.taxes()) > 0 {
+		return i.round(i.totalWithDiscount()).Sub(i.TotalWithoutTaxAndWithDiscount())
 	}
 
-	totalHT := i.TotalWithoutTaxAndWithDiscount()
-	taxType, taxAmount := i.Tax.getTax()
+	for _, amount := range i.taxesAmounts() {
+		result = result.Add(amount)
+	}
 
+	return result
+}
+
+// taxesAmounts returns the amount of each item tax, in taxes order. The last tax of tax inclusive lines
+// takes the rounding difference, so that amounts add up to the line tax.
+func (i *Item) taxesAmounts() []decimal.Decimal {
+	taxes := i.taxes()
+	amounts := make([]decimal.Decimal, 0, len(taxes))
+
+	totalHT := i.taxBase()
+	taxed := decimal.Zero
+	for index, tax := range taxes {
+		if i.PriceIncludesTax && index == len(taxes)-1 {
+			amounts = append(amounts, i.TaxWithTotalDiscounted().Sub(taxed))
+			break
+		}
+
+		amount := i.round(taxOf(tax, totalHT))
+		amounts = append(amounts, amount)
+		taxed = taxed.Add(amount)
+	}
+
+	return amounts
+}
+
+// taxOf returns the tax on total, unrounded. Amount taxes are refunded with return lines.
+func taxOf(tax *Tax, total decimal.Decimal) decimal.Decimal {
+	taxType, taxAmount := tax.getTax()
 	if taxType == TaxTypeAmount {
-		result = taxAmount
-	} else {
-		divider := decimal.NewFromFloat(100)
-		result = totalHT.Mul(taxAmount.Div(divider))
+		if total.IsNegative() {
+			return taxAmount.Neg()
+		}
+		return taxAmount
 	}
 
-	return result
+	return total.Mul(taxAmount.Div(decimal.NewFromFloat(100)))
+}
+
+// taxBase returns the total percent taxes are computed on: the total with discount, or without it
+// with Options.TaxBeforeDiscount
+func (i *Item) taxBase() decimal.Decimal {
+	if i._options != nil && i._options.TaxBeforeDiscount {
+		return i.TotalWithoutTaxAndWithoutDiscount()
+	}
+
+	return i.TotalWithoutTaxAndWithDiscount()
+}
+
+// hasTax returns true when the item has a non-zero tax
+func (i *Item) hasTax() bool {
+	for _, tax := range i.taxes() {
+		if _, taxAmount := tax.getTax(); !taxAmount.IsZero() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasDiscount returns true when the item has a non-zero discount
+func (i *Item) hasDiscount() bool {
+	return len(i.discounts()) > 0
+}
+
+// isReturn returns true for lines with a negative total, such as returned products (negative quantity)
+func (i *Item) isReturn() bool {
+	return i.TotalWithoutTaxAndWithoutDiscount().IsNegative()
 }
 
-// appendColTo document doc
-func (i *Item) appendColTo(options *Options, doc *Document) {
+// height returns the height of the item line once rendered in document
+func (i *Item) height(doc *Document) float64 {
+	height := i.nameHeight(doc)
+
+	if discountsHeight := i.discountsHeight(doc); discountsHeight > height {
+		height = discountsHeight
+	}
+
+	if taxesHeight := i.taxesHeight(doc); taxesHeight > height {
+		height = taxesHeight
+	}
+
+	if doc.Options.ItemMinHeight > height {
+		height = doc.Options.ItemMinHeight
+	}
+
+	return height
+}
+
+// nameHeight returns the height of the item image, name and description
+func (i *Item) nameHeight(doc *Document) float64 {
+	height := i.textHeight(doc)
+	if i.Image != nil && i.Image._height > height {
+		height = i.Image._height
+	}
+
+	return height
+}
+
+// details returns the texts rendered below the item name in grey: its description, truncated to
+// Options.ItemDescriptionMaxLines, service period and original currency amount
+func (i *Item) details(doc *Document) []string {
+	details := []string{}
+	if len(i.Description) > 0 {
+		_, width := doc.nameColumn()
+
+		doc.setItemFont(true)
+		details = append(details, doc.truncateLines(i.Description, width-i.imageWidth(), doc.Options.ItemDescriptionMaxLines))
+		doc.setItemFont(false)
+	}
+	if period := doc.itemPeriod(i); len(period) > 0 {
+		details = append(details, period)
+	}
+	if original := doc.originalAmount(i); len(original) > 0 {
+		details = append(details, original)
+	}
+
+	return details
+}
+
+// textHeight returns the height of the item name, description and period, beside the item image
+func (i *Item) textHeight(doc *Document) float64 {
+	_, width := doc.nameColumn()
+	width -= i.imageWidth()
+
+	lineHeight := doc.itemsLineHeight()
+
+	doc.setItemFont(false)
+	height := lineHeight * float64(len(doc.pdf.SplitLines([]byte(doc.encodeString(i.Name)), width-i.footnoteWidth())))
+
+	for _, detail := range i.details(doc) {
+		doc.setItemFont(true)
+		height += doc.itemsDetailsSpacing() + lineHeight*float64(len(doc.pdf.SplitLines([]byte(doc.encodeString(detail)), width)))
+		doc.setItemFont(false)
+	}
+
+	return height
+}
+
+// appendColTo document doc, row is the line index in its items block
+func (i *Item) appendColTo(options *Options, doc *Document, row int) error {
+	// Lines are numbered across pages and sections, and summed for footers
+	doc._lineNumber++
+	doc._runningTotal = doc._runningTotal.Add(i._payedPriceInclVAT)
+
 	// Get base Y (top of line)
 	baseY := doc.pdf.GetY()
+	columns := doc.itemColumns()
+
+	// Line height, the ref, name and description are vertically centered in taller lines
+	colHeight := i.height(doc)
+	nameY := baseY + (colHeight-i.nameHeight(doc))/2
+
+	// Zebra striping and highlight, filled over half of the lines spacing
+	fillColor := []int(nil)
+	if options.ZebraRows && row%2 == 1 {
+		fillColor = options.ZebraRowColor
+	}
+	if len(i.HighlightColor) > 0 {
+		fillColor = i.HighlightColor
+	}
+	if fillColor != nil {
+		spacing := doc.itemsRowsSpacing()
+		doc.pdf.SetFillColor(fillColor[0], fillColor[1], fillColor[2])
+		width := doc.contentWidth()
+		doc.pdf.Rect(doc.rtlX(doc.leftX(10), width), baseY-spacing/4, width, colHeight+spacing/2, "F")
+	}
+
+	if options.BeforeRowHook != nil {
+		options.BeforeRowHook(doc, i, baseY)
+		doc.setItemFont(false)
+		doc.pdf.SetY(baseY)
+	}
+
+	// Ref and name
+	for _, column := range columns {
+		if column.Key == ItemColumnRef {
+			doc.pdf.SetXY(doc.rtlX(column.X, column.Width), nameY)
+			doc.pdf.CellFormat(
+				column.Width,
+				doc.itemsLineHeight(),
+				doc.encodeString(i.Ref),
+				"0",
+				0,
+				doc.rtlAlign(column.Align),
+				false,
+				0,
+				"",
+			)
+		}
+
+		if column.Key == ItemColumnName {
+			doc.pdf.SetY(nameY)
+			i.appendNameTo(doc, column)
+		}
+	}
+
+	doc.pdf.SetY(baseY)
 
-	// Name
-	doc.pdf.SetX(ItemColNameOffset)
+	// Return lines amounts are rendered in NegativeTextColor
+	color := doc.Options.BaseTextColor
+	if i.isReturn() {
+		color = doc.Options.NegativeTextColor
+	}
+
+	// Other columns
+	for _, column := range columns {
+		if column.Key == ItemColumnName || column.Key == ItemColumnRef {
+			continue
+		}
+
+		title, desc := i.cell(doc, column)
+		doc.appendItemCell(column, baseY, colHeight, title, desc, color)
+	}
+
+	// Separator below the line, whatever its height
+	doc.appendItemSeparator(baseY + colHeight + doc.itemsRowsSpacing()/2)
+
+	if options.RowHook != nil {
+		options.RowHook(doc, i, baseY)
+		doc.setItemFont(false)
+	}
+
+	// Set Y for next line
+	doc.pdf.SetY(baseY + colHeight)
+
+	return doc.pdf.Error()
+}
+
+// appendNameTo append item image, name and description to document line
+func (i *Item) appendNameTo(doc *Document, column *itemColumn) {
+	// Image, the text is shifted after it and vertically centered beside it
+	x, width := column.X, column.Width
+	if imageWidth := i.imageWidth(); imageWidth > 0 {
+		y := doc.pdf.GetY()
+		i.appendImageTo(doc, column, y)
+		x, width = x+imageWidth, width-imageWidth
+		doc.pdf.SetY(y + (i.nameHeight(doc)-i.textHeight(doc))/2)
+	}
+
+	// Name, followed by its footnote marker
+	nameWidth := width - i.footnoteWidth()
+	nameX := doc.rtlX(x, width)
+	if doc.Options.RTL {
+		nameX += i.footnoteWidth()
+	}
+	doc.pdf.SetX(nameX)
 	doc.pdf.MultiCell(
-		ItemColHTPriceOffset-ItemColNameOffset,
-		3,
+		nameWidth,
+		doc.itemsLineHeight(),
 		doc.encodeString(i.Name),
 		"",
-		"",
+		doc.rtlAlign(column.Align),
 		false,
 	)
+	if len(i.Footnote) > 0 {
+		y := doc.pdf.GetY()
+		i.appendFootnoteMarkerTo(doc, nameX, nameWidth, y-doc.itemsLineHeight(), doc.rtlAlign(column.Align))
+		doc.pdf.SetY(y)
+	}
 
-	// Description
-	if len(i.Description) > 0 {
-		doc.pdf.SetX(ItemColNameOffset)
-		doc.pdf.SetY(doc.pdf.GetY() + 1)
+	// Description and period
+	for _, detail := range i.details(doc) {
+		doc.pdf.SetXY(doc.rtlX(x, width), doc.pdf.GetY()+doc.itemsDetailsSpacing())
 
-		doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+		doc.setItemFont(true)
 		doc.pdf.SetTextColor(
 			doc.Options.GreyTextColor[0],
 			doc.Options.GreyTextColor[1],
@@ -138,222 +606,226 @@ func (i *Item) appendColTo(options *Options, doc *Document) {
 		)
 
 		doc.pdf.MultiCell(
-			ItemColHTPriceOffset-ItemColNameOffset,
-			3,
-			doc.encodeString(i.Description),
-			"",
+			width,
+			doc.itemsLineHeight(),
+			doc.encodeString(detail),
 			"",
+			doc.rtlAlign(column.Align),
 			false,
 		)
 
 		// Reset font
-		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		doc.setItemFont(false)
 		doc.pdf.SetTextColor(
 			doc.Options.BaseTextColor[0],
 			doc.Options.BaseTextColor[1],
 			doc.Options.BaseTextColor[2],
 		)
 	}
+}
 
-	// Compute line height
-	colHeight := doc.pdf.GetY() - baseY
+// cell returns the item text of column, and an optional description rendered below in grey
+func (i *Item) cell(doc *Document, column *itemColumn) (string, string) {
+	if column.Value != nil {
+		return column.Value(i), ""
+	}
 
-	// PriceExclVAT
-	doc.pdf.SetY(baseY)
-	doc.pdf.SetX(ItemColHTPriceOffset)
-	doc.pdf.CellFormat(
-		ItemColPriceInclVATOffset-ItemColHTPriceOffset,
-		colHeight,
-		doc.encodeString(doc.ac.FormatMoneyDecimal(i._unitCost)),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
+	switch column.Key {
+	case ItemColumnLineNumber:
+		return strconv.Itoa(doc._lineNumber), ""
 
-	// PriceInclVAT
-	doc.pdf.SetX(ItemColPriceInclVATOffset)
-	doc.pdf.CellFormat(
-		ItemColQtyOffset-ItemColPriceInclVATOffset,
-		colHeight,
-		doc.encodeString(doc.ac.FormatMoneyDecimal(i._quantity)),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
+	case ItemColumnRef:
+		return i.Ref, ""
 
-	// Qty
-	doc.pdf.SetX(ItemColQtyOffset)
-	doc.pdf.CellFormat(
-		ItemColTaxOffset-ItemColQtyOffset,
-		colHeight,
-		doc.encodeString("1"),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
+	case ItemColumnUnitCost:
+		if doc.Options.ShowGrossUnitCost {
+			return doc.ac.FormatMoneyDecimal(i.unitCostWithTax()), ""
+		}
+		return doc.ac.FormatMoneyDecimal(i.unitCostWithoutTax()), ""
 
-	// Discount
-	doc.pdf.SetX(ItemColDiscountOffset)
-	if i.Discount == nil || i.Discount.Amount == "0.00" {
-		doc.pdf.CellFormat(
-			ItemColTotalTTCOffset-ItemColDiscountOffset,
-			colHeight,
-			doc.encodeString("--"),
-			"0",
-			0,
-			"",
-			false,
-			0,
-			"",
-		)
-	} else {
-		// If discount
-		var discountDesc string
-		decimalAmount, err := decimal.NewFromString(i.Discount.Amount)
-		if err != nil {
-			panic(err)
+	case ItemColumnQuantity:
+		quantity := doc.formatQuantity(i._quantity)
+		if len(i.Unit) > 0 && !doc.Options.HideItemUnit {
+			quantity = fmt.Sprintf("%s %s", quantity, i.Unit)
 		}
-		discountDesc = fmt.Sprintf("- %s", doc.ac.FormatMoneyDecimal(decimalAmount))
+		return quantity, ""
 
-		// discount title
-		// lastY := doc.pdf.GetY()
-		doc.pdf.CellFormat(
-			ItemColTotalTTCOffset-ItemColDiscountOffset,
-			colHeight/2,
-			doc.encodeString(discountDesc),
-			"0",
-			0,
-			"",
-			false,
-			0,
-			"",
-		)
-		// discount desc
-		doc.pdf.SetXY(ItemColDiscountOffset, baseY+(colHeight/2))
-		doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
-		doc.pdf.SetTextColor(
-			doc.Options.GreyTextColor[0],
-			doc.Options.GreyTextColor[1],
-			doc.Options.GreyTextColor[2],
+	case ItemColumnQty:
+		return "1", ""
+
+	case ItemColumnDiscount:
+		discounts := i.discounts()
+		if len(discounts) == 0 {
+			return "--", ""
+		}
+
+		// Single amount discount
+		if discountType, _ := discounts[0].getDiscount(); len(discounts) == 1 && discountType == DiscountTypeAmount {
+			return discountLabel(doc, discounts[0]), ""
+		}
+
+		// Percent discounts with their amount, one per line
+		if doc.Options.ShowDiscountAmount {
+			amounts := i.discountsAmounts()
+			labels := make([]string, 0, len(discounts))
+			for index, discount := range discounts {
+				labels = append(labels, combinedDiscountLabel(doc, discount, amounts[index]))
+			}
+
+			return strings.Join(labels, "\n"), ""
+		}
+
+		// Percent or cascading discounts, one per line, with the discounted amount as description
+		labels := make([]string, 0, len(discounts))
+		for _, discount := range discounts {
+			labels = append(labels, discountLabel(doc, discount))
+		}
+
+		return strings.Join(labels, "\n"), fmt.Sprintf(
+			"- %s",
+			doc.ac.FormatMoneyDecimal(i.TotalWithoutTaxAndWithoutDiscount().Sub(i.TotalWithoutTaxAndWithDiscount()).Abs()),
 		)
 
+	case ItemColumnTax:
+		taxes := i.taxes()
+		if len(taxes) == 0 {
+			return "--", ""
+		}
+
+		// Single tax
+		if len(taxes) == 1 {
+			taxType, taxAmount := taxes[0].getTax()
+			if taxType == TaxTypePercent {
+				taxAmount = decimal.Zero
+			}
+			return doc.ac.FormatMoneyDecimal(taxAmount), doc.formatTaxRate(taxes[0].Percent)
+		}
+
+		// Several taxes, one per line
+		labels := make([]string, 0, len(taxes))
+		for _, tax := range taxes {
+			labels = append(labels, taxLabel(doc, tax))
+		}
+
+		return strings.Join(labels, "\n"), ""
+
+	case ItemColumnTotal:
+		return doc.formatTotal(i._payedPriceInclVAT), ""
+	}
+
+	return i.Fields[column.Key], ""
+}
+
+// taxLabel returns the tax as rendered in items lines with several taxes ex "VAT 20 %", "€ 0.50"
+func taxLabel(doc *Document, tax *Tax) string {
+	label := doc.formatTaxRate(tax.Percent)
+	if taxType, taxAmount := tax.getTax(); taxType == TaxTypeAmount {
+		label = doc.ac.FormatMoneyDecimal(taxAmount)
+	}
+
+	if len(tax.Name) > 0 {
+		return fmt.Sprintf("%s %s", tax.Name, label)
+	}
+
+	return label
+}
+
+// discountLabel returns the discount as rendered in items lines ex "- 10 %"
+func discountLabel(doc *Document, discount *Discount) string {
+	discountType, discountValue := discount.getDiscount()
+	if discountType == DiscountTypeAmount {
+		return fmt.Sprintf("- %s", doc.ac.FormatMoneyDecimal(discountValue))
+	}
+
+	return fmt.Sprintf("- %s %%", discountValue.String())
+}
+
+// combinedDiscountLabel returns the discount with the amount it takes, as rendered in items lines with
+// Options.ShowDiscountAmount ex "10 % (- € 50.00)". Amount discounts are rendered as their discountLabel.
+func combinedDiscountLabel(doc *Document, discount *Discount, amount decimal.Decimal) string {
+	discountType, discountValue := discount.getDiscount()
+	if discountType == DiscountTypeAmount {
+		return discountLabel(doc, discount)
+	}
+
+	return fmt.Sprintf("%s %% (- %s)", discountValue.String(), doc.ac.FormatMoneyDecimal(amount.Abs()))
+}
+
+// appendItemCell append a line cell of column to document with title in color, the description is rendered below title in grey
+func (doc *Document) appendItemCell(column *itemColumn, baseY float64, colHeight float64, title string, desc string, color []int) {
+	doc.pdf.SetXY(doc.rtlX(column.X, column.Width), baseY)
+	doc.pdf.SetTextColor(color[0], color[1], color[2])
+
+	if len(desc) == 0 && !strings.Contains(title, "\n") {
 		doc.pdf.CellFormat(
-			ItemColTotalTTCOffset-ItemColDiscountOffset,
-			colHeight/2,
-			doc.encodeString(fmt.Sprintf("%s %%", i.Discount.Percent)),
+			column.Width,
+			colHeight,
+			doc.encodeString(title),
 			"0",
 			0,
-			"LT",
+			doc.rtlAlign(column.Align),
 			false,
 			0,
 			"",
 		)
-
-		// reset font and y
-		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
 		doc.pdf.SetTextColor(
 			doc.Options.BaseTextColor[0],
 			doc.Options.BaseTextColor[1],
 			doc.Options.BaseTextColor[2],
 		)
-		doc.pdf.SetY(baseY)
+		return
 	}
 
-	// Tax
-	doc.pdf.SetX(ItemColTaxOffset)
-	if i.Tax == nil {
-		// If no tax
-		doc.pdf.CellFormat(
-			ItemColDiscountOffset-ItemColTaxOffset,
-			colHeight,
-			doc.encodeString("--"),
-			"0",
-			0,
-			"",
-			false,
-			0,
-			"",
-		)
-	} else {
-		decimalAmount, err := decimal.NewFromString(i.Tax.Amount)
-		if err != nil {
-			panic(err)
+	// title, multiple lines are listed from the line top
+	descY := baseY + (colHeight / 2)
+	if titleLines := strings.Split(title, "\n"); len(titleLines) > 1 {
+		lineHeight := doc.itemsLineHeight()
+		for n, line := range titleLines {
+			doc.pdf.SetXY(doc.rtlX(column.X, column.Width), baseY+lineHeight*float64(n))
+			doc.pdf.CellFormat(column.Width, lineHeight, doc.encodeString(line), "0", 0, doc.rtlAlign(column.Align), false, 0, "")
 		}
-		taxTitle := fmt.Sprintf("%s", doc.ac.FormatMoneyDecimal(decimalAmount))
-		taxDesc := fmt.Sprintf("%s %s", i.Tax.Percent, doc.encodeString("%"))
-
-		// tax title
-		// lastY := doc.pdf.GetY()
-		doc.pdf.CellFormat(
-			ItemColDiscountOffset-ItemColTaxOffset,
-			colHeight/2,
-			doc.encodeString(taxTitle),
-			"0",
-			0,
-			"LB",
-			false,
-			0,
-			"",
-		)
-
-		// tax desc
-		doc.pdf.SetXY(ItemColTaxOffset, baseY+(colHeight/2))
-		doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
-		doc.pdf.SetTextColor(
-			doc.Options.GreyTextColor[0],
-			doc.Options.GreyTextColor[1],
-			doc.Options.GreyTextColor[2],
-		)
-
+		descY = baseY + lineHeight*float64(len(titleLines))
+	} else {
 		doc.pdf.CellFormat(
-			ItemColDiscountOffset-ItemColTaxOffset,
+			column.Width,
 			colHeight/2,
-			doc.encodeString(taxDesc),
+			doc.encodeString(title),
 			"0",
 			0,
-			"LT",
+			doc.rtlAlign(column.Align+"B"),
 			false,
 			0,
 			"",
 		)
-
-		// reset font and y
-		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
-		doc.pdf.SetTextColor(
-			doc.Options.BaseTextColor[0],
-			doc.Options.BaseTextColor[1],
-			doc.Options.BaseTextColor[2],
-		)
-		doc.pdf.SetY(baseY)
 	}
 
-	decimalAmount, err := decimal.NewFromString(i.PayedPriceInclVAT)
-	if err != nil {
-		panic(err)
-	}
-	// TOTAL TTC
-	doc.pdf.SetX(ItemColTotalTTCOffset)
+	// desc
+	doc.pdf.SetXY(doc.rtlX(column.X, column.Width), descY)
+	doc.setItemFont(true)
+	doc.pdf.SetTextColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+
 	doc.pdf.CellFormat(
-		190-ItemColTotalTTCOffset,
-		colHeight,
-		doc.encodeString(doc.ac.FormatMoneyDecimal(decimalAmount)),
+		column.Width,
+		baseY+colHeight-descY,
+		doc.encodeString(desc),
 		"0",
 		0,
-		"",
+		doc.rtlAlign(column.Align+"T"),
 		false,
 		0,
 		"",
 	)
 
-	// Set Y for next line
-	doc.pdf.SetY(baseY + colHeight)
+	// reset font and y
+	doc.setItemFont(false)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+	doc.pdf.SetY(baseY)
 }
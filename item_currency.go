@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidExchangeRate when an item currency rate is not positive
+var ErrInvalidExchangeRate = errors.New("invalid exchange rate")
+
+// ItemCurrency define the currency of an item priced in another currency than the document, see Item.Currency.
+// Only the item UnitCost is in this currency: it is converted with Rate, and the item amounts, discounts,
+// taxes and PayedPrice* overrides are in the document currency.
+type ItemCurrency struct {
+	Code   string `json:"code,omitempty" validate:"required"` // ISO 4217 code ex USD
+	Symbol string `json:"symbol,omitempty"`                   // Symbol of the original amount ex "$", the code when empty
+	Rate   string `json:"rate,omitempty" validate:"required"` // Document currency amount of one unit of this currency ex 0.92
+
+	// ShowOriginal render the line total in this currency below the item name, see Options.TextItemOriginalAmount
+	ShowOriginal bool `json:"show_original,omitempty"`
+}
+
+// Prepare check the rate is a positive decimal
+func (c *ItemCurrency) Prepare() error {
+	rate, err := parseDecimal("rate", c.Rate)
+	if err != nil {
+		return err
+	}
+
+	if !rate.IsPositive() {
+		return fmt.Errorf("%w: %s", ErrInvalidExchangeRate, c.Rate)
+	}
+
+	return nil
+}
+
+// rate returns the exchange rate to the document currency
+func (c *ItemCurrency) rate() decimal.Decimal {
+	rate, _ := decimal.NewFromString(c.Rate)
+	return rate
+}
+
+// unitCost returns the item unit cost, converted to the document currency
+func (i *Item) unitCost() decimal.Decimal {
+	unitCost := i.baseUnitCost()
+	if i.Currency == nil {
+		return unitCost
+	}
+
+	return unitCost.Mul(i.Currency.rate())
+}
+
+// originalAmount returns the item line total without tax and discount in its currency, formatted with its
+// symbol and the document rate ex "$200.00 (1 USD = 0.92 EUR)", empty unless Item.Currency.ShowOriginal
+func (doc *Document) originalAmount(item *Item) string {
+	if item.Currency == nil || !item.Currency.ShowOriginal {
+		return ""
+	}
+
+	ac := doc.ac
+	ac.Symbol = item.Currency.Symbol
+	if len(ac.Symbol) == 0 {
+		ac.Symbol = item.Currency.Code + " "
+	}
+
+	return fmt.Sprintf(
+		doc.Options.TextItemOriginalAmount,
+		ac.FormatMoneyDecimal(item.baseUnitCost().Mul(item.quantity())),
+		item.Currency.Code,
+		item.Currency.rate().String(),
+		doc.Options.CurrencyCode,
+	)
+}
@@ -0,0 +1,67 @@
+package generator
+
+import "github.com/shopspring/decimal"
+
+// NewItem returns an item of unit cost and quantity decimals. They are stored as is, Prepare does not parse them,
+// UnitCost and Quantity strings are only set for exports: change them with SetUnitCost and SetQuantity.
+func NewItem(name string, unitCost decimal.Decimal, quantity decimal.Decimal) *Item {
+	return (&Item{Name: name}).SetUnitCost(unitCost).SetQuantity(quantity)
+}
+
+// SetUnitCost of item, see NewItem
+func (i *Item) SetUnitCost(unitCost decimal.Decimal) *Item {
+	i.UnitCost = unitCost.String()
+	i._unitCostValue = decimal.NullDecimal{Decimal: unitCost, Valid: true}
+	return i
+}
+
+// SetQuantity of item, see NewItem
+func (i *Item) SetQuantity(quantity decimal.Decimal) *Item {
+	i.Quantity = quantity.String()
+	i._quantityValue = decimal.NullDecimal{Decimal: quantity, Valid: true}
+	return i
+}
+
+// SetTaxPercent of item ex 20 for a 20 % tax
+func (i *Item) SetTaxPercent(percent decimal.Decimal) *Item {
+	i.Tax = &Tax{Percent: percent.String()}
+	return i
+}
+
+// SetTaxAmount of item, charged once on the line
+func (i *Item) SetTaxAmount(amount decimal.Decimal) *Item {
+	i.Tax = &Tax{Amount: amount.String()}
+	return i
+}
+
+// SetDiscountPercent of item ex 10 for a 10 % discount
+func (i *Item) SetDiscountPercent(percent decimal.Decimal) *Item {
+	i.Discount = &Discount{Percent: percent.String()}
+	return i
+}
+
+// SetDiscountAmount of item, taken once on the line
+func (i *Item) SetDiscountAmount(amount decimal.Decimal) *Item {
+	i.Discount = &Discount{Amount: amount.String()}
+	return i
+}
+
+// baseUnitCost returns the unit cost decimal set with SetUnitCost, or parsed from UnitCost
+func (i *Item) baseUnitCost() decimal.Decimal {
+	if i._unitCostValue.Valid {
+		return i._unitCostValue.Decimal
+	}
+
+	unitCost, _ := decimal.NewFromString(i.UnitCost)
+	return unitCost
+}
+
+// quantity returns the quantity decimal set with SetQuantity, or parsed from Quantity
+func (i *Item) quantity() decimal.Decimal {
+	if i._quantityValue.Valid {
+		return i._quantityValue.Decimal
+	}
+
+	quantity, _ := decimal.NewFromString(i.Quantity)
+	return quantity
+}
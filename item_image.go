@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// ErrInvalidItemImage when an item image cannot be read or decoded
+var ErrInvalidItemImage = errors.New("invalid item image")
+
+// itemImageMargin is the space (mm) between an item image and its name
+const itemImageMargin = 2
+
+// ItemImage define a product thumbnail rendered in the item name column, before its name.
+// Image is read from Bytes, or from the file at Path when Bytes is empty.
+// It is scaled down to fit in Options.ItemImageMaxWidth x Options.ItemImageMaxHeight, keeping its aspect ratio.
+type ItemImage struct {
+	Path  string `json:"path,omitempty"`
+	Bytes []byte `json:"bytes,omitempty"`
+
+	_name   string
+	_format string
+	_width  float64
+	_height float64
+}
+
+// registerItemImages register items images in document pdf and compute their size, before items layout
+func (doc *Document) registerItemImages() error {
+	for index, item := range doc.Items {
+		if item.Image == nil {
+			continue
+		}
+
+		if err := doc.registerItemImage(item.Image); err != nil {
+			return fmt.Errorf("items[%d]: %w", index, err)
+		}
+	}
+
+	return nil
+}
+
+// registerItemImage register image in document pdf, identical images are embedded once
+func (doc *Document) registerItemImage(img *ItemImage) error {
+	imageBytes := img.Bytes
+	if len(imageBytes) == 0 {
+		fileBytes, err := os.ReadFile(img.Path)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidItemImage, err)
+		}
+		imageBytes = fileBytes
+	}
+
+	name := fmt.Sprintf("item-image-%x", sha1.Sum(imageBytes))
+	imageInfo, format, err := doc.registerImage(name, imageBytes)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidItemImage, err)
+	}
+
+	maxWidth, maxHeight := doc.Options.ItemImageMaxWidth, doc.Options.ItemImageMaxHeight
+	width, height := maxWidth, maxWidth*imageInfo.Height()/imageInfo.Width()
+	if height > maxHeight {
+		width, height = maxHeight*imageInfo.Width()/imageInfo.Height(), maxHeight
+	}
+
+	img._name, img._format, img._width, img._height = name, format, width, height
+
+	return nil
+}
+
+// imageWidth returns the width (mm) taken by the item image in the name column, margin included
+func (i *Item) imageWidth() float64 {
+	if i.Image == nil || len(i.Image._name) == 0 {
+		return 0
+	}
+
+	return i.Image._width + itemImageMargin
+}
+
+// appendImageTo draw the item image at the start of the name column
+func (i *Item) appendImageTo(doc *Document, column *itemColumn, y float64) {
+	img := i.Image
+	doc.pdf.ImageOptions(
+		img._name,
+		doc.rtlX(column.X, img._width),
+		y,
+		img._width,
+		img._height,
+		false,
+		fpdf.ImageOptions{ImageType: img._format},
+		0,
+		"",
+	)
+}
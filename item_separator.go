@@ -0,0 +1,36 @@
+package generator
+
+// Items separators, see Options.ItemSeparator
+const (
+	ItemSeparatorNone   string = "none"
+	ItemSeparatorThin   string = "thin"
+	ItemSeparatorDashed string = "dashed"
+)
+
+// itemSeparatorWidth is the line width (mm) of items separators
+const itemSeparatorWidth float64 = 0.1
+
+// appendItemSeparator draw Options.ItemSeparator across the items table at y, in ItemSeparatorColor
+func (doc *Document) appendItemSeparator(y float64) {
+	if doc.Options.ItemSeparator != ItemSeparatorThin && doc.Options.ItemSeparator != ItemSeparatorDashed {
+		return
+	}
+
+	doc.pdf.SetDrawColor(
+		doc.Options.ItemSeparatorColor[0],
+		doc.Options.ItemSeparatorColor[1],
+		doc.Options.ItemSeparatorColor[2],
+	)
+	doc.pdf.SetLineWidth(itemSeparatorWidth)
+	if doc.Options.ItemSeparator == ItemSeparatorDashed {
+		doc.pdf.SetDashPattern([]float64{1, 1}, 0)
+	}
+
+	x := doc.leftX(10)
+	doc.pdf.Line(x, y, x+doc.contentWidth(), y)
+
+	// Reset
+	doc.pdf.SetDashPattern([]float64{}, 0)
+	doc.pdf.SetLineWidth(0.2)
+	doc.pdf.SetDrawColor(0, 0, 0)
+}
@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/creasty/defaults"
+)
+
+// ErrInvalidJSON when a document json cannot be read
+var ErrInvalidJSON = errors.New("invalid json")
+
+// ReadDocumentJSON returns the document of a json, as written by Document.WriteJSON. Options missing from
+// the json are completed as by New, with the defaults of the json language and theme.
+//
+// Funcs are not part of the json: Options.UnicodeTranslateFunc, rows hooks, custom columns values and the
+// currency symbol of SetCurrencySymbol must be set again. Documents without Date are rendered with the date
+// of the day, set it to render the same pdf again.
+func ReadDocumentJSON(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJSON, err)
+	}
+
+	doc := &Document{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJSON, err)
+	}
+
+	// Defaults are set before reading options, so that zero values of the json (ex a 0 currency
+	// precision) are kept
+	options := &Options{}
+	if doc.Options != nil {
+		options.Language, options.Theme = doc.Options.Language, doc.Options.Theme
+	}
+	options.applyLanguage()
+	options.applyTheme()
+	_ = defaults.Set(options)
+
+	var raw struct {
+		Options json.RawMessage `json:"options"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJSON, err)
+	}
+	if len(raw.Options) > 0 {
+		if err := json.Unmarshal(raw.Options, options); err != nil {
+			return nil, fmt.Errorf("%w: options: %s", ErrInvalidJSON, err)
+		}
+	}
+
+	doc.Options = options
+	if err := doc.init(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// WriteJSON write the document json to w, read back by ReadDocumentJSON
+func (doc *Document) WriteJSON(w io.Writer) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/leekchan/accounting"
+)
+
+// localeFormat define how a locale formats money amounts
+type localeFormat struct {
+	thousand string
+	decimal  string
+	position string // CurrencyPositionBefore or CurrencyPositionAfter
+	spaced   bool   // Space between the symbol and the amount
+}
+
+// locales money formats by ISO locale, see Options.Locale
+var locales = map[string]localeFormat{
+	"en-US": {thousand: ",", decimal: ".", position: CurrencyPositionBefore},
+	"en-GB": {thousand: ",", decimal: ".", position: CurrencyPositionBefore},
+	"fr-FR": {thousand: " ", decimal: ",", position: CurrencyPositionAfter, spaced: true},
+	"de-DE": {thousand: ".", decimal: ",", position: CurrencyPositionAfter, spaced: true},
+	"de-CH": {thousand: "'", decimal: ".", position: CurrencyPositionBefore, spaced: true},
+	"es-ES": {thousand: ".", decimal: ",", position: CurrencyPositionAfter, spaced: true},
+	"it-IT": {thousand: ".", decimal: ",", position: CurrencyPositionAfter, spaced: true},
+	"nl-NL": {thousand: ".", decimal: ",", position: CurrencyPositionBefore, spaced: true},
+}
+
+// applyLocale set the money formatter separators and symbol position of locale, the symbol
+// spaces are replaced by the locale one. Unknown locales leave ac untouched.
+func applyLocale(ac *accounting.Accounting, locale string) {
+	format, ok := locales[locale]
+	if !ok {
+		return
+	}
+
+	ac.Thousand = format.thousand
+	ac.Decimal = format.decimal
+	ac.Symbol = strings.TrimSpace(ac.Symbol)
+	ac.Format = currencyFormat(format.position)
+
+	if format.spaced {
+		ac.Format = "%s %v"
+		if format.position == CurrencyPositionAfter {
+			ac.Format = "%v %s"
+		}
+	}
+}
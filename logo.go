@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// ErrInvalidLogo when the logo image cannot be read or decoded
+var ErrInvalidLogo = errors.New("invalid logo")
+
+// Logo define an image rendered at the top of the first page.
+// Image is read from Bytes, or from the file at Path when Bytes is empty.
+// It is scaled down to fit in MaxWidth x MaxHeight, keeping its aspect ratio.
+type Logo struct {
+	Path      string  `json:"path,omitempty"`
+	Bytes     []byte  `json:"bytes,omitempty"`
+	X         float64 `json:"x,omitempty"`          // Defaults to the left margin
+	Y         float64 `json:"y,omitempty"`          // Defaults to the top margin, below the header band when shown
+	MaxWidth  float64 `json:"max_width,omitempty"`  // Defaults to 60
+	MaxHeight float64 `json:"max_height,omitempty"` // Defaults to 30
+}
+
+// registerImage register image bytes in document pdf, and returns its informations
+func (doc *Document) registerImage(name string, imageBytes []byte) (*fpdf.ImageInfoType, string, error) {
+	// Flatten transparent images of PDF/A documents
+	imageBytes, err := doc.opaqueImage(imageBytes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Get image format
+	_, format, err := image.DecodeConfig(bytes.NewReader(imageBytes))
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Register image in pdf
+	imageInfo := doc.pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{
+		ImageType: format,
+	}, bytes.NewReader(imageBytes))
+
+	if err := doc.pdf.Error(); err != nil {
+		return nil, "", err
+	}
+
+	return imageInfo, format, nil
+}
+
+// appendLogo to document, returns the logo bottom y
+func (doc *Document) appendLogo() (float64, error) {
+	logo := doc.Options.Logo
+
+	imageBytes := logo.Bytes
+	if len(imageBytes) == 0 {
+		fileBytes, err := os.ReadFile(logo.Path)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrInvalidLogo, err)
+		}
+		imageBytes = fileBytes
+	}
+
+	name := "options-logo"
+	imageInfo, format, err := doc.registerImage(name, imageBytes)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidLogo, err)
+	}
+
+	// Position and size
+	x, y, maxWidth, maxHeight := logo.X, logo.Y, logo.MaxWidth, logo.MaxHeight
+	if x == 0 {
+		x = doc.margins().Left
+	}
+	if y == 0 {
+		y = doc.contentTop()
+	}
+	if maxWidth == 0 {
+		maxWidth = 60
+	}
+	if maxHeight == 0 {
+		maxHeight = 30
+	}
+
+	width, height := maxWidth, maxWidth*imageInfo.Height()/imageInfo.Width()
+	if height > maxHeight {
+		width, height = maxHeight*imageInfo.Width()/imageInfo.Height(), maxHeight
+	}
+
+	doc.pdf.ImageOptions(name, doc.rtlX(x, width), y, width, height, false, fpdf.ImageOptions{ImageType: format}, 0, "")
+
+	return y + height, doc.pdf.Error()
+}
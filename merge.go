@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// ErrNoDocuments when merging an empty documents list
+var ErrNoDocuments = errors.New("no documents")
+
+// MergeDocuments build docs in a single pdf, each document starting on a new page and returns the pdf bytes.
+// Documents are built with the first document pdf (see Document.Pdf), they keep their own header, footer
+// and pagination, numbered from their first page.
+func MergeDocuments(docs []*Document) ([]byte, error) {
+	if len(docs) == 0 {
+		return nil, ErrNoDocuments
+	}
+
+	if docs[0]._built {
+		docs[0].newPdf()
+	}
+	pdf := docs[0].pdf
+	var attachments []fpdf.Attachment
+
+	for index, doc := range docs {
+		doc.pdf = pdf
+		doc._built = false
+		doc._pageOffset = pdf.PageCount()
+		doc._pagesAlias = fmt.Sprintf("{nb%d}", index)
+
+		// Reset the previous document header and text direction
+		if index > 0 {
+			pdf.SetHeaderFunc(nil)
+			pdf.LTR()
+		}
+
+		if _, err := doc.Build(); err != nil {
+			return nil, fmt.Errorf("document %d %q: %w", index, doc.Ref, err)
+		}
+
+		pdf.RegisterAlias(doc._pagesAlias, strconv.Itoa(pdf.PageCount()-doc._pageOffset))
+		attachments = append(attachments, doc.pdfAttachments()...)
+	}
+
+	// Embed the attached files of all documents
+	if len(attachments) > 0 {
+		pdf.SetAttachments(attachments)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := pdf.Output(buf); err != nil {
+		return nil, err
+	}
+
+	if docs[0].Options.PDFA {
+		return appendPDFACatalog(buf.Bytes())
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidNumber when a document number template cannot be expanded, or is not a valid ref once expanded
+var ErrInvalidNumber = errors.New("invalid document number")
+
+// Number define a document number expanded to the document Ref from a template, a sequence and the document date.
+//
+// Template placeholders are {YYYY} {YY} {MM} {DD} for the document date (today when empty), and {SEQ} for the
+// sequence, zero padded with {SEQ:0000} ex INV-{YYYY}-{SEQ:0000} expands to INV-2024-0042.
+type Number struct {
+	Template string `json:"template,omitempty" validate:"required"`
+	Sequence int    `json:"sequence,omitempty" validate:"gte=0"`
+}
+
+var (
+	// numberPlaceholderRegexp matches the template placeholders, and unbalanced braces
+	numberPlaceholderRegexp = regexp.MustCompile(`\{[^{}]*\}|[{}]`)
+
+	// numberPaddingRegexp matches the zero padded sequence placeholder, ex {SEQ:0000}
+	numberPaddingRegexp = regexp.MustCompile(`^\{SEQ:(0+)\}$`)
+)
+
+// FormatNumber expand a document number template with date and sequence, see Number
+func FormatNumber(template string, date time.Time, sequence int) (string, error) {
+	var invalid error
+
+	number := numberPlaceholderRegexp.ReplaceAllStringFunc(template, func(placeholder string) string {
+		switch placeholder {
+		case "{YYYY}":
+			return fmt.Sprintf("%04d", date.Year())
+		case "{YY}":
+			return fmt.Sprintf("%02d", date.Year()%100)
+		case "{MM}":
+			return fmt.Sprintf("%02d", int(date.Month()))
+		case "{DD}":
+			return fmt.Sprintf("%02d", date.Day())
+		case "{SEQ}":
+			return strconv.Itoa(sequence)
+		}
+
+		// Padded sequence
+		if padding := numberPaddingRegexp.FindStringSubmatch(placeholder); padding != nil {
+			return fmt.Sprintf("%0*d", len(padding[1]), sequence)
+		}
+
+		if invalid == nil {
+			invalid = fmt.Errorf("%w: unknown placeholder %s", ErrInvalidNumber, placeholder)
+		}
+		return placeholder
+	})
+
+	if invalid != nil {
+		return "", invalid
+	}
+
+	return number, nil
+}
+
+// applyNumber replaces the document Ref by its expanded Number
+func (doc *Document) applyNumber() error {
+	if doc.Number == nil {
+		return nil
+	}
+
+	date := today(time.Now())
+	if len(doc.Date) > 0 {
+		parsed, err := doc.parseDate(doc.Date)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidNumber, err)
+		}
+		date = parsed
+	}
+
+	number, err := FormatNumber(doc.Number.Template, date, doc.Number.Sequence)
+	if err != nil {
+		return err
+	}
+
+	if len(number) == 0 || len(number) > 32 {
+		return fmt.Errorf("%w: %q must be 1 to 32 characters", ErrInvalidNumber, number)
+	}
+
+	doc.Ref = number
+
+	return nil
+}
@@ -3,44 +3,356 @@ package generator
 // UnicodeTranslateFunc ...
 type UnicodeTranslateFunc func(string) string
 
+// RowHookFunc is called around the rendering of each items line, y being the line top. It draws on doc.Pdf(),
+// and must restore the colors it changes. Font and position are restored after it.
+type RowHookFunc func(doc *Document, item *Item, y float64)
+
 // Options for Document
 type Options struct {
 	AutoPrint bool `json:"auto_print,omitempty"`
 
+	// ShowTaxSummary render a table of taxes grouped by rate below items
+	ShowTaxSummary bool `json:"show_tax_summary,omitempty"`
+
+	// ShowHeaderBand render the document title and ref in a full width colored band at the top of the first page,
+	// the contacts and metas are moved below it
+	ShowHeaderBand bool `json:"show_header_band,omitempty"`
+
+	// ShowTotalsDetails render the total of items and document discounts, and one tax line by rate in totals
+	ShowTotalsDetails bool `json:"show_totals_details,omitempty"`
+
+	// ShowGrandTotalBox draw a border around the total with tax line
+	ShowGrandTotalBox bool `json:"show_grand_total_box,omitempty"`
+
+	// ShowAmountDueBox render the balance due in a filled and bordered box below the totals, in large bold text.
+	// The box is filled with AmountDueBoxColor and bordered with AmountDueBorderColor, texts contrast with its fill.
+	ShowAmountDueBox bool `json:"show_amount_due_box,omitempty"`
+
+	// ShowTotalInWords render the total with tax spelled out below the totals, see Document.TotalInWords.
+	// It is skipped for languages other than English and French.
+	ShowTotalInWords bool `json:"show_total_in_words,omitempty"`
+
+	// ShowDiscountAmount render items percent discounts with their amount in a single line ex "10 % (- € 50.00)",
+	// instead of the discounted amount below the percent
+	ShowDiscountAmount bool `json:"show_discount_amount,omitempty"`
+
+	// ShowGrossUnitCost render items unit cost with tax in the unit price column, instead of the net unit cost
+	ShowGrossUnitCost bool `json:"show_gross_unit_cost,omitempty"`
+
+	// ShowLineNumbers render the items lines number in a narrow column before the ref and name
+	ShowLineNumbers bool `json:"show_line_numbers,omitempty"`
+
+	// ShowItemRef render items ref in a column before the name
+	ShowItemRef bool `json:"show_item_ref,omitempty"`
+
+	// ShowPageNumbers render TextPagination ("Page X of Y") in the footer of every page
+	ShowPageNumbers bool `json:"show_page_numbers,omitempty"`
+
+	// ShowFooterTotal render in the footer of every page the running total of the items rendered so far,
+	// titled TextFooterCarriedForward, then the document total with tax from the page of the totals block
+	ShowFooterTotal bool `json:"show_footer_total,omitempty"`
+
+	// ShowContinuedMarkers render TextContinuedNextPage below the items table when it breaks to a next page,
+	// and TextContinuedPreviousPage below its repeated header on the next page
+	ShowContinuedMarkers bool `json:"show_continued_markers,omitempty"`
+
+	// ShowRefBarcode render a Code128 barcode of the document ref below the metas, for scanning workflows
+	ShowRefBarcode bool `json:"show_ref_barcode,omitempty"`
+
+	// ShowGeneratedAt render TextGeneratedAt with the build time in the footer of every page, formatted
+	// with GeneratedAtLayout in GeneratedAtTimezone
+	ShowGeneratedAt bool `json:"show_generated_at,omitempty"`
+
+	// HideDeliveryNotePrices hide items prices and totals of delivery notes
+	HideDeliveryNotePrices bool `json:"hide_delivery_note_prices,omitempty"`
+
+	// RTL mirror the document layout (items columns, contacts, totals) and right align texts for right to left languages.
+	// Texts of UTF-8 fonts are reversed (see fpdf.RTL), they must be provided in logical order.
+	RTL bool `json:"rtl,omitempty"`
+
+	// SortItemsBy define the items rendering order, see SortItemsBy* constants. Default is insertion order.
+	SortItemsBy string `json:"sort_items_by,omitempty" validate:"omitempty,oneof=order name ref total"`
+
+	// ZebraRows fill every other items line background with ZebraRowColor, see also Item.HighlightColor
+	ZebraRows bool `json:"zebra_rows,omitempty"`
+
+	// ItemSeparator draw a rule in ItemSeparatorColor below each items line, in the middle of the rows spacing,
+	// see ItemSeparator* constants. Default is none.
+	ItemSeparator string `json:"item_separator,omitempty" validate:"omitempty,oneof=none thin dashed"`
+
+	// PageSize and Orientation of pages, see PageSize* and Orientation* constants. Layout positions
+	// are adapted to the page width, and the page height used above the footer.
+	PageSize    string `default:"A4" json:"page_size,omitempty" validate:"oneof=A4 Letter A5 Legal"`
+	Orientation string `default:"portrait" json:"orientation,omitempty" validate:"oneof=portrait landscape"`
+
+	// Margins of pages ex for pre-printed letterhead paper, defaults to 10mm sides and bottom and 20mm top.
+	// Validate returns ErrInvalidMargins when they leave no room for content.
+	Margins *Margins `json:"margins,omitempty"`
+
+	// Compact reduces items lines spacing, font sizes and table header height to fit more lines per page
+	Compact bool `json:"compact,omitempty"`
+
+	// ItemMinHeight is the minimum height (mm) of items lines, their cells are vertically centered
+	ItemMinHeight float64 `json:"item_min_height,omitempty" validate:"gte=0"`
+
+	// ItemDescriptionMaxLines truncates items descriptions to this number of lines, ended by an ellipsis.
+	// Descriptions are fully wrapped when 0.
+	ItemDescriptionMaxLines int `json:"item_description_max_lines,omitempty" validate:"gte=0"`
+
+	// ItemImageMaxWidth and ItemImageMaxHeight bound the size (mm) of items images, see Item.Image
+	ItemImageMaxWidth  float64 `default:"12" json:"item_image_max_width,omitempty" validate:"gt=0"`
+	ItemImageMaxHeight float64 `default:"12" json:"item_image_max_height,omitempty" validate:"gt=0"`
+
+	// HideEmptyColumns drop the tax and discount items columns when no item has a tax or a discount,
+	// their width is given to the name column
+	HideEmptyColumns bool `json:"hide_empty_columns,omitempty"`
+
+	// HideItemUnit disable the rendering of items unit of measure next to quantity
+	HideItemUnit bool `json:"hide_item_unit,omitempty"`
+
+	// QuantityPrecision is the maximum number of decimals of rendered quantities, trailing zeros are trimmed ex 2.5
+	QuantityPrecision int `default:"3" json:"quantity_precision" validate:"gte=0"`
+
 	CurrencySymbol    string `default:"€ " json:"currency_symbol,omitempty"`
-	CurrencyPrecision int    `default:"2" json:"currency_precision,omitempty"`
+	CurrencyCode      string `default:"EUR" json:"currency_code,omitempty"` // ISO 4217 code, used in xml exports
+	CurrencyPrecision int    `default:"2" json:"currency_precision"`
 	CurrencyDecimal   string `default:"." json:"currency_decimal,omitempty"`
 	CurrencyThousand  string `default:" " json:"currency_thousand,omitempty"`
+	CurrencyPosition  string `default:"before" json:"currency_position,omitempty" validate:"oneof=before after"` // Symbol position, see Currency
+
+	// Locale formats amounts like an ISO locale ex fr-FR (1 234,56 €), en-US ($1,234.56) or de-DE (1.234,56 €):
+	// it replaces CurrencyThousand, CurrencyDecimal, CurrencyPosition and the CurrencySymbol spacing.
+	Locale string `json:"locale,omitempty" validate:"omitempty,oneof=en-US en-GB fr-FR de-DE de-CH es-ES it-IT nl-NL"`
+
+	// RoundingMode applied to items lines and document totals, see RoundingMode* constants.
+	// Lines are rounded first, so the document totals are the sum of rounded lines.
+	RoundingMode   string `json:"rounding_mode,omitempty" validate:"omitempty,oneof=half_up half_even truncate"`
+	RoundingPlaces int    `default:"2" json:"rounding_places"`
+
+	// TaxBeforeDiscount compute percent taxes on totals before discounts, items and document ones,
+	// instead of the discounted totals. Tax inclusive items keep their gross total.
+	TaxBeforeDiscount bool `json:"tax_before_discount,omitempty"`
+
+	// CashRounding round the net payable to the nearest increment ex 0.05, the adjustment is rendered
+	// as a TextTotalRounding line (see Document.CashRoundingAdjustment)
+	CashRounding float64 `json:"cash_rounding,omitempty" validate:"gte=0"`
+
+	// Language of the document texts, see Language* constants. Empty Text* fields are set
+	// with the language translations in New, english is used for missing translations.
+	Language string `json:"language,omitempty" validate:"omitempty,oneof=en fr de es"`
+
+	// Theme of the document colors, see Theme* constants. Empty *Color fields are set with the theme
+	// colors in New, so they can be overridden in options or once New returned.
+	Theme string `json:"theme,omitempty" validate:"omitempty,oneof=classic modern-blue minimal"`
+
+	// DateLayout used to render and parse document dates, as a go time layout
+	DateLayout string `default:"01/02/2006" json:"date_layout,omitempty"`
+
+	// GeneratedAtLayout and GeneratedAtTimezone (IANA name ex Europe/Paris, local time when empty) of the
+	// footer timestamp, see ShowGeneratedAt
+	GeneratedAtLayout   string `default:"2006-01-02 15:04 MST" json:"generated_at_layout,omitempty"`
+	GeneratedAtTimezone string `json:"generated_at_timezone,omitempty"`
+
+	// TaxRateFormat used to render percent taxes rates, %s being the percent ex "%s%%" for "20%".
+	// The rate as a fraction is %[2]f ex "@ %.2[2]f" for "@ 0.20", the percent is then %[1]s.
+	TaxRateFormat string `default:"%s %%" json:"tax_rate_format,omitempty"`
 
 	TextTypeInvoice      string `default:"INVOICE" json:"text_type_invoice,omitempty"`
 	TextTypeQuotation    string `default:"QUOTATION" json:"text_type_quotation,omitempty"`
 	TextTypeDeliveryNote string `default:"DELIVERY NOTE" json:"text_type_delivery_note,omitempty"`
+	TextTypeCreditNote   string `default:"CREDIT NOTE" json:"text_type_credit_note,omitempty"`
+	TextTypeProForma     string `default:"PRO FORMA INVOICE" json:"text_type_pro_forma,omitempty"`
+
+	// TextTypeDepositInvoice replaces TextTypeInvoice when Document.Deposit is set
+	TextTypeDepositInvoice string `default:"DEPOSIT INVOICE" json:"text_type_deposit_invoice,omitempty"`
+
+	TextRefTitle          string `default:"Ref." json:"text_ref_title,omitempty"`
+	TextVersionTitle      string `default:"Version" json:"text_version_title,omitempty"`
+	TextDateTitle         string `default:"Date" json:"text_date_title,omitempty"`
+	TextPaymentTermTitle  string `default:"Payment term" json:"text_payment_term_title,omitempty"`
+	TextPaymentTermNet    string `default:"Net %d" json:"text_payment_term_net,omitempty"` // Computed payment term, %d is replaced by days until due date
+	TextDueDateTitle      string `default:"Due date" json:"text_due_date_title,omitempty"`
+	TextOverdue           string `default:"OVERDUE" json:"text_overdue,omitempty"`
+	TextValidityDateTitle string `default:"Valid until" json:"text_validity_date_title,omitempty"`
+	TextExpired           string `default:"EXPIRED" json:"text_expired,omitempty"`
+
+	// TextEarlyPaymentDiscount mention of Document.EarlyPaymentDiscount, with its percent, days and discounted net payable
+	TextEarlyPaymentDiscount string `default:"%s %% discount if paid within %d days: %s" json:"text_early_payment_discount,omitempty"`
+
+	TextStatusPaid          string `default:"PAID" json:"text_status_paid,omitempty"`
+	TextStatusPartiallyPaid string `default:"PARTIALLY PAID" json:"text_status_partially_paid,omitempty"`
+	TextStatusUnpaid        string `default:"UNPAID" json:"text_status_unpaid,omitempty"`
 
-	TextRefTitle         string `default:"Ref." json:"text_ref_title,omitempty"`
-	TextVersionTitle     string `default:"Version" json:"text_version_title,omitempty"`
-	TextDateTitle        string `default:"Date" json:"text_date_title,omitempty"`
-	TextPaymentTermTitle string `default:"Payment term" json:"text_payment_term_title,omitempty"`
+	TextContactVATNumberTitle          string `default:"VAT number" json:"text_contact_vat_number_title,omitempty"`
+	TextContactRegistrationNumberTitle string `default:"Registration number" json:"text_contact_registration_number_title,omitempty"`
+	TextContactEmailTitle              string `default:"Email" json:"text_contact_email_title,omitempty"`
+	TextContactPhoneTitle              string `default:"Phone" json:"text_contact_phone_title,omitempty"`
 
-	TextItemsNameTitle     string `default:"Name" json:"text_items_name_title,omitempty"`
-	TextItemsUnitCostTitle string `default:"Unit price" json:"text_items_unit_cost_title,omitempty"`
-	TextItemsQuantityTitle string `default:"Qty" json:"text_items_quantity_title,omitempty"`
-	TextItemsTotalHTTitle  string `default:"Total no tax" json:"text_items_total_ht_title,omitempty"`
-	TextItemsTaxTitle      string `default:"Tax" json:"text_items_tax_title,omitempty"`
-	TextItemsDiscountTitle string `default:"Discount" json:"text_items_discount_title,omitempty"`
-	TextItemsTotalTTCTitle string `default:"Total" json:"text_items_total_ttc_title,omitempty"`
+	// Customer and shipping recipient titles, rendered when Document.ShipTo differs from the customer
+	TextBillToTitle string `default:"Bill to" json:"text_bill_to_title,omitempty"`
+	TextShipToTitle string `default:"Ship to" json:"text_ship_to_title,omitempty"`
 
+	TextItemsRefTitle           string `default:"Ref." json:"text_items_ref_title,omitempty"`
+	TextItemsLineNumberTitle    string `default:"#" json:"text_items_line_number_title,omitempty"`
+	TextItemsNameTitle          string `default:"Name" json:"text_items_name_title,omitempty"`
+	TextItemsUnitCostTitle      string `default:"Unit price" json:"text_items_unit_cost_title,omitempty"`
+	TextItemsGrossUnitCostTitle string `default:"Gross price" json:"text_items_gross_unit_cost_title,omitempty"`
+	TextItemsQuantityTitle      string `default:"Qty" json:"text_items_quantity_title,omitempty"`
+	TextItemsTotalHTTitle       string `default:"Total no tax" json:"text_items_total_ht_title,omitempty"`
+	TextItemsTaxTitle           string `default:"Tax" json:"text_items_tax_title,omitempty"`
+	TextItemsDiscountTitle      string `default:"Discount" json:"text_items_discount_title,omitempty"`
+	TextItemsTotalTTCTitle      string `default:"Total" json:"text_items_total_ttc_title,omitempty"`
+	TextItemsSubtotalTitle      string `default:"Subtotal" json:"text_items_subtotal_title,omitempty"`
+
+	// Items service period, see Item.PeriodStart and Item.PeriodEnd
+	TextItemPeriod      string `default:"%s to %s" json:"text_item_period,omitempty"`
+	TextItemPeriodFrom  string `default:"From %s" json:"text_item_period_from,omitempty"`
+	TextItemPeriodUntil string `default:"Until %s" json:"text_item_period_until,omitempty"`
+
+	// TextItemOriginalAmount of items in another currency: the original total, its currency code, the rate and
+	// the document currency code, see ItemCurrency.ShowOriginal
+	TextItemOriginalAmount string `default:"%s (1 %s = %s %s)" json:"text_item_original_amount,omitempty"`
+
+	TextTotalDiscount   string `default:"TOTAL DISCOUNT" json:"text_total_discount,omitempty"`
 	TextTotalTotal      string `default:"TOTAL" json:"text_total_total,omitempty"`
 	TextTotalDiscounted string `default:"TOTAL DISCOUNTED" json:"text_total_discounted,omitempty"`
 	TextTotalTax        string `default:"TAX" json:"text_total_tax,omitempty"`
+	TextTotalShipping   string `default:"SHIPPING" json:"text_total_shipping,omitempty"`
 	TextTotalWithTax    string `default:"TOTAL WITH TAX" json:"text_total_with_tax,omitempty"`
 
+	TextReverseCharge string `default:"Reverse charge — VAT to be accounted for by the recipient" json:"text_reverse_charge,omitempty"`
+
+	TextTaxSummaryRateTitle  string `default:"Tax rate" json:"text_tax_summary_rate_title,omitempty"`
+	TextTaxSummaryBaseTitle  string `default:"Base" json:"text_tax_summary_base_title,omitempty"`
+	TextTaxSummaryTaxTitle   string `default:"Tax" json:"text_tax_summary_tax_title,omitempty"`
+	TextTaxSummaryTotalTitle string `default:"Total" json:"text_tax_summary_total_title,omitempty"`
+
+	TextProgressBillingTitle          string `default:"Progress billing" json:"text_progress_billing_title,omitempty"`
+	TextProgressBillingContractTitle  string `default:"Contract value" json:"text_progress_billing_contract_title,omitempty"`
+	TextProgressBillingPreviousTitle  string `default:"Previously invoiced" json:"text_progress_billing_previous_title,omitempty"`
+	TextProgressBillingCurrentTitle   string `default:"This invoice" json:"text_progress_billing_current_title,omitempty"`
+	TextProgressBillingRemainingTitle string `default:"Remaining to invoice" json:"text_progress_billing_remaining_title,omitempty"`
+
+	TextTotalWithholdingTax string `default:"WITHHOLDING TAX" json:"text_total_withholding_tax,omitempty"`
+	TextTotalNetPayable     string `default:"NET PAYABLE" json:"text_total_net_payable,omitempty"`
+	TextTotalAmountPaid     string `default:"AMOUNT PAID" json:"text_total_amount_paid,omitempty"`
+	TextTotalBalanceDue     string `default:"BALANCE DUE" json:"text_total_balance_due,omitempty"`
+	TextAmountDue           string `default:"AMOUNT DUE" json:"text_amount_due,omitempty"` // See ShowAmountDueBox
+	TextTotalRounding       string `default:"ROUNDING" json:"text_total_rounding,omitempty"`
+
+	TextTotalInWords string `default:"Amount in words: %s" json:"text_total_in_words,omitempty"` // See ShowTotalInWords
+
+	TextTotalDepositTax       string `default:"DEPOSIT TAX" json:"text_total_deposit_tax,omitempty"`
+	TextTotalDeposit          string `default:"DEPOSIT DUE" json:"text_total_deposit,omitempty"`
+	TextTotalDepositRemaining string `default:"REMAINING" json:"text_total_deposit_remaining,omitempty"`
+
+	TextEPCQRCodeTitle string `default:"Scan to pay" json:"text_epc_qr_code_title,omitempty"`
+
+	TextPaymentInfoTitle              string `default:"Payment details" json:"text_payment_info_title,omitempty"`
+	TextPaymentInfoAccountHolderTitle string `default:"Account holder" json:"text_payment_info_account_holder_title,omitempty"`
+	TextPaymentInfoBankTitle          string `default:"Bank" json:"text_payment_info_bank_title,omitempty"`
+	TextPaymentInfoIBANTitle          string `default:"IBAN" json:"text_payment_info_iban_title,omitempty"`
+	TextPaymentInfoBICTitle           string `default:"BIC / SWIFT" json:"text_payment_info_bic_title,omitempty"`
+	TextPaymentInfoReferenceTitle     string `default:"Reference" json:"text_payment_info_reference_title,omitempty"`
+
+	TextRemittanceSlipTitle               string `default:"REMITTANCE" json:"text_remittance_slip_title,omitempty"`
+	TextRemittanceSlipDetach              string `default:"Please detach and return this portion with your payment" json:"text_remittance_slip_detach,omitempty"`
+	TextRemittanceSlipPayableToTitle      string `default:"Make payments payable to" json:"text_remittance_slip_payable_to_title,omitempty"`
+	TextRemittanceSlipAccountTitle        string `default:"Account number" json:"text_remittance_slip_account_title,omitempty"`
+	TextRemittanceSlipAmountEnclosedTitle string `default:"Amount enclosed" json:"text_remittance_slip_amount_enclosed_title,omitempty"`
+
+	TextSwissQRBillReceiptTitle         string `default:"Receipt" json:"text_swiss_qr_bill_receipt_title,omitempty"`
+	TextSwissQRBillPaymentPartTitle     string `default:"Payment part" json:"text_swiss_qr_bill_payment_part_title,omitempty"`
+	TextSwissQRBillAccountTitle         string `default:"Account / Payable to" json:"text_swiss_qr_bill_account_title,omitempty"`
+	TextSwissQRBillReferenceTitle       string `default:"Reference" json:"text_swiss_qr_bill_reference_title,omitempty"`
+	TextSwissQRBillAdditionalInfoTitle  string `default:"Additional information" json:"text_swiss_qr_bill_additional_info_title,omitempty"`
+	TextSwissQRBillPayableByTitle       string `default:"Payable by" json:"text_swiss_qr_bill_payable_by_title,omitempty"`
+	TextSwissQRBillPayableByNameTitle   string `default:"Payable by (name/address)" json:"text_swiss_qr_bill_payable_by_name_title,omitempty"`
+	TextSwissQRBillCurrencyTitle        string `default:"Currency" json:"text_swiss_qr_bill_currency_title,omitempty"`
+	TextSwissQRBillAmountTitle          string `default:"Amount" json:"text_swiss_qr_bill_amount_title,omitempty"`
+	TextSwissQRBillAcceptancePointTitle string `default:"Acceptance point" json:"text_swiss_qr_bill_acceptance_point_title,omitempty"`
+
+	TextPagination string `default:"Page %d of %s" json:"text_pagination,omitempty"` // Page number and total pages
+
+	TextGeneratedAt string `default:"Generated on %s" json:"text_generated_at,omitempty"` // Build time, see ShowGeneratedAt
+
+	TextFooterCarriedForward string `default:"Carried forward" json:"text_footer_carried_forward,omitempty"` // Running total, see ShowFooterTotal
+
+	TextContinuedNextPage     string `default:"continued…" json:"text_continued_next_page,omitempty"`     // See ShowContinuedMarkers
+	TextContinuedPreviousPage string `default:"…continued" json:"text_continued_previous_page,omitempty"` // See ShowContinuedMarkers
+
+	TextSignatureDate string `default:"Date and signature" json:"text_signature_date,omitempty"` // Below signatures lines
+
 	BaseTextColor []int `default:"[35,35,35]" json:"base_text_color,omitempty"`
 	GreyTextColor []int `default:"[82,82,82]" json:"grey_text_color,omitempty"`
 	GreyBgColor   []int `default:"[232,232,232]" json:"grey_bg_color,omitempty"`
 	DarkBgColor   []int `default:"[212,212,212]" json:"dark_bg_color,omitempty"`
+	ZebraRowColor []int `default:"[245,245,245]" json:"zebra_row_color,omitempty" validate:"omitempty,len=3"`
+
+	// ItemSeparatorColor of the rules between items lines, see ItemSeparator
+	ItemSeparatorColor []int `default:"[212,212,212]" json:"item_separator_color,omitempty" validate:"omitempty,len=3"`
+
+	// AmountDueBoxColor and AmountDueBorderColor of the amount due box, see ShowAmountDueBox
+	AmountDueBoxColor    []int `default:"[255,244,214]" json:"amount_due_box_color,omitempty" validate:"omitempty,len=3"`
+	AmountDueBorderColor []int `default:"[230,160,0]" json:"amount_due_border_color,omitempty" validate:"omitempty,len=3"`
+
+	// HeaderBandColor and HeaderBandTextColor of the first page header band, see ShowHeaderBand.
+	// Without HeaderBandTextColor, texts are white on dark bands and BaseTextColor on light ones.
+	HeaderBandColor     []int `default:"[41,65,122]" json:"header_band_color,omitempty" validate:"omitempty,len=3"`
+	HeaderBandTextColor []int `json:"header_band_text_color,omitempty" validate:"omitempty,len=3"`
+
+	// NegativeTextColor of return lines amounts, see Item.Quantity
+	NegativeTextColor []int `default:"[192,0,0]" json:"negative_text_color,omitempty"`
+
+	// Watermark text drawn behind the content of every page ex DRAFT.
+	// It is not drawn when the header uses a custom func (see HeaderFooter.UseCustomFunc).
+	// WatermarkOpacity is from 0 to 1, a transparent watermark is set with SetWatermarkOpacity.
+	Watermark         string  `json:"watermark,omitempty"`
+	WatermarkColor    []int   `default:"[200,200,200]" json:"watermark_color,omitempty"`
+	WatermarkOpacity  float64 `default:"0.3" json:"watermark_opacity" validate:"gte=0,lte=1"`
+	WatermarkAngle    float64 `default:"45" json:"watermark_angle"` // Degrees, counter clockwise
+	WatermarkFontSize float64 `default:"80" json:"watermark_font_size,omitempty"`
+
+	// PDFA render a PDF/A-1b archival document: XMP metadata and a sRGB output intent, without transparency.
+	// Fonts must be embedded (FontFile or UTF8Font), attachments, AutoPrint and Password are not allowed.
+	// The output intent is added to the output of Document.Write and MergeDocuments, not to the pdf returned by Build.
+	PDFA bool `json:"pdfa,omitempty"`
+
+	// Password encrypt the pdf, with the passwords and permissions of readers, see Password
+	Password *Password `json:"password,omitempty"`
+
+	ColumnOffsets ColumnOffsets `json:"column_offsets,omitempty"`
+
+	// ItemColumns define the items table columns, built-in and custom ones (see ItemColumn).
+	// When empty, the built-in columns are laid out with ColumnOffsets.
+	ItemColumns []*ItemColumn `json:"item_columns,omitempty" validate:"dive"`
+
+	// Logo rendered at the top left of the first page, above the company contact
+	Logo *Logo `json:"logo,omitempty"`
+
+	Font     string `default:"Helvetica" json:"font,omitempty"`
+	BoldFont string `default:"Helvetica" json:"bold_font,omitempty"`
+
+	// Fonts of the document title, items table headers, items lines and footer, default to Font and BoldFont
+	TitleFont  *TextFont `json:"title_font,omitempty"`
+	HeaderFont *TextFont `json:"header_font,omitempty"`
+	ItemFont   *TextFont `json:"item_font,omitempty"`
+	FooterFont *TextFont `json:"footer_font,omitempty"`
+
+	// FontFile and BoldFontFile are TrueType fonts paths, embedded to render any UTF-8 text.
+	// When set, Font and BoldFont are replaced by UTF8FontFamily.
+	FontFile     string `json:"font_file,omitempty"`
+	BoldFontFile string `json:"bold_font_file,omitempty"`
+
+	// UTF8Font disable UnicodeTranslateFunc, when Font and BoldFont are UTF-8 fonts registered with Document.Pdf().AddUTF8Font
+	UTF8Font bool `json:"utf8_font,omitempty"`
 
-	Font     string `default:"Helvetica"`
-	BoldFont string `default:"Helvetica"`
+	// UnicodeTranslateFunc overrides the documents cp1252 translator, see Document.SetUnicodeTranslator.
+	// It must be safe for concurrent use when Options are shared by documents built concurrently.
+	UnicodeTranslateFunc UnicodeTranslateFunc `json:"-"`
 
-	UnicodeTranslateFunc UnicodeTranslateFunc
+	// BeforeRowHook is called before drawing each items line, above its zebra stripe, and RowHook once it is drawn.
+	// Like UnicodeTranslateFunc, they must be safe for concurrent use when Options are shared.
+	BeforeRowHook RowHookFunc `json:"-"`
+	RowHook       RowHookFunc `json:"-"`
 }
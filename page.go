@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"errors"
+	"math"
+)
+
+// Page sizes, see Options.PageSize
+const (
+	PageSizeA4     string = "A4"
+	PageSizeLetter string = "Letter"
+	PageSizeA5     string = "A5"
+	PageSizeLegal  string = "Legal"
+)
+
+// Page orientations, see Options.Orientation
+const (
+	OrientationPortrait  string = "portrait"
+	OrientationLandscape string = "landscape"
+)
+
+// Layout positions are expressed for an A4 portrait page (mm), and adapted to the page size
+const (
+	referencePageWidth  float64 = 210
+	referencePageHeight float64 = 297
+)
+
+// Margins define the page margins (mm), the layout is moved and scaled to fit between them.
+// Bottom is the distance from the page bottom to the footer.
+type Margins struct {
+	Left   float64 `json:"left" validate:"gte=0"`
+	Top    float64 `json:"top" validate:"gte=0"`
+	Right  float64 `json:"right" validate:"gte=0"`
+	Bottom float64 `json:"bottom" validate:"gte=0"`
+}
+
+// ErrInvalidMargins when Options.Margins leave no room for content on the page
+var ErrInvalidMargins = errors.New("invalid margins")
+
+// defaultMargins are the page margins when Options.Margins is not set
+var defaultMargins = Margins{Left: BaseMargin, Top: BaseMarginTop, Right: BaseMargin, Bottom: BaseMargin}
+
+// margins returns Options.Margins, or the default margins
+func (doc *Document) margins() Margins {
+	if doc.Options.Margins == nil {
+		return defaultMargins
+	}
+
+	return *doc.Options.Margins
+}
+
+// validateMargins check that margins leave room for content, between the page sides and above the footer
+func (doc *Document) validateMargins() error {
+	if doc.contentWidth() <= 0 || doc.footerY() <= doc.margins().Top {
+		return ErrInvalidMargins
+	}
+
+	return nil
+}
+
+// pdfOrientation returns the fpdf orientation of Options.Orientation
+func (o *Options) pdfOrientation() string {
+	if o.Orientation == OrientationLandscape {
+		return "L"
+	}
+
+	return "P"
+}
+
+// pageSize returns the page width and height (mm), rounded to 0.1mm since fpdf sizes are converted from points
+func (doc *Document) pageSize() (float64, float64) {
+	width, height := doc.pdf.GetPageSize()
+	return math.Round(width*10) / 10, math.Round(height*10) / 10
+}
+
+// contentWidth returns the page width between margins, 190 on A4 portrait pages
+func (doc *Document) contentWidth() float64 {
+	pageWidth, _ := doc.pageSize()
+	margins := doc.margins()
+	return pageWidth - margins.Left - margins.Right
+}
+
+// leftX returns the x of an A4 portrait position anchored to the page left edge, moved to the left margin
+func (doc *Document) leftX(x float64) float64 {
+	return x + doc.margins().Left - BaseMargin
+}
+
+// rightX returns the x of an A4 portrait position anchored to the page right edge, such as metas and
+// totals blocks, moved to the page right margin
+func (doc *Document) rightX(x float64) float64 {
+	pageWidth, _ := doc.pageSize()
+	return x + pageWidth - referencePageWidth - (doc.margins().Right - BaseMargin)
+}
+
+// topY returns the y of an A4 portrait position anchored to the page top, moved to the top margin
+func (doc *Document) topY(y float64) float64 {
+	return y + doc.margins().Top - BaseMarginTop
+}
+
+// maxPageHeight returns the maximum height of content in a page, above the footer, MaxPageHeight on A4 pages
+func (doc *Document) maxPageHeight() float64 {
+	_, pageHeight := doc.pageSize()
+	return MaxPageHeight + pageHeight - referencePageHeight - (doc.margins().Bottom - BaseMargin)
+}
+
+// footerY returns the y of the footer, the bottom margin above the page bottom
+func (doc *Document) footerY() float64 {
+	_, pageHeight := doc.pageSize()
+	return pageHeight - doc.margins().Bottom
+}
+
+// setMargins apply the document margins to its pdf
+func (doc *Document) setMargins() {
+	margins := doc.margins()
+	doc.pdf.SetMargins(margins.Left, margins.Top, margins.Right)
+}
+
+// columnOffsets returns Options.ColumnOffsets, expressed for an A4 portrait page, scaled to the page content width
+func (doc *Document) columnOffsets() ColumnOffsets {
+	cols := doc.Options.ColumnOffsets
+	scale := doc.contentWidth() / (referencePageWidth - 2*BaseMargin)
+	if scale == 1 && doc.margins().Left == BaseMargin {
+		return cols
+	}
+
+	scaled := func(x float64) float64 {
+		return doc.margins().Left + (x-BaseMargin)*scale
+	}
+
+	cols.Name = scaled(cols.Name)
+	cols.HTPrice = scaled(cols.HTPrice)
+	cols.PriceInclVAT = scaled(cols.PriceInclVAT)
+	cols.Qty = scaled(cols.Qty)
+	cols.Discount = scaled(cols.Discount)
+	cols.Tax = scaled(cols.Tax)
+	cols.TotalTTC = scaled(cols.TotalTTC)
+	cols.End = scaled(cols.End)
+	cols.RefWidth *= scale
+
+	return cols
+}
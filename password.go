@@ -0,0 +1,40 @@
+package generator
+
+import "github.com/go-pdf/fpdf"
+
+// Password protect the pdf with RC4 encryption, see Options.Password.
+// Permissions apply to readers opening the document with the user password, they are advisory.
+type Password struct {
+	User  string `json:"user,omitempty"`  // Required to open the document, empty to open it without password
+	Owner string `json:"owner,omitempty"` // Grants full access regardless of permissions, random when empty
+
+	AllowPrint       bool `json:"allow_print,omitempty"`
+	AllowModify      bool `json:"allow_modify,omitempty"`
+	AllowCopy        bool `json:"allow_copy,omitempty"`
+	AllowAnnotations bool `json:"allow_annotations,omitempty"` // Annotations and forms
+}
+
+// permissions returns the fpdf protection flags of the allowed operations
+func (p *Password) permissions() byte {
+	var flags byte
+	if p.AllowPrint {
+		flags |= fpdf.CnProtectPrint
+	}
+	if p.AllowModify {
+		flags |= fpdf.CnProtectModify
+	}
+	if p.AllowCopy {
+		flags |= fpdf.CnProtectCopy
+	}
+	if p.AllowAnnotations {
+		flags |= fpdf.CnProtectAnnotForms
+	}
+
+	return flags
+}
+
+// applyPassword encrypt the document pdf, see Options.Password
+func (doc *Document) applyPassword() {
+	password := doc.Options.Password
+	doc.pdf.SetProtection(password.permissions(), password.User, password.Owner)
+}
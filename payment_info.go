@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidIBAN when PaymentInfo.IBAN checksum is invalid, see PaymentInfo.ValidateIBAN
+var ErrInvalidIBAN = errors.New("invalid iban")
+
+// PaymentInfo define the bank transfer details rendered at the bottom of the last page
+type PaymentInfo struct {
+	AccountHolder string `json:"account_holder,omitempty"`
+	BankName      string `json:"bank_name,omitempty"`
+	IBAN          string `json:"iban,omitempty"` // Printed in groups of 4 chars, see FormatIBAN
+	BIC           string `json:"bic,omitempty"`  // BIC / SWIFT code
+	Reference     string `json:"reference,omitempty"`
+
+	// ValidateIBAN checks the IBAN checksum (mod 97) in Document.Validate
+	ValidateIBAN bool `json:"validate_iban,omitempty"`
+}
+
+// normalizeIBAN returns iban uppercased, without spaces
+func normalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+}
+
+// FormatIBAN returns iban uppercased, in groups of 4 chars ex FR76 3000 6000 0112 3456 7890 189
+func FormatIBAN(iban string) string {
+	return groupChars(normalizeIBAN(iban), 4, 4)
+}
+
+// ValidIBAN returns true when iban length, country code and mod 97 checksum are valid, spaces are ignored
+func ValidIBAN(iban string) bool {
+	iban = normalizeIBAN(iban)
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	for i, c := range iban[:4] {
+		if (i < 2 && (c < 'A' || c > 'Z')) || (i >= 2 && (c < '0' || c > '9')) {
+			return false
+		}
+	}
+
+	return validMod97(iban)
+}
+
+// validate checks the IBAN when ValidateIBAN is set
+func (p *PaymentInfo) validate() error {
+	if p.ValidateIBAN && !ValidIBAN(p.IBAN) {
+		return fmt.Errorf("%w: %s", ErrInvalidIBAN, p.IBAN)
+	}
+
+	return nil
+}
+
+// paymentInfoLines returns the rendered title / value lines, empty values are skipped
+func (doc *Document) paymentInfoLines() [][2]string {
+	p := doc.PaymentInfo
+	lines := [][2]string{}
+
+	for _, line := range [][2]string{
+		{doc.Options.TextPaymentInfoAccountHolderTitle, p.AccountHolder},
+		{doc.Options.TextPaymentInfoBankTitle, p.BankName},
+		{doc.Options.TextPaymentInfoIBANTitle, FormatIBAN(p.IBAN)},
+		{doc.Options.TextPaymentInfoBICTitle, strings.ToUpper(p.BIC)},
+		{doc.Options.TextPaymentInfoReferenceTitle, p.Reference},
+	} {
+		if len(line[1]) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// appendPaymentInfo append the payment details block at the bottom of the last page,
+// or below the content when it does not fit or a swiss QR-bill is rendered
+func (doc *Document) appendPaymentInfo() {
+	lines := doc.paymentInfoLines()
+	height := 5 + 4*float64(len(lines))
+
+	y := doc.maxPageHeight() - height
+	if doc.SwissQRBill != nil || doc.pdf.GetY()+10 > y {
+		y = doc.pdf.GetY() + 10
+		if y+height > doc.maxPageHeight() {
+			doc.pdf.AddPage()
+			y = doc.margins().Top
+		}
+	}
+
+	// Title
+	doc.pdf.SetXY(doc.rtlX(doc.margins().Left, 100), y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.CellFormat(100, 4, doc.encodeString(doc.Options.TextPaymentInfoTitle), "0", 0, doc.rtlAlign(""), false, 0, "")
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+
+	// Lines
+	for n, line := range lines {
+		lineY := y + 5 + 4*float64(n)
+
+		doc.pdf.SetXY(doc.rtlX(doc.margins().Left, 30), lineY)
+		doc.pdf.SetTextColor(
+			doc.Options.GreyTextColor[0],
+			doc.Options.GreyTextColor[1],
+			doc.Options.GreyTextColor[2],
+		)
+		doc.pdf.CellFormat(30, 4, doc.encodeString(line[0]), "0", 0, doc.rtlAlign(""), false, 0, "")
+
+		doc.pdf.SetXY(doc.rtlX(doc.margins().Left+30, 70), lineY)
+		doc.pdf.SetTextColor(
+			doc.Options.BaseTextColor[0],
+			doc.Options.BaseTextColor[1],
+			doc.Options.BaseTextColor[2],
+		)
+		doc.pdf.CellFormat(70, 4, doc.encodeString(line[1]), "0", 0, doc.rtlAlign(""), false, 0, "")
+	}
+
+	doc.pdf.SetY(y + height)
+}
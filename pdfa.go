@@ -0,0 +1,257 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrPDFAFont when a document rendered as PDF/A uses core fonts, which cannot be embedded
+	ErrPDFAFont = errors.New("pdf/a: fonts must be embedded, use Options.FontFile or Options.UTF8Font")
+
+	// ErrPDFAUnsupported when a document rendered as PDF/A uses features PDF/A-1 forbids
+	ErrPDFAUnsupported = errors.New("pdf/a: attachments, auto print and encryption are not allowed")
+
+	// ErrPDFAOutput when the pdf output cannot be completed with the PDF/A catalog entries
+	ErrPDFAOutput = errors.New("pdf/a: invalid pdf output")
+)
+
+// pdfaProducer is the producer of PDF/A documents, in their info dictionary and XMP metadata
+const pdfaProducer string = "go-invoice-generator"
+
+// pdfaOutputCondition is the output intent identifier of the embedded sRGB color profile
+const pdfaOutputCondition string = "sRGB IEC61966-2.1"
+
+// pdfaCoreFonts are the fpdf core fonts families, which are not embedded
+var pdfaCoreFonts = []string{"arial", "courier", "helvetica", "times", "symbol", "zapfdingbats"}
+
+// validatePDFA checks the document can be rendered as PDF/A-1b, see Options.PDFA
+func (doc *Document) validatePDFA() error {
+	if !doc.utf8Font() {
+		return ErrPDFAFont
+	}
+
+	families := []string{}
+	if len(doc.Options.FontFile) == 0 {
+		families = append(families, doc.Options.Font, doc.Options.BoldFont)
+	}
+	for _, font := range []*TextFont{doc.Options.TitleFont, doc.Options.HeaderFont, doc.Options.ItemFont, doc.Options.FooterFont} {
+		if font != nil {
+			families = append(families, font.Family)
+		}
+	}
+
+	for _, family := range families {
+		for _, coreFont := range pdfaCoreFonts {
+			if strings.EqualFold(family, coreFont) {
+				return ErrPDFAFont
+			}
+		}
+	}
+
+	if len(doc.Attachments) > 0 || doc.Options.AutoPrint || doc.Options.Password != nil {
+		return ErrPDFAUnsupported
+	}
+
+	return nil
+}
+
+// applyPDFA set the PDF/A-1b XMP metadata of the document pdf, with the info dictionary dates and producer
+func (doc *Document) applyPDFA() {
+	now := time.Now()
+
+	doc.pdf.SetProducer(pdfaProducer, false)
+	doc.pdf.SetCreationDate(now)
+	doc.pdf.SetModificationDate(now)
+	doc.pdf.SetXmpMetadata([]byte(fmt.Sprintf(pdfaXmp, now.Format(time.RFC3339), pdfaProducer)))
+}
+
+// opaqueImage returns imageBytes as an opaque png when the document is rendered as PDF/A and the image has
+// transparency, which fpdf renders with soft masks: the image is flattened on a white background
+func (doc *Document) opaqueImage(imageBytes []byte) ([]byte, error) {
+	if !doc.Options.PDFA {
+		return imageBytes, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(imageBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && opaque.Opaque() {
+		return imageBytes, nil
+	}
+
+	flattened := image.NewRGBA(img.Bounds())
+	draw.Draw(flattened, flattened.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(flattened, flattened.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, flattened); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+var (
+	pdfaTrailerRegexp   = regexp.MustCompile(`trailer\n<<\n/Size (\d+)\n/Root (\d+) 0 R\n/Info (\d+) 0 R\n>>\nstartxref\n(\d+)\n%%EOF\n?$`)
+	pdfaMetadataRegexp  = regexp.MustCompile(`(\d+) 0 obj\n<< /Type /Metadata /Subtype /XML`)
+	pdfaCatalogTemplate = `(?s)\n%s 0 obj\n<<\n(.*?)\n>>\nendobj\n`
+
+	// fpdf always writes an embedded files name tree, forbidden by PDF/A-1 even when empty
+	pdfaEmptyNamesRegexp = regexp.MustCompile(`\n/Names <<\n/EmbeddedFiles << /Names \[\s*\] >>\n>>`)
+)
+
+// appendPDFACatalog completes a fpdf output with the PDF/A catalog entries fpdf does not write: the XMP
+// metadata reference and the sRGB output intent. They are appended as an incremental update replacing the
+// catalog, along with the file identifier.
+func appendPDFACatalog(output []byte) ([]byte, error) {
+	trailer := pdfaTrailerRegexp.FindSubmatch(output)
+	metadata := pdfaMetadataRegexp.FindSubmatch(output)
+	if trailer == nil || metadata == nil {
+		return nil, ErrPDFAOutput
+	}
+
+	size, _ := strconv.Atoi(string(trailer[1]))
+	root, info, startxref := string(trailer[2]), string(trailer[3]), string(trailer[4])
+
+	catalog := regexp.MustCompile(fmt.Sprintf(pdfaCatalogTemplate, root)).FindSubmatch(output)
+	if catalog == nil {
+		return nil, ErrPDFAOutput
+	}
+
+	buf := bytes.NewBuffer(output)
+	if output[len(output)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+
+	// sRGB profile and output intent
+	profile := srgbProfile()
+	profileOffset := buf.Len()
+	fmt.Fprintf(buf, "%d 0 obj\n<< /N 3 /Length %d >>\nstream\n", size, len(profile))
+	buf.Write(profile)
+	buf.WriteString("\nendstream\nendobj\n")
+
+	intentOffset := buf.Len()
+	fmt.Fprintf(
+		buf,
+		"%d 0 obj\n<< /Type /OutputIntent /S /GTS_PDFA1 /OutputConditionIdentifier (%s) /Info (%s) /DestOutputProfile %d 0 R >>\nendobj\n",
+		size+1, pdfaOutputCondition, pdfaOutputCondition, size,
+	)
+
+	// Catalog replacement
+	catalogOffset := buf.Len()
+	fmt.Fprintf(
+		buf,
+		"%s 0 obj\n<<\n%s\n/Metadata %s 0 R\n/OutputIntents [%d 0 R]\n>>\nendobj\n",
+		root, pdfaEmptyNamesRegexp.ReplaceAll(catalog[1], nil), metadata[1], size+1,
+	)
+
+	// Cross-ref and trailer
+	id := fmt.Sprintf("%x", md5.Sum(output))
+	xrefOffset := buf.Len()
+	fmt.Fprintf(buf, "xref\n%s 1\n%010d 00000 n \n%d 2\n%010d 00000 n \n%010d 00000 n \n", root, catalogOffset, size, profileOffset, intentOffset)
+	fmt.Fprintf(
+		buf,
+		"trailer\n<<\n/Size %d\n/Root %s 0 R\n/Info %s 0 R\n/Prev %s\n/ID [<%s><%s>]\n>>\nstartxref\n%d\n%%%%EOF\n",
+		size+2, root, info, startxref, id, id, xrefOffset,
+	)
+
+	return buf.Bytes(), nil
+}
+
+// srgbProfile returns an ICC v2 display profile of the sRGB color space, D50 adapted with a 2.2 gamma
+func srgbProfile() []byte {
+	xyz := func(x, y, z float64) []byte {
+		tag := []byte("XYZ \x00\x00\x00\x00")
+		for _, v := range []float64{x, y, z} {
+			tag = appendUint32(tag, uint32(int32(math.Round(v*65536))))
+		}
+		return tag
+	}
+	curve := []byte("curv\x00\x00\x00\x00\x00\x00\x00\x01\x02\x33\x00\x00")
+
+	description := []byte("desc\x00\x00\x00\x00")
+	description = appendUint32(description, uint32(len(pdfaOutputCondition)+1))
+	description = append(description, pdfaOutputCondition+"\x00"...)
+	description = append(description, make([]byte, 4+4+2+1+67)...)
+
+	tags := []struct {
+		signature string
+		data      []byte
+	}{
+		{"desc", description},
+		{"cprt", []byte("text\x00\x00\x00\x00No copyright, use freely\x00")},
+		{"wtpt", xyz(0.9642, 1, 0.8249)},
+		{"rXYZ", xyz(0.4361, 0.2225, 0.0139)},
+		{"gXYZ", xyz(0.3851, 0.7169, 0.0971)},
+		{"bXYZ", xyz(0.1431, 0.0606, 0.7141)},
+		{"rTRC", curve},
+		{"gTRC", curve},
+		{"bTRC", curve},
+	}
+
+	// Tags data follows the header and tags table, 4 bytes aligned
+	table := appendUint32(nil, uint32(len(tags)))
+	data := []byte{}
+	offset := 128 + 4 + 12*len(tags)
+	for _, tag := range tags {
+		table = append(table, tag.signature...)
+		table = appendUint32(table, uint32(offset+len(data)))
+		table = appendUint32(table, uint32(len(tag.data)))
+
+		data = append(data, tag.data...)
+		for len(data)%4 != 0 {
+			data = append(data, 0)
+		}
+	}
+
+	header := make([]byte, 128)
+	binary.BigEndian.PutUint32(header[0:], uint32(offset+len(data)))
+	binary.BigEndian.PutUint32(header[8:], 0x02100000) // Version 2.1
+	copy(header[12:], "mntrRGB XYZ ")
+	binary.BigEndian.PutUint16(header[24:], 2000) // Creation date, 2000-01-01
+	binary.BigEndian.PutUint16(header[26:], 1)
+	binary.BigEndian.PutUint16(header[28:], 1)
+	copy(header[36:], "acsp")
+	copy(header[68:], xyz(0.9642, 1, 0.8249)[8:]) // D50 illuminant
+
+	return append(append(header, table...), data...)
+}
+
+// appendUint32 append v to b, big endian
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// pdfaXmp is the XMP metadata declaring PDF/A-1b conformance, with the creation date and producer of the info dictionary
+const pdfaXmp string = `<?xpacket begin="` + "\ufeff" + `" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+  <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+    <rdf:Description rdf:about="" xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/">
+      <pdfaid:part>1</pdfaid:part>
+      <pdfaid:conformance>B</pdfaid:conformance>
+    </rdf:Description>
+    <rdf:Description rdf:about="" xmlns:xmp="http://ns.adobe.com/xap/1.0/">
+      <xmp:CreateDate>%[1]s</xmp:CreateDate>
+      <xmp:ModifyDate>%[1]s</xmp:ModifyDate>
+    </rdf:Description>
+    <rdf:Description rdf:about="" xmlns:pdf="http://ns.adobe.com/pdf/1.3/">
+      <pdf:Producer>%[2]s</pdf:Producer>
+    </rdf:Description>
+  </rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`
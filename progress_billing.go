@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidContractValue when ProgressBilling.ContractValue is not positive
+var ErrInvalidContractValue = errors.New("invalid contract value")
+
+// ProgressBilling define the contract of a milestone invoice, amounts are tax included like the document total.
+// A summary table of the contract value, previously invoiced, this invoice and remaining amounts is rendered
+// below items, see Document.ProgressRemaining.
+type ProgressBilling struct {
+	ContractValue      string `json:"contract_value,omitempty" validate:"required"` // Contract total ex 10000
+	PreviouslyInvoiced string `json:"previously_invoiced,omitempty"`                // Previous invoices of the contract total ex 4000
+
+	_contractValue      decimal.Decimal
+	_previouslyInvoiced decimal.Decimal
+}
+
+// Prepare convert strings to decimal
+func (p *ProgressBilling) Prepare() error {
+	contractValue, err := parseDecimal("contract_value", p.ContractValue)
+	if err != nil {
+		return err
+	}
+	if !contractValue.IsPositive() {
+		return fmt.Errorf("%w: %s", ErrInvalidContractValue, p.ContractValue)
+	}
+	p._contractValue = contractValue
+
+	p._previouslyInvoiced = decimal.Zero
+	if len(p.PreviouslyInvoiced) > 0 {
+		previouslyInvoiced, err := parseDecimal("previously_invoiced", p.PreviouslyInvoiced)
+		if err != nil {
+			return err
+		}
+		p._previouslyInvoiced = previouslyInvoiced
+	}
+
+	return nil
+}
+
+// ProgressRemaining return the contract value left to invoice after this document total with tax,
+// negative when the contract is overbilled
+func (doc *Document) ProgressRemaining() decimal.Decimal {
+	if doc.ProgressBilling == nil {
+		return decimal.Zero
+	}
+
+	return doc.ProgressBilling._contractValue.Sub(doc.ProgressBilling._previouslyInvoiced).Sub(doc.TotalWithTax())
+}
+
+// progressPercent returns amount in percent of the contract value ex "40.00 %"
+func (doc *Document) progressPercent(amount decimal.Decimal) string {
+	percent := amount.Mul(decimal.NewFromInt(100)).Div(doc.ProgressBilling._contractValue)
+	return fmt.Sprintf("%s %%", percent.StringFixed(2))
+}
+
+// progressBillingHeight returns the height of the progress billing table
+func (doc *Document) progressBillingHeight() float64 {
+	return 16 + 6*4
+}
+
+// appendProgressBilling append the progress billing table below items
+func (doc *Document) appendProgressBilling() {
+	p := doc.ProgressBilling
+
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+
+	// Title
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	doc.pdf.Rect(doc.rtlX(doc.rightX(120), 80), doc.pdf.GetY(), 80, 6, "F")
+	doc.appendProgressBillingRow(doc.Options.TextProgressBillingTitle, "", "")
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+
+	// Lines
+	for _, line := range []struct {
+		title  string
+		amount decimal.Decimal
+	}{
+		{doc.Options.TextProgressBillingContractTitle, p._contractValue},
+		{doc.Options.TextProgressBillingPreviousTitle, p._previouslyInvoiced},
+		{doc.Options.TextProgressBillingCurrentTitle, doc.TotalWithTax()},
+		{doc.Options.TextProgressBillingRemainingTitle, doc.ProgressRemaining()},
+	} {
+		doc.pdf.SetY(doc.pdf.GetY() + 6)
+		doc.appendProgressBillingRow(line.title, doc.formatTotal(line.amount), doc.progressPercent(line.amount))
+	}
+}
+
+// appendProgressBillingRow append a title, amount and percent row of the progress billing table at current y
+func (doc *Document) appendProgressBillingRow(title string, amount string, percent string) {
+	doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 40), doc.pdf.GetY())
+	doc.pdf.CellFormat(40, 6, doc.encodeString(title), "0", 0, doc.rtlAlign("L"), false, 0, "")
+	doc.pdf.SetX(doc.rtlX(doc.rightX(160), 25))
+	doc.pdf.CellFormat(25, 6, doc.encodeString(amount), "0", 0, doc.rtlAlign("R"), false, 0, "")
+	doc.pdf.SetX(doc.rtlX(doc.rightX(185), 15))
+	doc.pdf.CellFormat(15, 6, doc.encodeString(percent), "0", 0, doc.rtlAlign("R"), false, 0, "")
+}
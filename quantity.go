@@ -0,0 +1,22 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/leekchan/accounting"
+	"github.com/shopspring/decimal"
+)
+
+// formatQuantity returns quantity without currency symbol, with at most Options.QuantityPrecision decimals
+// and the document thousand and decimal separators ex 2.5, 1 200
+func (doc *Document) formatQuantity(quantity decimal.Decimal) string {
+	formatted := accounting.FormatNumberDecimal(quantity, doc.Options.QuantityPrecision, doc.ac.Thousand, doc.ac.Decimal)
+
+	// Trim trailing zeros of the fractional part
+	if doc.Options.QuantityPrecision > 0 {
+		formatted = strings.TrimRight(formatted, "0")
+		formatted = strings.TrimSuffix(formatted, doc.ac.Decimal)
+	}
+
+	return formatted
+}
@@ -0,0 +1,22 @@
+package generator
+
+import "github.com/go-pdf/fpdf/contrib/barcode"
+
+// Ref barcode size (mm), see Options.ShowRefBarcode
+const (
+	refBarcodeWidth  float64 = 50
+	refBarcodeHeight float64 = 10
+)
+
+// appendRefBarcode append the Code128 barcode of the document ref at y, right aligned below the metas,
+// and returns its bottom y
+func (doc *Document) appendRefBarcode(y float64) (float64, error) {
+	key := barcode.RegisterCode128(doc.pdf, doc.Ref)
+	if err := doc.pdf.Error(); err != nil {
+		return y, err
+	}
+
+	barcode.Barcode(doc.pdf, key, doc.rtlX(doc.rightX(200)-refBarcodeWidth, refBarcodeWidth), y, refBarcodeWidth, refBarcodeHeight, false)
+
+	return y + refBarcodeHeight, doc.pdf.Error()
+}
@@ -0,0 +1,131 @@
+package generator
+
+import "strings"
+
+// remittanceSlipHeight is the height (mm) of the remittance slip, from its cut line to the footer
+const remittanceSlipHeight float64 = 55
+
+// RemittanceSlip define a tear-off stub rendered at the bottom of the last page, for the customer to detach
+// and mail back with their payment. It repeats the document ref, due date and balance due.
+type RemittanceSlip struct {
+	AccountNumber string   `json:"account_number,omitempty"` // Customer account number at the company
+	PayableTo     string   `json:"payable_to,omitempty"`     // Payments payee, the company name when empty
+	RemitTo       *Address `json:"remit_to,omitempty"`       // Payments mailing address, the company address when nil
+}
+
+// remittanceSlipPayableTo returns the payee of the slip payments, the document company name by default
+func (doc *Document) remittanceSlipPayableTo() string {
+	if len(doc.RemittanceSlip.PayableTo) > 0 || doc.Company == nil {
+		return doc.RemittanceSlip.PayableTo
+	}
+
+	return doc.Company.Name
+}
+
+// remittanceSlipRemitTo returns the slip payments mailing address, the document company address by default
+func (doc *Document) remittanceSlipRemitTo() *Address {
+	if doc.RemittanceSlip.RemitTo != nil || doc.Company == nil {
+		return doc.RemittanceSlip.RemitTo
+	}
+
+	return doc.Company.Address
+}
+
+// appendRemittanceSlip append the remittance slip below a dashed cut line, above the footer of the last page
+func (doc *Document) appendRemittanceSlip() {
+	s := doc.RemittanceSlip
+
+	top := doc.footerY() - remittanceSlipHeight
+	if doc.pdf.GetY() > top {
+		doc.pdf.AddPage()
+	}
+
+	// Slip is drawn over the page break margin
+	autoPageBreak, pageBreakMargin := doc.pdf.GetAutoPageBreak()
+	doc.pdf.SetAutoPageBreak(false, 0)
+
+	// Cut line
+	pageWidth, _ := doc.pdf.GetPageSize()
+	doc.pdf.SetDrawColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+	doc.pdf.SetLineWidth(0.2)
+	doc.pdf.SetDashPattern([]float64{2, 1}, 0)
+	doc.pdf.Line(0, top, pageWidth, top)
+	doc.pdf.SetDashPattern([]float64{}, 0)
+	doc.pdf.SetDrawColor(0, 0, 0)
+
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+	doc.pdf.SetXY(doc.margins().Left, top+1)
+	doc.pdf.CellFormat(doc.contentWidth(), 4, doc.encodeString(doc.Options.TextRemittanceSlipDetach), "0", 0, "C", false, 0, "")
+
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+
+	// Title and payee, on the left
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+	doc.pdf.SetXY(doc.rtlX(doc.margins().Left, 80), top+9)
+	doc.pdf.CellFormat(80, 6, doc.encodeString(doc.Options.TextRemittanceSlipTitle), "0", 0, doc.rtlAlign("L"), false, 0, "")
+
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", SmallTextFontSize)
+	doc.pdf.SetXY(doc.rtlX(doc.margins().Left, 80), top+18)
+	doc.pdf.CellFormat(80, 4, doc.encodeString(doc.Options.TextRemittanceSlipPayableToTitle), "0", 0, doc.rtlAlign("L"), false, 0, "")
+
+	payee := []string{doc.remittanceSlipPayableTo()}
+	if address := doc.remittanceSlipRemitTo(); address != nil {
+		payee = append(payee, address.lines()...)
+	}
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetXY(doc.rtlX(doc.margins().Left, 80), top+22)
+	doc.pdf.MultiCell(80, 4, doc.encodeString(strings.Join(payee, "\n")), "0", doc.rtlAlign("L"), false)
+
+	// Payment details, on the right
+	rows := [][2]string{}
+	if len(s.AccountNumber) > 0 {
+		rows = append(rows, [2]string{doc.Options.TextRemittanceSlipAccountTitle, s.AccountNumber})
+	}
+	rows = append(rows, [2]string{doc.Options.TextRefTitle, doc.Ref})
+	if len(doc.DueDate) > 0 {
+		rows = append(rows, [2]string{doc.Options.TextDueDateTitle, doc.DueDate})
+	}
+
+	y := top + 9
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	for _, row := range rows {
+		doc.appendRemittanceSlipRow(y, row[0], row[1])
+		y += 6
+	}
+
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.appendRemittanceSlipRow(y, doc.Options.TextAmountDue, doc.formatTotal(doc.BalanceDue()))
+	y += 8
+
+	// Blank field to fill by hand
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.appendRemittanceSlipRow(y, doc.Options.TextRemittanceSlipAmountEnclosedTitle, "")
+	doc.pdf.SetLineWidth(0.2)
+	doc.pdf.Rect(doc.rtlX(doc.rightX(160), 40), y-1, 40, 8, "D")
+
+	// Reset
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetAutoPageBreak(autoPageBreak, pageBreakMargin)
+	doc.pdf.SetY(doc.footerY())
+}
+
+// appendRemittanceSlipRow draw a title and value row of the remittance slip at y, in the right block
+func (doc *Document) appendRemittanceSlipRow(y float64, title string, value string) {
+	doc.pdf.SetXY(doc.rtlX(doc.rightX(120), 40), y)
+	doc.pdf.CellFormat(40, 6, doc.encodeString(title), "0", 0, doc.rtlAlign("L"), false, 0, "")
+	doc.pdf.SetXY(doc.rtlX(doc.rightX(160), 40), y)
+	doc.pdf.CellFormat(40, 6, doc.encodeString(value), "0", 0, doc.rtlAlign("R"), false, 0, "")
+}
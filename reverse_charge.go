@@ -0,0 +1,22 @@
+package generator
+
+// reverseChargeTax replaces items and shipping taxes of reverse charge documents, see Document.ReverseCharge.
+// Only VAT moves to the buyer: additional items Taxes ex eco-taxes are kept.
+var reverseChargeTax = &Tax{Percent: "0"}
+
+// appendReverseCharge append the reverse charge legal mention below totals
+func (doc *Document) appendReverseCharge() {
+	if !doc.ReverseCharge {
+		return
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+	if doc.pdf.GetY()+4 > doc.maxPageHeight() {
+		doc.pdf.AddPage()
+	}
+
+	doc.pdf.SetX(doc.rtlX(doc.margins().Left, doc.contentWidth()))
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.MultiCell(doc.contentWidth(), 4, doc.encodeString(doc.Options.TextReverseCharge), "0", doc.rtlAlign("L"), false)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+}
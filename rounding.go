@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Rounding modes
+const (
+	// RoundingModeNone keep full precision (default)
+	RoundingModeNone string = ""
+
+	// RoundingModeHalfUp round half away from zero
+	RoundingModeHalfUp string = "half_up"
+
+	// RoundingModeHalfEven round half to the nearest even digit (bankers' rounding)
+	RoundingModeHalfEven string = "half_even"
+
+	// RoundingModeTruncate drop extra digits
+	RoundingModeTruncate string = "truncate"
+)
+
+// round d with options rounding mode and places
+func (o *Options) round(d decimal.Decimal) decimal.Decimal {
+	places := int32(o.RoundingPlaces)
+
+	switch o.RoundingMode {
+	case RoundingModeHalfUp:
+		return d.Round(places)
+	case RoundingModeHalfEven:
+		return d.RoundBank(places)
+	case RoundingModeTruncate:
+		return d.Truncate(places)
+	}
+
+	return d
+}
+
+// round d with the options of the document the item belongs to
+func (i *Item) round(d decimal.Decimal) decimal.Decimal {
+	if i._options == nil {
+		return d
+	}
+
+	return i._options.round(d)
+}
@@ -0,0 +1,35 @@
+package generator
+
+import "strings"
+
+// rtlX returns the x of a w wide box, mirrored on the page when Options.RTL
+func (doc *Document) rtlX(x float64, w float64) float64 {
+	if !doc.Options.RTL {
+		return x
+	}
+
+	pageWidth, _ := doc.pdf.GetPageSize()
+	return pageWidth - x - w
+}
+
+// rtlAlign returns the cell align with left and right swapped when Options.RTL,
+// cells without horizontal align (left by default) are right aligned
+func (doc *Document) rtlAlign(align string) string {
+	if !doc.Options.RTL {
+		return align
+	}
+
+	if !strings.ContainsAny(align, "LRC") {
+		return align + "R"
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case 'L':
+			return 'R'
+		case 'R':
+			return 'L'
+		}
+		return r
+	}, align)
+}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// itemSection define a group of items sharing the same Item.Section
+type itemSection struct {
+	Title string
+	Items []*Item
+}
+
+// Subtotal returns the total with tax and discount of section items
+func (s *itemSection) Subtotal() decimal.Decimal {
+	total := decimal.NewFromInt(0)
+
+	for _, item := range s.Items {
+		total = total.Add(item._payedPriceInclVAT)
+	}
+
+	return total
+}
+
+// hasSections returns true if at least one item belongs to a section
+func (doc *Document) hasSections() bool {
+	for _, item := range doc.Items {
+		if len(item.Section) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// itemSections returns document items grouped by section, in order of first appearance once sorted.
+// Items without section are returned first, in an untitled section.
+func (doc *Document) itemSections() []*itemSection {
+	ungrouped := &itemSection{}
+	sections := []*itemSection{ungrouped}
+	sectionsByTitle := map[string]*itemSection{}
+
+	for _, item := range doc.sortedItems() {
+		if len(item.Section) == 0 {
+			ungrouped.Items = append(ungrouped.Items, item)
+			continue
+		}
+
+		section, ok := sectionsByTitle[item.Section]
+		if !ok {
+			section = &itemSection{Title: item.Section}
+			sectionsByTitle[item.Section] = section
+			sections = append(sections, section)
+		}
+
+		section.Items = append(section.Items, item)
+	}
+
+	if len(ungrouped.Items) == 0 {
+		return sections[1:]
+	}
+
+	return sections
+}
+
+// appendSectionTitle to document
+func (doc *Document) appendSectionTitle(section *itemSection) {
+	cols := doc.columnOffsets()
+
+	doc.pdf.SetX(doc.rtlX(cols.Name, doc.contentWidth()))
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.CellFormat(
+		doc.contentWidth(),
+		4,
+		doc.encodeString(section.Title),
+		"0",
+		0,
+		doc.rtlAlign(""),
+		false,
+		0,
+		"",
+	)
+	doc.setItemFont(false)
+
+	doc.pdf.SetX(doc.leftX(10))
+	doc.pdf.SetY(doc.pdf.GetY() + 6)
+}
+
+// appendSectionSubtotal to document
+func (doc *Document) appendSectionSubtotal(section *itemSection) {
+	titleColumn, amountColumn := doc.subtotalColumns()
+
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+
+	// Title
+	if titleColumn != amountColumn {
+		doc.pdf.SetX(doc.rtlX(titleColumn.X, titleColumn.Width))
+		doc.pdf.CellFormat(
+			titleColumn.Width,
+			4,
+			doc.encodeString(doc.Options.TextItemsSubtotalTitle),
+			"T",
+			0,
+			doc.rtlAlign(""),
+			false,
+			0,
+			"",
+		)
+	}
+
+	// Amount
+	doc.pdf.SetX(doc.rtlX(amountColumn.X, amountColumn.Width))
+	doc.pdf.CellFormat(
+		amountColumn.Width,
+		4,
+		doc.encodeString(doc.formatTotal(section.Subtotal())),
+		"T",
+		0,
+		doc.rtlAlign(""),
+		false,
+		0,
+		"",
+	)
+	doc.setItemFont(false)
+
+	doc.pdf.SetX(doc.leftX(10))
+	doc.pdf.SetY(doc.pdf.GetY() + 8)
+}
@@ -24,12 +24,30 @@ func (d *Document) SetRef(ref string) *Document {
 	return d
 }
 
+// SetNumber of document, expanded to its ref from template and sequence, see Number
+func (d *Document) SetNumber(template string, sequence int) *Document {
+	d.Number = &Number{Template: template, Sequence: sequence}
+	return d
+}
+
 // SetVersion of document
 func (d *Document) SetVersion(version string) *Document {
 	d.Version = version
 	return d
 }
 
+// SetTitle of document, replacing its type title
+func (d *Document) SetTitle(title string) *Document {
+	d.Title = title
+	return d
+}
+
+// SetSubtitle of document, rendered below its title
+func (d *Document) SetSubtitle(subtitle string) *Document {
+	d.Subtitle = subtitle
+	return d
+}
+
 // SetDescription of document
 func (d *Document) SetDescription(desc string) *Document {
 	d.Description = desc
@@ -42,6 +60,12 @@ func (d *Document) SetNotes(notes string) *Document {
 	return d
 }
 
+// SetTerms of document
+func (d *Document) SetTerms(terms string) *Document {
+	d.Terms = terms
+	return d
+}
+
 // SetCompany of document
 func (d *Document) SetCompany(company *Contact) *Document {
 	d.Company = company
@@ -54,6 +78,12 @@ func (d *Document) SetCustomer(customer *Contact) *Document {
 	return d
 }
 
+// SetShipTo set the shipping recipient of document, when goods are not delivered to the customer address
+func (d *Document) SetShipTo(shipTo *Contact) *Document {
+	d.ShipTo = shipTo
+	return d
+}
+
 // AppendItem to document items
 func (d *Document) AppendItem(item *Item) *Document {
 	d.Items = append(d.Items, item)
@@ -66,6 +96,30 @@ func (d *Document) SetDate(date string) *Document {
 	return d
 }
 
+// SetDueDate of document
+func (d *Document) SetDueDate(date string) *Document {
+	d.DueDate = date
+	return d
+}
+
+// SetValidityDate of document, the date quotations are valid until
+func (d *Document) SetValidityDate(date string) *Document {
+	d.ValidityDate = date
+	return d
+}
+
+// SetPaid of document
+func (d *Document) SetPaid(paid bool) *Document {
+	d.Paid = paid
+	return d
+}
+
+// SetAmountPaid of document
+func (d *Document) SetAmountPaid(amount string) *Document {
+	d.AmountPaid = amount
+	return d
+}
+
 // SetPaymentTerm of document
 func (d *Document) SetPaymentTerm(term string) *Document {
 	d.PaymentTerm = term
@@ -83,3 +137,92 @@ func (d *Document) SetDiscount(discount *Discount) *Document {
 	d.Discount = discount
 	return d
 }
+
+// SetShipping of document
+func (d *Document) SetShipping(shipping *Shipping) *Document {
+	d.Shipping = shipping
+	return d
+}
+
+// SetPaymentInfo of document
+func (d *Document) SetPaymentInfo(paymentInfo *PaymentInfo) *Document {
+	d.PaymentInfo = paymentInfo
+	return d
+}
+
+// SetReverseCharge of document
+func (d *Document) SetReverseCharge(reverseCharge bool) *Document {
+	d.ReverseCharge = reverseCharge
+	return d
+}
+
+// SetDeposit of document
+func (d *Document) SetDeposit(deposit *Deposit) *Document {
+	d.Deposit = deposit
+	return d
+}
+
+// SetEarlyPaymentDiscount of document
+func (d *Document) SetEarlyPaymentDiscount(discount *EarlyPaymentDiscount) *Document {
+	d.EarlyPaymentDiscount = discount
+	return d
+}
+
+// SetWithholdingTax of document
+func (d *Document) SetWithholdingTax(tax *Tax) *Document {
+	d.WithholdingTax = tax
+	return d
+}
+
+// SetEPCPayment of document
+func (d *Document) SetEPCPayment(payment *EPCPayment) *Document {
+	d.EPCPayment = payment
+	return d
+}
+
+// SetSwissQRBill of document
+func (d *Document) SetSwissQRBill(bill *SwissQRBill) *Document {
+	d.SwissQRBill = bill
+	return d
+}
+
+// SetRemittanceSlip of document
+func (d *Document) SetRemittanceSlip(slip *RemittanceSlip) *Document {
+	d.RemittanceSlip = slip
+	return d
+}
+
+// SetProgressBilling of document
+func (d *Document) SetProgressBilling(progress *ProgressBilling) *Document {
+	d.ProgressBilling = progress
+	return d
+}
+
+// SetCurrencyPrecision of document money amounts.
+// Use it to render currencies without fractional digits (ex JPY), as a zero
+// Options.CurrencyPrecision is replaced by its default value.
+func (d *Document) SetCurrencyPrecision(precision int) *Document {
+	d.Options.CurrencyPrecision = precision
+	return d
+}
+
+// SetQuantityPrecision of document items quantities.
+// Use it to render quantities rounded to integers, as a zero Options.QuantityPrecision is replaced by its default value.
+func (d *Document) SetQuantityPrecision(precision int) *Document {
+	d.Options.QuantityPrecision = precision
+	return d
+}
+
+// SetWatermarkOpacity of document watermark, from 0 to 1.
+// Use it to set a transparent watermark, as a zero Options.WatermarkOpacity is replaced by its default value.
+func (d *Document) SetWatermarkOpacity(opacity float64) *Document {
+	d.Options.WatermarkOpacity = opacity
+	return d
+}
+
+// SetRoundingPlaces of document lines and totals.
+// Use it to round to whole units, as a zero Options.RoundingPlaces is replaced by its default value.
+func (d *Document) SetRoundingPlaces(places int) *Document {
+	d.Options.RoundingPlaces = places
+	return d
+}
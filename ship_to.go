@@ -0,0 +1,54 @@
+package generator
+
+// showShipTo returns true when the shipping recipient is set and differs from the customer, see Document.ShipTo
+func (doc *Document) showShipTo() bool {
+	return doc.ShipTo != nil && !doc.ShipTo.sameRecipient(doc.Customer)
+}
+
+// sameRecipient returns true when c and other have the same name and address
+func (c *Contact) sameRecipient(other *Contact) bool {
+	if c.Name != other.Name || c.Country != other.Country || c.AddressLine != other.AddressLine ||
+		c.ZipCode != other.ZipCode || c.City != other.City {
+		return false
+	}
+
+	if c.Address == nil || other.Address == nil {
+		return c.Address == other.Address
+	}
+
+	return *c.Address == *other.Address
+}
+
+// appendRecipients append the customer contact at y, followed by the shipping recipient when it differs,
+// each one below its title. Returns the bottom of the last contact.
+func (doc *Document) appendRecipients(y float64) float64 {
+	if !doc.showShipTo() {
+		return doc.Customer.appendCustomerContactToDoc(doc, y)
+	}
+
+	customerBottom := doc.Customer.appendCustomerContactToDoc(doc, doc.appendRecipientTitle(doc.Options.TextBillToTitle, y))
+
+	return doc.ShipTo.appendCustomerContactToDoc(doc, doc.appendRecipientTitle(doc.Options.TextShipToTitle, customerBottom+4))
+}
+
+// appendRecipientTitle append a recipient contact title at y, returns the y of the contact below
+func (doc *Document) appendRecipientTitle(title string, y float64) float64 {
+	doc.pdf.SetXY(doc.rtlX(doc.rightX(130), 70), y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", SmallTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+	doc.pdf.CellFormat(70, 4, doc.encodeString(title), "0", 0, doc.rtlAlign("L"), false, 0, "")
+
+	// Reset font
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+
+	return y + 5
+}
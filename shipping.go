@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Shipping define a shipping / handling cost, added to the totals after the document discount.
+// Shipping is taxed with Tax only, a nil Tax means untaxed shipping (the document default tax does not apply).
+type Shipping struct {
+	Amount string `json:"amount,omitempty" validate:"required"` // Amount without tax ex 12.50
+	Tax    *Tax   `json:"tax,omitempty"`
+
+	_amount decimal.Decimal
+}
+
+// Prepare convert strings to decimal
+func (s *Shipping) Prepare() error {
+	amount, err := parseDecimal("amount", s.Amount)
+	if err != nil {
+		return err
+	}
+	s._amount = amount
+
+	if s.Tax != nil {
+		if err := s.Tax.Prepare(); err != nil {
+			return fmt.Errorf("tax: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// shippingAmount returns the document shipping amount without tax
+func (doc *Document) shippingAmount() decimal.Decimal {
+	if doc.Shipping == nil {
+		return decimal.Zero
+	}
+
+	return doc.Shipping._amount
+}
+
+// effectiveShippingTax returns the tax applied to shipping, 0% under reverse charge. Shipping.Tax is left as set.
+func (doc *Document) effectiveShippingTax() *Tax {
+	if doc.Shipping == nil {
+		return nil
+	}
+
+	if doc.ReverseCharge {
+		return reverseChargeTax
+	}
+
+	return doc.Shipping.Tax
+}
+
+// shippingTax returns the document shipping tax
+func (doc *Document) shippingTax() decimal.Decimal {
+	tax := doc.effectiveShippingTax()
+	if tax == nil {
+		return decimal.Zero
+	}
+
+	taxType, taxAmount := tax.getTax()
+	if taxType == TaxTypeAmount {
+		return taxAmount
+	}
+
+	return doc.Options.round(doc.Shipping._amount.Mul(taxAmount).Div(decimal.NewFromFloat(100)))
+}
+
+// showShipping returns true when the shipping line must be rendered, zero shipping is hidden
+func (doc *Document) showShipping() bool {
+	return doc.Shipping != nil && !doc.shippingAmount().Add(doc.shippingTax()).IsZero()
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// ErrInvalidSignature when a signature image cannot be read or decoded
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// signatureHeight is the height (mm) of signature areas: label, image and line, then date label
+const signatureHeight float64 = 32
+
+// Signature define a signature area rendered at the bottom of the last page, with a line to sign on.
+// An optional signature image is drawn above the line, read from Image or from the file at ImagePath.
+type Signature struct {
+	Label     string `json:"label,omitempty" validate:"required"` // ex For the company, Customer acceptance
+	Image     []byte `json:"image,omitempty"`
+	ImagePath string `json:"image_path,omitempty"`
+}
+
+// AppendSignature area to document, the first one is rendered on the left and the second on the right
+func (d *Document) AppendSignature(signature *Signature) *Document {
+	d.Signatures = append(d.Signatures, signature)
+	return d
+}
+
+// appendSignatures to the bottom of the page, above the footer
+func (doc *Document) appendSignatures() error {
+	y := doc.maxPageHeight() - signatureHeight
+	if doc.pdf.GetY()+10 > y {
+		doc.pdf.AddPage()
+	}
+
+	for index, signature := range doc.Signatures {
+		x := doc.margins().Left
+		if index > 0 {
+			x = doc.rightX(120)
+		}
+
+		if err := doc.appendSignature(signature, x, y); err != nil {
+			return fmt.Errorf("signature %d: %w", index, err)
+		}
+	}
+
+	doc.pdf.SetY(doc.maxPageHeight())
+
+	return nil
+}
+
+// appendSignature area at x, y
+func (doc *Document) appendSignature(signature *Signature, x float64, y float64) error {
+	width := 80.0
+
+	// Label
+	doc.pdf.SetXY(doc.rtlX(x, width), y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.CellFormat(width, 4, doc.encodeString(signature.Label), "0", 0, doc.rtlAlign("L"), false, 0, "")
+
+	// Image, fitted above the line
+	if len(signature.Image) > 0 || len(signature.ImagePath) > 0 {
+		imageBytes := signature.Image
+		if len(imageBytes) == 0 {
+			fileBytes, err := os.ReadFile(signature.ImagePath)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+			}
+			imageBytes = fileBytes
+		}
+
+		name := fmt.Sprintf("signature-%x", sha1.Sum(imageBytes))
+		imageInfo, format, err := doc.registerImage(name, imageBytes)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+		}
+
+		maxHeight := 20.0
+		imageWidth, imageHeight := maxHeight*imageInfo.Width()/imageInfo.Height(), maxHeight
+		if imageWidth > width {
+			imageWidth, imageHeight = width, width*imageInfo.Height()/imageInfo.Width()
+		}
+
+		doc.pdf.ImageOptions(name, doc.rtlX(x, imageWidth), y+25-imageHeight, imageWidth, imageHeight, false, fpdf.ImageOptions{ImageType: format}, 0, "")
+	}
+
+	// Line to sign on
+	doc.pdf.SetDrawColor(doc.Options.BaseTextColor[0], doc.Options.BaseTextColor[1], doc.Options.BaseTextColor[2])
+	doc.pdf.Line(doc.rtlX(x, width), y+26, doc.rtlX(x, width)+width, y+26)
+	doc.pdf.SetDrawColor(0, 0, 0)
+
+	// Date
+	doc.pdf.SetXY(doc.rtlX(x, width), y+27)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.SetTextColor(doc.Options.GreyTextColor[0], doc.Options.GreyTextColor[1], doc.Options.GreyTextColor[2])
+	doc.pdf.CellFormat(width, 4, doc.encodeString(doc.Options.TextSignatureDate), "0", 0, doc.rtlAlign("L"), false, 0, "")
+	doc.pdf.SetTextColor(doc.Options.BaseTextColor[0], doc.Options.BaseTextColor[1], doc.Options.BaseTextColor[2])
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+
+	return doc.pdf.Error()
+}
@@ -0,0 +1,39 @@
+package generator
+
+import "sort"
+
+// Items sort modes, see Options.SortItemsBy
+const (
+	SortItemsByInsertion string = ""
+	SortItemsByOrder     string = "order"
+	SortItemsByName      string = "name"
+	SortItemsByRef       string = "ref"
+	SortItemsByTotal     string = "total"
+)
+
+// sortedItems returns document items in rendering order, see Options.SortItemsBy.
+// The sort is stable, items with equal keys keep their insertion order.
+func (doc *Document) sortedItems() []*Item {
+	items := make([]*Item, len(doc.Items))
+	copy(items, doc.Items)
+
+	var less func(a *Item, b *Item) bool
+	switch doc.Options.SortItemsBy {
+	case SortItemsByOrder:
+		less = func(a *Item, b *Item) bool { return a.Order < b.Order }
+	case SortItemsByName:
+		less = func(a *Item, b *Item) bool { return a.Name < b.Name }
+	case SortItemsByRef:
+		less = func(a *Item, b *Item) bool { return a.Ref < b.Ref }
+	case SortItemsByTotal:
+		less = func(a *Item, b *Item) bool { return a._payedPriceInclVAT.LessThan(b._payedPriceInclVAT) }
+	default:
+		return items
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return less(items[i], items[j])
+	})
+
+	return items
+}
@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Document payment statuses, see Document.Status
+const (
+	StatusPaid          string = "PAID"
+	StatusPartiallyPaid string = "PARTIALLY_PAID"
+	StatusUnpaid        string = "UNPAID"
+)
+
+// BalanceDue returns the net payable minus the amount already paid
+func (doc *Document) BalanceDue() decimal.Decimal {
+	return doc.NetPayable().Sub(doc._amountPaid)
+}
+
+// Status returns the document payment status, paid when Document.Paid is true or when the balance due is zero
+func (doc *Document) Status() string {
+	if doc.Paid || (len(doc.AmountPaid) > 0 && !doc.BalanceDue().IsPositive()) {
+		return StatusPaid
+	}
+
+	if doc._amountPaid.IsPositive() {
+		return StatusPartiallyPaid
+	}
+
+	return StatusUnpaid
+}
+
+// showStatus returns true when payment status must be rendered
+func (doc *Document) showStatus() bool {
+	return (doc.Paid || len(doc.AmountPaid) > 0) && !doc.hidePrices()
+}
+
+// appendStatus stamp at the right of y, returns the stamp bottom y
+func (doc *Document) appendStatus(y float64) float64 {
+	var text string
+	var color []int
+
+	switch doc.Status() {
+	case StatusPaid:
+		text, color = doc.Options.TextStatusPaid, []int{0, 140, 60}
+	case StatusPartiallyPaid:
+		text, color = doc.Options.TextStatusPartiallyPaid, []int{220, 120, 0}
+	default:
+		text, color = doc.Options.TextStatusUnpaid, []int{200, 0, 0}
+	}
+
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+	width := doc.pdf.GetStringWidth(doc.encodeString(text)) + 6
+
+	doc.pdf.SetDrawColor(color[0], color[1], color[2])
+	doc.pdf.SetTextColor(color[0], color[1], color[2])
+	doc.pdf.SetLineWidth(0.5)
+	doc.pdf.SetXY(doc.rtlX(doc.rightX(200)-width, width), y+1)
+	doc.pdf.CellFormat(width, 6, doc.encodeString(text), "1", 0, "C", false, 0, "")
+
+	// Reset
+	doc.pdf.SetLineWidth(0.2)
+	doc.pdf.SetDrawColor(0, 0, 0)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+
+	return y + 8
+}
+
+// appendAmountPaid lines below totals
+func (doc *Document) appendAmountPaid() {
+	doc.appendTotalLine(
+		doc.Options.TextTotalAmountPaid,
+		doc.formatTotal(doc._amountPaid.Neg()),
+	)
+	doc.appendTotalLine(
+		doc.Options.TextTotalBalanceDue,
+		doc.formatTotal(doc.BalanceDue()),
+	)
+}
+
+// prepareAmountPaid convert amount paid string to decimal
+func (doc *Document) prepareAmountPaid() error {
+	doc._amountPaid = decimal.Zero
+	if len(doc.AmountPaid) == 0 {
+		return nil
+	}
+
+	amountPaid, err := parseDecimal("amount_paid", doc.AmountPaid)
+	if err != nil {
+		return err
+	}
+	doc._amountPaid = amountPaid
+
+	return nil
+}
@@ -0,0 +1,406 @@
+package generator
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf/contrib/barcode"
+	"github.com/shopspring/decimal"
+)
+
+// ErrSwissQRBillInvalidCurrency when document currency is neither CHF nor EUR
+var ErrSwissQRBillInvalidCurrency = errors.New("swiss qr-bill: currency must be CHF or EUR")
+
+// ErrSwissQRBillInvalidAmount when document net payable is out of range (0.01 to 999999999.99)
+var ErrSwissQRBillInvalidAmount = errors.New("swiss qr-bill: invalid amount")
+
+// ErrSwissQRBillInvalidAccount when account is not a swiss or liechtenstein IBAN
+var ErrSwissQRBillInvalidAccount = errors.New("swiss qr-bill: account must be a CH or LI IBAN")
+
+// ErrSwissQRBillInvalidReference when reference checksum is invalid, or does not match the account type
+var ErrSwissQRBillInvalidReference = errors.New("swiss qr-bill: invalid reference")
+
+// Swiss QR-bill reference types
+const (
+	// SwissQRBillReferenceQRR QR reference, 27 digits, required with a QR-IBAN
+	SwissQRBillReferenceQRR string = "QRR"
+
+	// SwissQRBillReferenceSCOR creditor reference (ISO 11649)
+	SwissQRBillReferenceSCOR string = "SCOR"
+
+	// SwissQRBillReferenceNON without reference
+	SwissQRBillReferenceNON string = "NON"
+)
+
+// swissQRBillHeight define the height of the payment slip at the bottom of the page
+const swissQRBillHeight float64 = 105
+
+// SwissQRBillAddress define a structured address of a swiss QR-bill party
+type SwissQRBillAddress struct {
+	Name           string `json:"name,omitempty" validate:"required,min=1,max=70"`
+	Street         string `json:"street,omitempty" validate:"max=70"`
+	BuildingNumber string `json:"building_number,omitempty" validate:"max=16"`
+	PostalCode     string `json:"postal_code,omitempty" validate:"required,max=16"`
+	Town           string `json:"town,omitempty" validate:"required,max=35"`
+	Country        string `json:"country,omitempty" validate:"required,len=2"` // ISO 3166-1 alpha-2
+}
+
+// SwissQRBill define the informations of a swiss QR-bill payment slip.
+// The amount is the document net payable, and the currency the document currency code (CHF or EUR).
+type SwissQRBill struct {
+	Account        string              `json:"account,omitempty" validate:"required"` // IBAN or QR-IBAN
+	Creditor       *SwissQRBillAddress `json:"creditor,omitempty" validate:"required"`
+	Debtor         *SwissQRBillAddress `json:"debtor,omitempty"`
+	Reference      string              `json:"reference,omitempty"` // QR reference (27 digits) or creditor reference (RF...)
+	AdditionalInfo string              `json:"additional_info,omitempty" validate:"max=140"`
+}
+
+// account returns the account without spaces
+func (b *SwissQRBill) account() string {
+	return strings.ToUpper(strings.ReplaceAll(b.Account, " ", ""))
+}
+
+// reference returns the reference without spaces
+func (b *SwissQRBill) reference() string {
+	return strings.ToUpper(strings.ReplaceAll(b.Reference, " ", ""))
+}
+
+// isQRIBAN returns true when account institution id is in the QR-IID range (30000 to 31999)
+func (b *SwissQRBill) isQRIBAN() bool {
+	account := b.account()
+	if len(account) < 9 {
+		return false
+	}
+
+	iid, err := strconv.Atoi(account[4:9])
+	return err == nil && iid >= 30000 && iid <= 31999
+}
+
+// ReferenceType returns the reference type (QRR, SCOR or NON)
+func (b *SwissQRBill) ReferenceType() string {
+	ref := b.reference()
+
+	switch {
+	case len(ref) == 0:
+		return SwissQRBillReferenceNON
+	case strings.HasPrefix(ref, "RF"):
+		return SwissQRBillReferenceSCOR
+	default:
+		return SwissQRBillReferenceQRR
+	}
+}
+
+// validate account and reference
+func (b *SwissQRBill) validate() error {
+	account := b.account()
+	if len(account) != 21 || (!strings.HasPrefix(account, "CH") && !strings.HasPrefix(account, "LI")) {
+		return ErrSwissQRBillInvalidAccount
+	}
+
+	ref := b.reference()
+	switch b.ReferenceType() {
+	case SwissQRBillReferenceQRR:
+		if !b.isQRIBAN() || len(ref) != 27 || qrReferenceCheckDigit(ref[:26]) != int(ref[26]-'0') {
+			return ErrSwissQRBillInvalidReference
+		}
+	case SwissQRBillReferenceSCOR:
+		if b.isQRIBAN() || len(ref) < 5 || len(ref) > 25 || !validMod97(ref) {
+			return ErrSwissQRBillInvalidReference
+		}
+	default:
+		if b.isQRIBAN() {
+			return ErrSwissQRBillInvalidReference
+		}
+	}
+
+	return nil
+}
+
+// qrReferenceCheckDigit returns the recursive mod 10 check digit of digits, or -1 when digits contains non digit chars
+func qrReferenceCheckDigit(digits string) int {
+	table := []int{0, 9, 4, 6, 8, 2, 7, 1, 3, 5}
+	carry := 0
+
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return -1
+		}
+		carry = table[(carry+int(c-'0'))%10]
+	}
+
+	return (10 - carry) % 10
+}
+
+// validMod97 returns true when ref checksum is valid once its first 4 chars are moved to the end,
+// as ISO 11649 creditor references and IBAN (ISO 7064 mod 97-10)
+func validMod97(ref string) bool {
+	var digits strings.Builder
+	for _, c := range ref[4:] + ref[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			digits.WriteRune(c)
+		case c >= 'A' && c <= 'Z':
+			digits.WriteString(strconv.Itoa(int(c-'A') + 10))
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && n.Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// lines returns the address payload lines
+func (a *SwissQRBillAddress) lines() []string {
+	if a == nil {
+		return make([]string, 7)
+	}
+
+	return []string{"S", a.Name, a.Street, a.BuildingNumber, a.PostalCode, a.Town, strings.ToUpper(a.Country)}
+}
+
+// displayLines returns the address lines rendered on the payment slip
+func (a *SwissQRBillAddress) displayLines() []string {
+	lines := []string{a.Name}
+
+	if street := strings.TrimSpace(a.Street + " " + a.BuildingNumber); len(street) > 0 {
+		lines = append(lines, street)
+	}
+
+	return append(lines, strings.ToUpper(a.Country)+"-"+a.PostalCode+" "+a.Town)
+}
+
+// swissQRBillAmount returns the document net payable rounded to 2 digits
+func (doc *Document) swissQRBillAmount() (decimal.Decimal, error) {
+	amount := doc.NetPayable().Round(2)
+	if amount.LessThan(decimal.New(1, -2)) || amount.GreaterThan(decimal.RequireFromString("999999999.99")) {
+		return amount, ErrSwissQRBillInvalidAmount
+	}
+
+	return amount, nil
+}
+
+// SwissQRBillPayload returns the swiss QR-bill QR code payload of the document (Swiss Payments Code, version 2.0)
+func (doc *Document) SwissQRBillPayload() (string, error) {
+	b := doc.SwissQRBill
+
+	if doc.Options.CurrencyCode != "CHF" && doc.Options.CurrencyCode != "EUR" {
+		return "", ErrSwissQRBillInvalidCurrency
+	}
+
+	if err := b.validate(); err != nil {
+		return "", err
+	}
+
+	amount, err := doc.swissQRBillAmount()
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{"SPC", "0200", "1", b.account()}
+	lines = append(lines, b.Creditor.lines()...)
+	lines = append(lines, make([]string, 7)...) // Ultimate creditor, reserved for future use
+	lines = append(lines, amount.StringFixed(2), doc.Options.CurrencyCode)
+	lines = append(lines, b.Debtor.lines()...)
+	lines = append(lines, b.ReferenceType(), b.reference(), b.AdditionalInfo, "EPD")
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// groupChars returns s with a space every size chars, starting with a first group of first chars
+func groupChars(s string, first int, size int) string {
+	if len(s) <= first {
+		return s
+	}
+
+	groups := []string{s[:first]}
+	for i := first; i < len(s); i += size {
+		end := i + size
+		if end > len(s) {
+			end = len(s)
+		}
+		groups = append(groups, s[i:end])
+	}
+
+	return strings.Join(groups, " ")
+}
+
+// displayReference returns the reference grouped as printed on the payment slip
+func (b *SwissQRBill) displayReference() string {
+	ref := b.reference()
+
+	if b.ReferenceType() == SwissQRBillReferenceQRR {
+		return groupChars(ref, 2, 5)
+	}
+
+	return groupChars(ref, 4, 4)
+}
+
+// appendSwissQRBill to the bottom of the last page, on a new page when content overlaps the payment slip
+func (doc *Document) appendSwissQRBill() error {
+	payload, err := doc.SwissQRBillPayload()
+	if err != nil {
+		return err
+	}
+
+	amount, _ := doc.swissQRBillAmount()
+	b := doc.SwissQRBill
+
+	pageWidth, pageHeight := doc.pdf.GetPageSize()
+	top := pageHeight - swissQRBillHeight
+	if doc.pdf.GetY() > top {
+		doc.pdf.AddPage()
+	}
+
+	// Slip is drawn over the bottom margin
+	autoPageBreak, pageBreakMargin := doc.pdf.GetAutoPageBreak()
+	doc.pdf.SetAutoPageBreak(false, 0)
+
+	// Separators
+	doc.pdf.SetDrawColor(0, 0, 0)
+	doc.pdf.SetLineWidth(0.2)
+	doc.pdf.SetDashPattern([]float64{1, 1}, 0)
+	doc.pdf.Line(0, top, pageWidth, top)
+	doc.pdf.Line(62, top, 62, pageHeight)
+	doc.pdf.SetDashPattern([]float64{}, 0)
+
+	account := groupChars(b.account(), 4, 4)
+	creditor := append([]string{account}, b.Creditor.displayLines()...)
+	amountString := swissQRBillAmountString(amount)
+
+	// Receipt
+	doc.pdf.SetTextColor(0, 0, 0)
+	doc.pdf.SetXY(5, top+5)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", 11)
+	doc.pdf.CellFormat(52, 7, doc.encodeString(doc.Options.TextSwissQRBillReceiptTitle), "0", 0, "L", false, 0, "")
+
+	y := top + 12
+	y = doc.appendSwissQRBillBlock(5, y, 52, 6, 8, doc.Options.TextSwissQRBillAccountTitle, creditor)
+	if b.ReferenceType() != SwissQRBillReferenceNON {
+		y = doc.appendSwissQRBillBlock(5, y, 52, 6, 8, doc.Options.TextSwissQRBillReferenceTitle, []string{b.displayReference()})
+	}
+	if b.Debtor != nil {
+		doc.appendSwissQRBillBlock(5, y, 52, 6, 8, doc.Options.TextSwissQRBillPayableByTitle, b.Debtor.displayLines())
+	} else {
+		doc.appendSwissQRBillBlock(5, y, 52, 6, 8, doc.Options.TextSwissQRBillPayableByNameTitle, nil)
+		doc.drawCornerMarks(5, y+4, 52, 20)
+	}
+
+	doc.appendSwissQRBillBlock(5, top+68, 12, 6, 8, doc.Options.TextSwissQRBillCurrencyTitle, []string{doc.Options.CurrencyCode})
+	doc.appendSwissQRBillBlock(17, top+68, 40, 6, 8, doc.Options.TextSwissQRBillAmountTitle, []string{amountString})
+
+	doc.pdf.SetXY(5, top+82)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", 6)
+	doc.pdf.CellFormat(52, 3, doc.encodeString(doc.Options.TextSwissQRBillAcceptancePointTitle), "0", 0, "R", false, 0, "")
+
+	// Payment part
+	doc.pdf.SetXY(67, top+5)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", 11)
+	doc.pdf.CellFormat(51, 7, doc.encodeString(doc.Options.TextSwissQRBillPaymentPartTitle), "0", 0, "L", false, 0, "")
+
+	// QR code with swiss cross, error correction level M
+	key := barcode.RegisterQR(doc.pdf, payload, qr.M, qr.Unicode)
+	barcode.Barcode(doc.pdf, key, 67, top+17, 46, 46, false)
+	doc.drawSwissCross(67+23, top+17+23)
+
+	doc.appendSwissQRBillBlock(67, top+68, 13, 8, 10, doc.Options.TextSwissQRBillCurrencyTitle, []string{doc.Options.CurrencyCode})
+	doc.appendSwissQRBillBlock(80, top+68, 38, 8, 10, doc.Options.TextSwissQRBillAmountTitle, []string{amountString})
+
+	y = top + 5
+	y = doc.appendSwissQRBillBlock(118, y, 87, 8, 10, doc.Options.TextSwissQRBillAccountTitle, creditor)
+	if b.ReferenceType() != SwissQRBillReferenceNON {
+		y = doc.appendSwissQRBillBlock(118, y, 87, 8, 10, doc.Options.TextSwissQRBillReferenceTitle, []string{b.displayReference()})
+	}
+	if len(b.AdditionalInfo) > 0 {
+		y = doc.appendSwissQRBillBlock(118, y, 87, 8, 10, doc.Options.TextSwissQRBillAdditionalInfoTitle, []string{b.AdditionalInfo})
+	}
+	if b.Debtor != nil {
+		doc.appendSwissQRBillBlock(118, y, 87, 8, 10, doc.Options.TextSwissQRBillPayableByTitle, b.Debtor.displayLines())
+	} else {
+		doc.appendSwissQRBillBlock(118, y, 87, 8, 10, doc.Options.TextSwissQRBillPayableByNameTitle, nil)
+		doc.drawCornerMarks(118, y+5, 65, 25)
+	}
+
+	// Reset font, colors and page break
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+	doc.pdf.SetAutoPageBreak(autoPageBreak, pageBreakMargin)
+
+	return doc.pdf.Error()
+}
+
+// swissQRBillAmountString returns amount with space as thousands separator and dot as decimal separator
+func swissQRBillAmountString(amount decimal.Decimal) string {
+	parts := strings.Split(amount.StringFixed(2), ".")
+
+	var thousands []string
+	for len(parts[0]) > 3 {
+		thousands = append([]string{parts[0][len(parts[0])-3:]}, thousands...)
+		parts[0] = parts[0][:len(parts[0])-3]
+	}
+	thousands = append([]string{parts[0]}, thousands...)
+
+	return strings.Join(thousands, " ") + "." + parts[1]
+}
+
+// appendSwissQRBillBlock appends a heading and its value lines at x y, returns the block bottom y
+func (doc *Document) appendSwissQRBillBlock(
+	x float64,
+	y float64,
+	width float64,
+	headingSize float64,
+	valueSize float64,
+	heading string,
+	lines []string,
+) float64 {
+	headingHeight := headingSize * 0.45
+	valueHeight := valueSize * 0.45
+
+	doc.pdf.SetXY(x, y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", headingSize)
+	doc.pdf.CellFormat(width, headingHeight, doc.encodeString(heading), "0", 0, "L", false, 0, "")
+	doc.pdf.SetXY(x, y+headingHeight)
+
+	doc.pdf.SetFont(doc.Options.Font, "", valueSize)
+	for _, line := range lines {
+		doc.pdf.SetX(x)
+		doc.pdf.MultiCell(width, valueHeight, doc.encodeString(line), "0", "L", false)
+	}
+
+	return doc.pdf.GetY() + valueHeight
+}
+
+// drawCornerMarks draws the corners of a blank field to fill by hand
+func (doc *Document) drawCornerMarks(x float64, y float64, w float64, h float64) {
+	doc.pdf.SetLineWidth(0.25)
+
+	mark := 3.0
+	doc.pdf.Line(x, y, x+mark, y)
+	doc.pdf.Line(x, y, x, y+mark)
+	doc.pdf.Line(x+w-mark, y, x+w, y)
+	doc.pdf.Line(x+w, y, x+w, y+mark)
+	doc.pdf.Line(x, y+h, x+mark, y+h)
+	doc.pdf.Line(x, y+h-mark, x, y+h)
+	doc.pdf.Line(x+w-mark, y+h, x+w, y+h)
+	doc.pdf.Line(x+w, y+h-mark, x+w, y+h)
+}
+
+// drawSwissCross draws the 7mm swiss cross centered at cx cy
+func (doc *Document) drawSwissCross(cx float64, cy float64) {
+	doc.pdf.SetFillColor(255, 255, 255)
+	doc.pdf.Rect(cx-3.5, cy-3.5, 7, 7, "F")
+
+	doc.pdf.SetFillColor(0, 0, 0)
+	doc.pdf.Rect(cx-3, cy-3, 6, 6, "F")
+
+	doc.pdf.SetFillColor(255, 255, 255)
+	doc.pdf.Rect(cx-1.95, cy-0.6, 3.9, 1.2, "F")
+	doc.pdf.Rect(cx-0.6, cy-1.95, 1.2, 3.9, "F")
+}
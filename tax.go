@@ -2,6 +2,8 @@ package generator
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/shopspring/decimal"
 )
@@ -17,14 +19,12 @@ const (
 
 // Tax define tax as percent or fixed amount
 type Tax struct {
+	Name    string `json:"name,omitempty"`    // Tax name ex GST, totals are then rendered by name
 	Percent string `json:"percent,omitempty"` // Tax in percent ex 17
 	Amount  string `json:"amount,omitempty"`  // Tax in amount ex 123.40
-
-	_percent decimal.Decimal
-	_amount  decimal.Decimal
 }
 
-// Prepare convert strings to decimal
+// Prepare check strings are valid decimals. It does not modify the tax, which can be shared by documents.
 func (t *Tax) Prepare() error {
 	if len(t.Percent) == 0 && len(t.Amount) == 0 {
 		return ErrInvalidTax
@@ -32,20 +32,16 @@ func (t *Tax) Prepare() error {
 
 	// Percent
 	if len(t.Percent) > 0 {
-		percent, err := decimal.NewFromString(t.Percent)
-		if err != nil {
+		if _, err := parseDecimal("percent", t.Percent); err != nil {
 			return err
 		}
-		t._percent = percent
 	}
 
 	// Amount
 	if len(t.Amount) > 0 {
-		amount, err := decimal.NewFromString(t.Amount)
-		if err != nil {
+		if _, err := parseDecimal("amount", t.Amount); err != nil {
 			return err
 		}
-		t._amount = amount
 	}
 
 	return nil
@@ -69,3 +65,15 @@ func (t *Tax) getTax() (string, decimal.Decimal) {
 
 	return taxType, decVal
 }
+
+// formatTaxRate returns a tax percent rendered with Options.TaxRateFormat ex "20 %"
+func (doc *Document) formatTaxRate(percent string) string {
+	if !strings.Contains(doc.Options.TaxRateFormat, "[2]") {
+		return fmt.Sprintf(doc.Options.TaxRateFormat, percent)
+	}
+
+	decPercent, _ := decimal.NewFromString(percent)
+	rate, _ := decPercent.Shift(-2).Float64()
+
+	return fmt.Sprintf(doc.Options.TaxRateFormat, percent, rate)
+}
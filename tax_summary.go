@@ -0,0 +1,194 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// TaxSummaryLine define the taxes of items sharing the same tax name and rate
+type TaxSummaryLine struct {
+	Name    string          // Tax name, see Tax.Name
+	Type    string          // TaxTypePercent, or TaxTypeAmount for fixed amount taxes
+	Percent decimal.Decimal // Tax rate, zero for items without tax and amount taxes
+	Base    decimal.Decimal // Total without tax, with document discount
+	Tax     decimal.Decimal // Total tax, with document discount
+}
+
+// Total returns the line base with tax
+func (l *TaxSummaryLine) Total() decimal.Decimal {
+	return l.Base.Add(l.Tax)
+}
+
+// TaxSummary returns document taxes grouped by name and rate, in order of first appearance, shipping included.
+// Items without tax are grouped with 0% items, and amount taxes are grouped in a single line by name.
+// The base of items with several taxes (see Item.Taxes) is counted in the line of each of their taxes.
+func (doc *Document) TaxSummary() []*TaxSummaryLine {
+	lines := []*TaxSummaryLine{}
+	linesByKey := map[string]*TaxSummaryLine{}
+
+	add := func(tax *Tax, base decimal.Decimal, taxTotal decimal.Decimal) {
+		name, taxType, percent := "", TaxTypePercent, decimal.Zero
+		if tax != nil {
+			name = tax.Name
+
+			var taxAmount decimal.Decimal
+			taxType, taxAmount = tax.getTax()
+			if taxType == TaxTypePercent {
+				percent = taxAmount
+			}
+		}
+
+		key := fmt.Sprintf("%s:%s:%s", name, taxType, percent.String())
+		line, ok := linesByKey[key]
+		if !ok {
+			line = &TaxSummaryLine{Name: name, Type: taxType, Percent: percent}
+			linesByKey[key] = line
+			lines = append(lines, line)
+		}
+
+		line.Base = line.Base.Add(base)
+		line.Tax = line.Tax.Add(taxTotal)
+	}
+
+	for _, item := range doc.Items {
+		taxes := item.taxes()
+		if len(taxes) == 0 {
+			add(nil, doc.itemBase(item), decimal.Zero)
+			continue
+		}
+
+		amounts := doc.itemTaxes(item)
+		for index, tax := range taxes {
+			add(tax, doc.itemBase(item), amounts[index])
+		}
+	}
+
+	// Shipping
+	if doc.showShipping() {
+		add(doc.effectiveShippingTax(), doc.shippingAmount(), doc.shippingTax())
+	}
+
+	return lines
+}
+
+// TaxTotalsByName returns document taxes grouped by name, in order of first appearance, shipping included.
+// The line of a name used with several rates or with amount taxes has the TaxTypeAmount type and no rate,
+// taxes without name are grouped in the line with an empty name.
+func (doc *Document) TaxTotalsByName() []*TaxSummaryLine {
+	lines := []*TaxSummaryLine{}
+	linesByName := map[string]*TaxSummaryLine{}
+
+	for _, summaryLine := range doc.TaxSummary() {
+		line, ok := linesByName[summaryLine.Name]
+		if !ok {
+			line = &TaxSummaryLine{Name: summaryLine.Name, Type: summaryLine.Type, Percent: summaryLine.Percent}
+			linesByName[summaryLine.Name] = line
+			lines = append(lines, line)
+		} else if line.Type != summaryLine.Type || !line.Percent.Equal(summaryLine.Percent) {
+			line.Type, line.Percent = TaxTypeAmount, decimal.Zero
+		}
+
+		line.Base = line.Base.Add(summaryLine.Base)
+		line.Tax = line.Tax.Add(summaryLine.Tax)
+	}
+
+	return lines
+}
+
+// hasTaxNames returns true when a document tax is named, see Tax.Name
+func (doc *Document) hasTaxNames() bool {
+	for _, line := range doc.TaxSummary() {
+		if len(line.Name) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// totalsTaxLines returns the tax lines of the totals block: one by name for named taxes, one by rate with
+// totals details, or nil for a single total tax line
+func (doc *Document) totalsTaxLines() []*TaxSummaryLine {
+	if doc.hasTaxNames() {
+		return doc.TaxTotalsByName()
+	}
+
+	if doc.Options.ShowTotalsDetails {
+		if lines := doc.TaxSummary(); len(lines) > 0 {
+			return lines
+		}
+	}
+
+	return nil
+}
+
+// taxSummaryTotalTitle returns the totals tax line title of a tax summary line, its tax name or TextTotalTax,
+// with its rate for percent taxes
+func (doc *Document) taxSummaryTotalTitle(line *TaxSummaryLine) string {
+	title := doc.Options.TextTotalTax
+	if len(line.Name) > 0 {
+		title = line.Name
+	}
+
+	if line.Type == TaxTypeAmount {
+		return title
+	}
+
+	return fmt.Sprintf("%s %s", title, doc.formatTaxRate(line.Percent.String()))
+}
+
+// appendTaxSummary to document
+func (doc *Document) appendTaxSummary() {
+	lines := doc.TaxSummary()
+
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+
+	// Titles
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	doc.pdf.Rect(doc.rtlX(doc.rightX(120), 80), doc.pdf.GetY(), 80, 6, "F")
+	doc.appendTaxSummaryRow(
+		doc.Options.TextTaxSummaryRateTitle,
+		doc.Options.TextTaxSummaryBaseTitle,
+		doc.Options.TextTaxSummaryTaxTitle,
+		doc.Options.TextTaxSummaryTotalTitle,
+	)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+
+	// Lines
+	for _, line := range lines {
+		rate := "--"
+		if line.Type == TaxTypePercent {
+			rate = doc.formatTaxRate(line.Percent.String())
+		}
+		if len(line.Name) > 0 {
+			rate = fmt.Sprintf("%s %s", line.Name, rate)
+		}
+
+		doc.pdf.SetY(doc.pdf.GetY() + 6)
+		doc.appendTaxSummaryRow(
+			rate,
+			doc.formatTotal(line.Base),
+			doc.formatTotal(line.Tax),
+			doc.formatTotal(line.Total()),
+		)
+	}
+}
+
+// appendTaxSummaryRow append a 4 columns row of the tax summary at current y
+func (doc *Document) appendTaxSummaryRow(cols ...string) {
+	doc.pdf.SetX(doc.rtlX(doc.rightX(120), 80))
+	for i := range cols {
+		col := cols[i]
+		if doc.Options.RTL {
+			col = cols[len(cols)-1-i]
+		}
+		doc.pdf.CellFormat(20, 6, doc.encodeString(col), "0", 0, doc.rtlAlign("R"), false, 0, "")
+	}
+}
+
+// taxSummaryHeight returns the height of the tax summary block
+func (doc *Document) taxSummaryHeight() float64 {
+	return 16 + 6*float64(len(doc.TaxSummary()))
+}
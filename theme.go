@@ -0,0 +1,57 @@
+package generator
+
+import "reflect"
+
+// Themes, see Options.Theme
+const (
+	ThemeClassic    string = "classic"
+	ThemeModernBlue string = "modern-blue"
+	ThemeMinimal    string = "minimal"
+)
+
+// themes colors by Options field name, fields missing from a theme keep their default
+var themes = map[string]map[string][]int{
+	ThemeClassic: {
+		"BaseTextColor":      {35, 35, 35},
+		"GreyTextColor":      {82, 82, 82},
+		"GreyBgColor":        {232, 232, 232},
+		"DarkBgColor":        {212, 212, 212},
+		"ZebraRowColor":      {245, 245, 245},
+		"ItemSeparatorColor": {212, 212, 212},
+		"HeaderBandColor":    {41, 65, 122},
+		"NegativeTextColor":  {192, 0, 0},
+	},
+	ThemeModernBlue: {
+		"BaseTextColor":      {33, 43, 54},
+		"GreyTextColor":      {99, 115, 129},
+		"GreyBgColor":        {227, 236, 248},
+		"DarkBgColor":        {184, 206, 236},
+		"ZebraRowColor":      {243, 247, 253},
+		"ItemSeparatorColor": {184, 206, 236},
+		"HeaderBandColor":    {21, 101, 192},
+		"NegativeTextColor":  {198, 40, 40},
+	},
+	ThemeMinimal: {
+		"BaseTextColor":      {0, 0, 0},
+		"GreyTextColor":      {110, 110, 110},
+		"GreyBgColor":        {255, 255, 255},
+		"DarkBgColor":        {240, 240, 240},
+		"ZebraRowColor":      {250, 250, 250},
+		"ItemSeparatorColor": {220, 220, 220},
+		"HeaderBandColor":    {0, 0, 0},
+		"NegativeTextColor":  {0, 0, 0},
+	},
+}
+
+// applyTheme set empty Options color fields with the Theme colors.
+// It must run before defaults are set, so user defined colors are kept.
+func (o *Options) applyTheme() {
+	options := reflect.ValueOf(o).Elem()
+
+	for field, color := range themes[o.Theme] {
+		value := options.FieldByName(field)
+		if value.IsValid() && value.Kind() == reflect.Slice && value.Len() == 0 {
+			value.Set(reflect.ValueOf(append([]int{}, color...)))
+		}
+	}
+}
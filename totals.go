@@ -1,26 +1,34 @@
 package generator
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/shopspring/decimal"
 )
 
+// ErrInvalidAllocation when Totals.Allocate percentages are empty, negative or do not sum to 100
+var ErrInvalidAllocation = errors.New("invalid allocation")
+
 // TotalWithoutTaxAndWithoutDocumentDiscount return total without tax and without document discount
 func (doc *Document) TotalWithoutTaxAndWithoutDocumentDiscount() decimal.Decimal {
 	total := decimal.NewFromInt(0)
 
 	for _, item := range doc.Items {
-		decimalAmount, err := decimal.NewFromString(item.PayedPriceExclVAT)
-		if err != nil {
-			panic(err)
-		}
-		total = total.Add(decimalAmount)
+		total = total.Add(item._payedPriceExclVAT)
 	}
 
 	return total
 }
 
-// TotalWithoutTax return total without tax and with document discount
+// TotalWithoutTax return total without tax, with document discount and shipping.
+// The document discount applies after items discounts, on the sum of items totals without tax.
 func (doc *Document) TotalWithoutTax() decimal.Decimal {
+	return doc.totalWithDocumentDiscount().Add(doc.shippingAmount())
+}
+
+// totalWithDocumentDiscount return items total without tax and with document discount, without shipping
+func (doc *Document) totalWithDocumentDiscount() decimal.Decimal {
 	total := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
 
 	// Apply document discount
@@ -36,7 +44,7 @@ func (doc *Document) TotalWithoutTax() decimal.Decimal {
 		}
 	}
 
-	return total
+	return doc.Options.round(total)
 }
 
 // TotalWithTax return total with tax and with document discount
@@ -47,43 +55,225 @@ func (doc *Document) TotalWithTax() decimal.Decimal {
 	return totalWithoutTax.Add(tax)
 }
 
-// Tax return the total tax with document discount
+// Tax return the total tax with document discount and shipping tax.
+// Percent taxes are computed on items totals reduced by the document discount (see Options.TaxBeforeDiscount),
+// amount taxes are left unchanged.
 func (doc *Document) Tax() decimal.Decimal {
-	totalWithoutTaxAndWithoutDocDiscount := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
-	totalTax := decimal.NewFromFloat(0)
+	totalTax := doc.shippingTax()
 
+	for _, item := range doc.Items {
+		totalTax = totalTax.Add(doc.itemTax(item))
+	}
+
+	return totalTax
+}
+
+// itemBase return the item total without tax, reduced by the document discount
+func (doc *Document) itemBase(item *Item) decimal.Decimal {
+	itemTotal := item.TotalWithoutTaxAndWithDiscount()
 	if doc.Discount == nil {
-		for _, item := range doc.Items {
-			totalTax = totalTax.Add(item.TaxWithTotalDiscounted())
-		}
-	} else {
-		discountType, discountAmount := doc.Discount.getDiscount()
-		discountPercent := discountAmount
-		if discountType == DiscountTypeAmount {
-			// Get percent from total discounted
-			discountPercent = discountAmount.Mul(decimal.NewFromFloat(100)).Div(totalWithoutTaxAndWithoutDocDiscount)
+		return itemTotal
+	}
+
+	toSub := doc.discountPercent().Mul(itemTotal).Div(decimal.NewFromFloat(100))
+	return doc.Options.round(itemTotal.Sub(toSub))
+}
+
+// itemTax return the item tax, computed on the item total reduced by the document discount
+// unless Options.TaxBeforeDiscount. Item taxes are summed, see itemTaxes.
+func (doc *Document) itemTax(item *Item) decimal.Decimal {
+	total := decimal.Zero
+	for _, tax := range doc.itemTaxes(item) {
+		total = total.Add(tax)
+	}
+
+	return total
+}
+
+// itemTaxes return the amount of each item tax, in Item.taxes order, computed on the item total reduced by
+// the document discount unless Options.TaxBeforeDiscount
+func (doc *Document) itemTaxes(item *Item) []decimal.Decimal {
+	if doc.Discount == nil || doc.Options.TaxBeforeDiscount {
+		return item.taxesAmounts()
+	}
+
+	// Remove doc discount % from item total without tax and item discount
+	itemTotal := item.TotalWithoutTaxAndWithDiscount()
+	toSub := doc.discountPercent().Mul(itemTotal).Div(decimal.NewFromFloat(100))
+	itemTotalDiscounted := itemTotal.Sub(toSub)
+
+	amounts := item.taxesAmounts()
+	for index, tax := range item.taxes() {
+		// If tax type is amount, just add amount to tax (refunded with return lines)
+		if taxType, _ := tax.getTax(); taxType == TaxTypeAmount {
+			continue
 		}
 
-		for _, item := range doc.Items {
-			if item.Tax != nil {
-				taxType, taxAmount := item.Tax.getTax()
-				if taxType == TaxTypeAmount {
-					// If tax type is amount, just add amount to tax
-					totalTax = totalTax.Add(taxAmount)
-				} else {
-					// Else, remove doc discount % from item total without tax and item discount
-					itemTotal := item.TotalWithoutTaxAndWithDiscount()
-					toSub := discountPercent.Mul(itemTotal).Div(decimal.NewFromFloat(100))
-					itemTotalDiscounted := itemTotal.Sub(toSub)
-
-					// Then recompute tax on itemTotalDiscounted
-					itemTaxDiscounted := taxAmount.Mul(itemTotalDiscounted).Div(decimal.NewFromFloat(100))
-
-					totalTax = totalTax.Add(itemTaxDiscounted)
-				}
-			}
+		// Else recompute tax on itemTotalDiscounted
+		amounts[index] = doc.Options.round(taxOf(tax, itemTotalDiscounted))
+	}
+
+	return amounts
+}
+
+// Withholding return the withholding tax amount, computed on the total without tax and with document discount
+func (doc *Document) Withholding() decimal.Decimal {
+	if doc.WithholdingTax == nil {
+		return decimal.Zero
+	}
+
+	taxType, taxAmount := doc.WithholdingTax.getTax()
+	if taxType == TaxTypeAmount {
+		return taxAmount
+	}
+
+	return doc.Options.round(doc.TotalWithoutTax().Mul(taxAmount).Div(decimal.NewFromFloat(100)))
+}
+
+// NetPayable return the total with tax minus the withholding tax, with the cash rounding adjustment
+func (doc *Document) NetPayable() decimal.Decimal {
+	return doc.netPayableWithoutCashRounding().Add(doc.CashRoundingAdjustment())
+}
+
+// netPayableWithoutCashRounding return the total with tax minus the withholding tax
+func (doc *Document) netPayableWithoutCashRounding() decimal.Decimal {
+	return doc.TotalWithTax().Sub(doc.Withholding())
+}
+
+// CashRoundingAdjustment return the amount added to the net payable to round it to the nearest Options.CashRounding
+func (doc *Document) CashRoundingAdjustment() decimal.Decimal {
+	if doc.Options.CashRounding <= 0 {
+		return decimal.Zero
+	}
+
+	increment := decimal.NewFromFloat(doc.Options.CashRounding)
+	total := doc.netPayableWithoutCashRounding()
+
+	return total.Div(increment).Round(0).Mul(increment).Sub(total)
+}
+
+// itemsDiscount returns the sum of items discounts
+func (doc *Document) itemsDiscount() decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range doc.Items {
+		total = total.Add(item.TotalWithoutTaxAndWithoutDiscount().Sub(item.TotalWithoutTaxAndWithDiscount()))
+	}
+
+	return total
+}
+
+// documentDiscount returns the document discount amount, applied on the items totals
+func (doc *Document) documentDiscount() decimal.Decimal {
+	return doc.TotalWithoutTaxAndWithoutDocumentDiscount().Sub(doc.totalWithDocumentDiscount())
+}
+
+// totalDiscount returns the items and document discounts
+func (doc *Document) totalDiscount() decimal.Decimal {
+	return doc.itemsDiscount().Add(doc.documentDiscount())
+}
+
+// showTotalDiscount returns true when the total discount line must be rendered, see Options.ShowTotalsDetails
+func (doc *Document) showTotalDiscount() bool {
+	return doc.Options.ShowTotalsDetails && !doc.totalDiscount().IsZero()
+}
+
+// discountPercent returns the document discount as a percent of the total without tax and without document discount
+func (doc *Document) discountPercent() decimal.Decimal {
+	if doc.Discount == nil {
+		return decimal.Zero
+	}
+
+	discountType, discountAmount := doc.Discount.getDiscount()
+	if discountType == DiscountTypePercent {
+		return discountAmount
+	}
+
+	total := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
+	if total.IsZero() {
+		return decimal.Zero
+	}
+
+	// Get percent from total discounted
+	return discountAmount.Mul(decimal.NewFromFloat(100)).Div(total)
+}
+
+// Totals is the breakdown of document totals, as rendered in the pdf (see Document.Totals)
+type Totals struct {
+	Subtotal        decimal.Decimal `json:"subtotal"`          // Items totals without tax, with items discounts
+	ItemsDiscount   decimal.Decimal `json:"items_discount"`    // Sum of items discounts
+	Discount        decimal.Decimal `json:"discount"`          // Document discount, applied on Subtotal
+	Shipping        decimal.Decimal `json:"shipping"`          // Shipping without tax
+	TotalWithoutTax decimal.Decimal `json:"total_without_tax"` // Subtotal - Discount + Shipping
+	Tax             decimal.Decimal `json:"tax"`               // Items and shipping taxes
+	TotalWithTax    decimal.Decimal `json:"total_with_tax"`
+	Withholding     decimal.Decimal `json:"withholding"`
+	CashRounding    decimal.Decimal `json:"cash_rounding"`
+	NetPayable      decimal.Decimal `json:"net_payable"` // TotalWithTax - Withholding + CashRounding
+	AmountPaid      decimal.Decimal `json:"amount_paid"`
+	BalanceDue      decimal.Decimal `json:"balance_due"` // NetPayable - AmountPaid
+
+	Deposit          decimal.Decimal `json:"deposit"`           // Deposit due now, with its tax
+	DepositRemaining decimal.Decimal `json:"deposit_remaining"` // NetPayable - Deposit
+
+	_precision int32 // Options.CurrencyPrecision, allocations are rounded to it
+}
+
+// Totals validate the document and returns its totals breakdown
+func (doc *Document) Totals() (Totals, error) {
+	if err := doc.Validate(); err != nil {
+		return Totals{}, err
+	}
+
+	return Totals{
+		Subtotal:        doc.TotalWithoutTaxAndWithoutDocumentDiscount(),
+		ItemsDiscount:   doc.itemsDiscount(),
+		Discount:        doc.documentDiscount(),
+		Shipping:        doc.shippingAmount(),
+		TotalWithoutTax: doc.TotalWithoutTax(),
+		Tax:             doc.Tax(),
+		TotalWithTax:    doc.TotalWithTax(),
+		Withholding:     doc.Withholding(),
+		CashRounding:    doc.CashRoundingAdjustment(),
+		NetPayable:      doc.NetPayable(),
+		AmountPaid:      doc._amountPaid,
+		BalanceDue:      doc.BalanceDue(),
+
+		Deposit:          doc.DepositDue(),
+		DepositRemaining: doc.DepositRemaining(),
+
+		_precision: int32(doc.Options.CurrencyPrecision),
+	}, nil
+}
+
+// Allocate split TotalWithTax by percentages ex for cost centers, in percentages order. Allocations are
+// rounded to the currency precision and the last one absorbs the rounding, so that they sum exactly to the total.
+// Percentages must be positive and sum to 100, up to their own rounding (0.01 by percentage, 3 × 33.33 is valid).
+func (t Totals) Allocate(percentages []decimal.Decimal) ([]decimal.Decimal, error) {
+	if len(percentages) == 0 {
+		return nil, fmt.Errorf("%w: no percentages", ErrInvalidAllocation)
+	}
+
+	sum := decimal.Zero
+	for _, percentage := range percentages {
+		if percentage.IsNegative() {
+			return nil, fmt.Errorf("%w: negative percentage %s", ErrInvalidAllocation, percentage)
 		}
+		sum = sum.Add(percentage)
 	}
 
-	return totalTax
+	hundred := decimal.NewFromFloat(100)
+	tolerance := decimal.New(1, -2).Mul(decimal.NewFromInt(int64(len(percentages))))
+	if sum.Sub(hundred).Abs().GreaterThan(tolerance) {
+		return nil, fmt.Errorf("%w: percentages sum to %s", ErrInvalidAllocation, sum)
+	}
+
+	allocations := make([]decimal.Decimal, len(percentages))
+	allocated := decimal.Zero
+	for index, percentage := range percentages[:len(percentages)-1] {
+		allocations[index] = t.TotalWithTax.Mul(percentage).Div(hundred).Round(t._precision)
+		allocated = allocated.Add(allocations[index])
+	}
+	allocations[len(percentages)-1] = t.TotalWithTax.Sub(allocated)
+
+	return allocations, nil
 }
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ellipsis ends truncated texts
+const ellipsis string = "…"
+
+// truncateLines returns text cut at a word boundary and ended by an ellipsis so that it renders in at most
+// maxLines lines of width with the current font, or text unchanged when it fits or maxLines is 0
+func (doc *Document) truncateLines(text string, width float64, maxLines int) string {
+	fits := func(candidate string) bool {
+		return len(doc.pdf.SplitLines([]byte(doc.encodeString(candidate)), width)) <= maxLines
+	}
+
+	if maxLines <= 0 || fits(text) {
+		return text
+	}
+
+	// Longest words prefix fitting with the ellipsis, or runes prefix for a single long word
+	truncated := ellipsis
+	for index, r := range text {
+		if index == 0 || !unicode.IsSpace(r) {
+			continue
+		}
+
+		candidate := strings.TrimRightFunc(text[:index], unicode.IsSpace) + ellipsis
+		if !fits(candidate) {
+			break
+		}
+		truncated = candidate
+	}
+
+	if truncated == ellipsis {
+		for index := range text {
+			if index > 0 && !fits(text[:index]+ellipsis) {
+				break
+			}
+			truncated = text[:index] + ellipsis
+		}
+	}
+
+	return truncated
+}
@@ -0,0 +1,355 @@
+package generator
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// UBL errors
+var (
+	// ErrUBLInvalidType when the document is not an invoice
+	ErrUBLInvalidType = errors.New("ubl: document must be an invoice")
+
+	// ErrUBLAmountTax when an item tax is a fixed amount, which cannot be expressed as a rate
+	ErrUBLAmountTax = errors.New("ubl: amount taxes are not supported")
+
+	// ErrUBLMultipleTaxes when an item has several taxes, which cannot be expressed as a single VAT category
+	ErrUBLMultipleTaxes = errors.New("ubl: items with several taxes are not supported")
+
+	// ErrUBLInvalidDate when the document date or due date cannot be parsed
+	ErrUBLInvalidDate = errors.New("ubl: invalid document date")
+
+	// ErrUBLTotalsMismatch when the xml totals differ from the rendered ones
+	ErrUBLTotalsMismatch = errors.New("ubl: xml totals do not match document totals")
+)
+
+// MarshalUBL returns the UBL 2.1 Invoice xml of the document, following the PEPPOL BIS Billing 3.0 rules.
+// Document must have been validated (see Document.Validate).
+//
+// Note that parties electronic addresses (EndpointID) are not part of the document data, they must be
+// added before sending the xml to a PEPPOL access point.
+func (doc *Document) MarshalUBL() ([]byte, error) {
+	if doc.Type != Invoice {
+		return nil, ErrUBLInvalidType
+	}
+
+	issueDate, err := doc.issueDate()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUBLInvalidDate, err)
+	}
+
+	if doc.hasAmountTax() {
+		return nil, ErrUBLAmountTax
+	}
+
+	if doc.hasMultipleTaxes() {
+		return nil, ErrUBLMultipleTaxes
+	}
+
+	currency := doc.Options.CurrencyCode
+	amount := func(value decimal.Decimal) *ublAmount {
+		return &ublAmount{Currency: currency, Value: value.StringFixed(int32(doc.Options.CurrencyPrecision))}
+	}
+
+	inv := &ublInvoice{
+		Xmlns:           "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2",
+		XmlnsCac:        "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2",
+		XmlnsCbc:        "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2",
+		CustomizationID: "urn:cen.eu:en16931:2017#compliant#urn:fdc:peppol.eu:2017:poacc:billing:3.0",
+		ProfileID:       "urn:fdc:peppol.eu:2017:poacc:billing:01:1.0",
+		ID:              doc.Ref,
+		IssueDate:       issueDate.Format("2006-01-02"),
+		TypeCode:        "380",
+		Currency:        currency,
+		BuyerReference:  doc.ClientRef,
+		Supplier:        ublParty{Party: newUBLParty(doc.Company)},
+		Customer:        ublParty{Party: newUBLParty(doc.Customer)},
+	}
+
+	if len(doc.DueDate) > 0 {
+		dueDate, err := doc.parseDate(doc.DueDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUBLInvalidDate, err)
+		}
+		inv.DueDate = dueDate.Format("2006-01-02")
+	}
+
+	if len(doc.PaymentTerm) > 0 {
+		inv.PaymentTerms = &ublPaymentTerms{Note: doc.PaymentTerm}
+	}
+
+	breakdown := doc.newEInvoice()
+
+	// Document allowances and charges, taxes by rate
+	inv.TaxTotal.Amount = amount(breakdown.taxTotal)
+	for _, t := range breakdown.taxes {
+		if !t.allowance.IsZero() {
+			inv.AllowanceCharges = append(inv.AllowanceCharges, &ublAllowanceCharge{
+				Indicator: false,
+				Reason:    doc.Options.TextTotalDiscounted,
+				Amount:    amount(t.allowance),
+				Tax:       doc.newUBLTaxCategory(t.rate, false),
+			})
+		}
+
+		if !t.charge.IsZero() {
+			inv.AllowanceCharges = append(inv.AllowanceCharges, &ublAllowanceCharge{
+				Indicator: true,
+				Reason:    doc.Options.TextTotalShipping,
+				Amount:    amount(t.charge),
+				Tax:       doc.newUBLTaxCategory(t.rate, false),
+			})
+		}
+
+		inv.TaxTotal.Subtotals = append(inv.TaxTotal.Subtotals, &ublTaxSubtotal{
+			Taxable:  amount(t.basis),
+			Amount:   amount(t.tax),
+			Category: doc.newUBLTaxCategory(t.rate, true),
+		})
+	}
+
+	// Totals
+	inv.MonetaryTotal = ublMonetaryTotal{
+		LineExtension: amount(breakdown.lineTotal),
+		TaxExclusive:  amount(breakdown.taxBasis),
+		TaxInclusive:  amount(breakdown.grandTotal),
+		Payable:       amount(breakdown.duePayable()),
+	}
+	if !breakdown.allowanceTotal.IsZero() {
+		inv.MonetaryTotal.AllowanceTotal = amount(breakdown.allowanceTotal)
+	}
+	if !breakdown.chargeTotal.IsZero() {
+		inv.MonetaryTotal.ChargeTotal = amount(breakdown.chargeTotal)
+	}
+	if !breakdown.rounding.IsZero() {
+		inv.MonetaryTotal.Rounding = amount(breakdown.rounding)
+	}
+
+	// Lines
+	for n, l := range breakdown.lines {
+		line := &ublLine{
+			ID:            strconv.Itoa(n + 1),
+			Quantity:      ublQuantity{UnitCode: "C62", Value: l.item._quantity.String()},
+			LineExtension: amount(l.net),
+			Item: ublItem{
+				Description: l.item.Description,
+				Name:        l.item.Name,
+				Tax:         doc.newUBLTaxCategory(l.rate, false),
+			},
+			Price: ublPrice{Amount: amount(l.item.unitCostWithoutTax())},
+		}
+
+		if len(l.item.Ref) > 0 {
+			line.Item.SellersID = &ublID{ID: l.item.Ref}
+		}
+
+		if !l.allowance.IsZero() {
+			line.AllowanceCharges = []*ublAllowanceCharge{{
+				Indicator: false,
+				Reason:    doc.Options.TextItemsDiscountTitle,
+				Amount:    amount(l.allowance),
+			}}
+		}
+
+		inv.Lines = append(inv.Lines, line)
+	}
+
+	// Check xml totals against document ones
+	if !breakdown.matches(doc) {
+		return nil, ErrUBLTotalsMismatch
+	}
+
+	out, err := xml.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), out...), nil
+}
+
+// newUBLParty returns the ubl party of contact
+func newUBLParty(c *Contact) ublPartyDetails {
+	party := ublPartyDetails{
+		Name:        &ublPartyName{Name: c.Name},
+		LegalEntity: ublLegalEntity{RegistrationName: c.Name},
+	}
+
+	if c.Address != nil {
+		party.Address = ublAddress{
+			Street:           c.Address.Address,
+			AdditionalStreet: c.Address.Address2,
+			City:             c.Address.City,
+			PostalZone:       c.Address.PostalCode,
+		}
+
+		if len(c.Address.Country) == 2 {
+			party.Address.Country = &ublCountry{Code: c.Address.Country}
+		}
+	}
+
+	if len(c.VATNumber) > 0 {
+		party.TaxScheme = &ublPartyTaxScheme{CompanyID: c.VATNumber, TaxScheme: ublID{ID: "VAT"}}
+	}
+
+	return party
+}
+
+// newUBLTaxCategory returns the ubl VAT category of rate, reverse charge exemption reason is only
+// allowed in tax subtotals
+func (doc *Document) newUBLTaxCategory(rate decimal.Decimal, subtotal bool) *ublTaxCategory {
+	category := &ublTaxCategory{
+		ID:        "S",
+		Percent:   rate.StringFixed(2),
+		TaxScheme: ublID{ID: "VAT"},
+	}
+
+	if rate.IsZero() {
+		category.ID = "Z"
+	}
+
+	if doc.ReverseCharge {
+		category.ID = "AE"
+		if subtotal {
+			category.ExemptionReason = doc.Options.TextReverseCharge
+		}
+	}
+
+	return category
+}
+
+// UBL 2.1 Invoice xml structure, limited to the PEPPOL BIS Billing 3.0 fields.
+// Elements are declared in schema sequence order.
+type ublInvoice struct {
+	XMLName          xml.Name              `xml:"Invoice"`
+	Xmlns            string                `xml:"xmlns,attr"`
+	XmlnsCac         string                `xml:"xmlns:cac,attr"`
+	XmlnsCbc         string                `xml:"xmlns:cbc,attr"`
+	CustomizationID  string                `xml:"cbc:CustomizationID"`
+	ProfileID        string                `xml:"cbc:ProfileID"`
+	ID               string                `xml:"cbc:ID"`
+	IssueDate        string                `xml:"cbc:IssueDate"`
+	DueDate          string                `xml:"cbc:DueDate,omitempty"`
+	TypeCode         string                `xml:"cbc:InvoiceTypeCode"`
+	Currency         string                `xml:"cbc:DocumentCurrencyCode"`
+	BuyerReference   string                `xml:"cbc:BuyerReference,omitempty"`
+	Supplier         ublParty              `xml:"cac:AccountingSupplierParty"`
+	Customer         ublParty              `xml:"cac:AccountingCustomerParty"`
+	PaymentTerms     *ublPaymentTerms      `xml:"cac:PaymentTerms,omitempty"`
+	AllowanceCharges []*ublAllowanceCharge `xml:"cac:AllowanceCharge"`
+	TaxTotal         ublTaxTotal           `xml:"cac:TaxTotal"`
+	MonetaryTotal    ublMonetaryTotal      `xml:"cac:LegalMonetaryTotal"`
+	Lines            []*ublLine            `xml:"cac:InvoiceLine"`
+}
+
+type ublID struct {
+	ID string `xml:"cbc:ID"`
+}
+
+type ublAmount struct {
+	Currency string `xml:"currencyID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublParty struct {
+	Party ublPartyDetails `xml:"cac:Party"`
+}
+
+type ublPartyName struct {
+	Name string `xml:"cbc:Name"`
+}
+
+type ublCountry struct {
+	Code string `xml:"cbc:IdentificationCode"`
+}
+
+type ublAddress struct {
+	Street           string      `xml:"cbc:StreetName,omitempty"`
+	AdditionalStreet string      `xml:"cbc:AdditionalStreetName,omitempty"`
+	City             string      `xml:"cbc:CityName,omitempty"`
+	PostalZone       string      `xml:"cbc:PostalZone,omitempty"`
+	Country          *ublCountry `xml:"cac:Country,omitempty"`
+}
+
+type ublPartyTaxScheme struct {
+	CompanyID string `xml:"cbc:CompanyID"`
+	TaxScheme ublID  `xml:"cac:TaxScheme"`
+}
+
+type ublLegalEntity struct {
+	RegistrationName string `xml:"cbc:RegistrationName"`
+}
+
+type ublPartyDetails struct {
+	Name        *ublPartyName      `xml:"cac:PartyName,omitempty"`
+	Address     ublAddress         `xml:"cac:PostalAddress"`
+	TaxScheme   *ublPartyTaxScheme `xml:"cac:PartyTaxScheme,omitempty"`
+	LegalEntity ublLegalEntity     `xml:"cac:PartyLegalEntity"`
+}
+
+type ublPaymentTerms struct {
+	Note string `xml:"cbc:Note"`
+}
+
+type ublTaxCategory struct {
+	ID              string `xml:"cbc:ID"`
+	Percent         string `xml:"cbc:Percent"`
+	ExemptionReason string `xml:"cbc:TaxExemptionReason,omitempty"`
+	TaxScheme       ublID  `xml:"cac:TaxScheme"`
+}
+
+type ublAllowanceCharge struct {
+	Indicator bool            `xml:"cbc:ChargeIndicator"`
+	Reason    string          `xml:"cbc:AllowanceChargeReason"`
+	Amount    *ublAmount      `xml:"cbc:Amount"`
+	Tax       *ublTaxCategory `xml:"cac:TaxCategory,omitempty"`
+}
+
+type ublTaxSubtotal struct {
+	Taxable  *ublAmount      `xml:"cbc:TaxableAmount"`
+	Amount   *ublAmount      `xml:"cbc:TaxAmount"`
+	Category *ublTaxCategory `xml:"cac:TaxCategory"`
+}
+
+type ublTaxTotal struct {
+	Amount    *ublAmount        `xml:"cbc:TaxAmount"`
+	Subtotals []*ublTaxSubtotal `xml:"cac:TaxSubtotal"`
+}
+
+type ublMonetaryTotal struct {
+	LineExtension  *ublAmount `xml:"cbc:LineExtensionAmount"`
+	TaxExclusive   *ublAmount `xml:"cbc:TaxExclusiveAmount"`
+	TaxInclusive   *ublAmount `xml:"cbc:TaxInclusiveAmount"`
+	AllowanceTotal *ublAmount `xml:"cbc:AllowanceTotalAmount,omitempty"`
+	ChargeTotal    *ublAmount `xml:"cbc:ChargeTotalAmount,omitempty"`
+	Rounding       *ublAmount `xml:"cbc:PayableRoundingAmount,omitempty"`
+	Payable        *ublAmount `xml:"cbc:PayableAmount"`
+}
+
+type ublQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublItem struct {
+	Description string          `xml:"cbc:Description,omitempty"`
+	Name        string          `xml:"cbc:Name"`
+	SellersID   *ublID          `xml:"cac:SellersItemIdentification,omitempty"`
+	Tax         *ublTaxCategory `xml:"cac:ClassifiedTaxCategory"`
+}
+
+type ublPrice struct {
+	Amount *ublAmount `xml:"cbc:PriceAmount"`
+}
+
+type ublLine struct {
+	ID               string                `xml:"cbc:ID"`
+	Quantity         ublQuantity           `xml:"cbc:InvoicedQuantity"`
+	LineExtension    *ublAmount            `xml:"cbc:LineExtensionAmount"`
+	AllowanceCharges []*ublAllowanceCharge `xml:"cac:AllowanceCharge"`
+	Item             ublItem               `xml:"cac:Item"`
+	Price            ublPrice              `xml:"cac:Price"`
+}
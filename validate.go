@@ -1,29 +1,172 @@
 package generator
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 )
 
-// Validate document fields
+// ErrNilItem when a document item is nil, see Document.PrepareAll
+var ErrNilItem = errors.New("nil")
+
+// Validate document fields, then prepare items, taxes and discounts amounts (see PrepareAll).
+// Struct tags of the whole document graph are checked first, all failing fields are returned at once
+// as validator.ValidationErrors, named by their json keys ex Document.items[0].name
 func (d *Document) Validate() error {
 	validate := validator.New()
+	validate.RegisterTagNameFunc(jsonFieldName)
 	if err := validate.Struct(d); err != nil {
 		return err
 	}
 
+	// Expand document number to ref
+	if err := d.applyNumber(); err != nil {
+		return err
+	}
+
+	// Check page margins
+	if err := d.validateMargins(); err != nil {
+		return err
+	}
+
+	// Check columns layout
+	pageWidth, _ := d.pdf.GetPageSize()
+	cols := d.columnOffsets()
+	if err := cols.validate(pageWidth); err != nil {
+		return err
+	}
+	if err := d.validateItemColumns(); err != nil {
+		return err
+	}
+
+	// Check PDF/A constraints
+	if d.Options.PDFA {
+		if err := d.validatePDFA(); err != nil {
+			return err
+		}
+	}
+
+	// Check due date
+	if len(d.DueDate) > 0 {
+		if _, err := d.parseDate(d.DueDate); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidDueDate, err)
+		}
+	}
+
+	// Check validity date
+	if len(d.ValidityDate) > 0 {
+		if _, err := d.parseDate(d.ValidityDate); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidValidityDate, err)
+		}
+	}
+
+	// Check generation timestamp time zone
+	if d.Options.ShowGeneratedAt {
+		if _, err := d.Options.generatedAtLocation(); err != nil {
+			return err
+		}
+	}
+
+	// Check payment info
+	if d.PaymentInfo != nil {
+		if err := d.PaymentInfo.validate(); err != nil {
+			return err
+		}
+	}
+
+	return d.PrepareAll()
+}
+
+// PrepareAll prepare items, with their taxes and discounts, then the document taxes, discounts and amounts.
+// It returns the first invalid decimal with its context ex item 2 "Name": tax: field percent: invalid decimal "x".
+// Validate calls it after checking fields, it can be called alone before Build as a pre-flight check.
+func (d *Document) PrepareAll() error {
+	// Prepare default tax
+	if d.DefaultTax != nil {
+		if err := d.DefaultTax.Prepare(); err != nil {
+			return fmt.Errorf("default tax: %w", err)
+		}
+	}
+
 	// Prepare items
-	for _, item := range d.Items {
+	for index, item := range d.Items {
+		if item == nil {
+			return fmt.Errorf("item %d: %w", index, ErrNilItem)
+		}
+
+		// Share document options (rounding), default tax and reverse charge with item, see Item.effectiveTax
+		item._options = d.Options
+		item._defaultTax = d.DefaultTax
+		item._reverseCharge = d.ReverseCharge
+
 		if err := item.Prepare(); err != nil {
-			return err
+			return fmt.Errorf("item %d %q: %w", index, item.Name, err)
+		}
+
+		if err := d.validateItemPeriod(item); err != nil {
+			return fmt.Errorf("item %d %q: %w", index, item.Name, err)
 		}
 	}
 
 	// Prepare document discount
 	if d.Discount != nil {
 		if err := d.Discount.Prepare(); err != nil {
-			return err
+			return fmt.Errorf("discount: %w", err)
+		}
+	}
+
+	// Prepare shipping
+	if d.Shipping != nil {
+		if err := d.Shipping.Prepare(); err != nil {
+			return fmt.Errorf("shipping: %w", err)
+		}
+	}
+
+	// Prepare withholding tax
+	if d.WithholdingTax != nil {
+		if err := d.WithholdingTax.Prepare(); err != nil {
+			return fmt.Errorf("withholding tax: %w", err)
 		}
 	}
 
+	// Prepare deposit
+	if d.Deposit != nil {
+		if err := d.Deposit.Prepare(); err != nil {
+			return fmt.Errorf("deposit: %w", err)
+		}
+	}
+
+	// Prepare progress billing
+	if d.ProgressBilling != nil {
+		if err := d.ProgressBilling.Prepare(); err != nil {
+			return fmt.Errorf("progress billing: %w", err)
+		}
+	}
+
+	// Prepare early payment discount
+	if d.EarlyPaymentDiscount != nil {
+		if err := d.EarlyPaymentDiscount.Prepare(); err != nil {
+			return fmt.Errorf("early payment discount: %w", err)
+		}
+	}
+
+	// Prepare amount paid
+	if err := d.prepareAmountPaid(); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// jsonFieldName returns the json key of field, used to name validation errors
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if len(name) == 0 {
+		return field.Name
+	}
+
+	return name
+}
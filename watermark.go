@@ -0,0 +1,55 @@
+package generator
+
+import "math"
+
+// withWatermark returns a header func drawing Options.Watermark before calling fn.
+// Header funcs run when a page is added, so the watermark is drawn behind the page content.
+func (doc *Document) withWatermark(fn func()) func() {
+	return func() {
+		if len(doc.Options.Watermark) > 0 {
+			doc.appendWatermark()
+		}
+
+		if fn != nil {
+			fn()
+		}
+	}
+}
+
+// appendWatermark draw Options.Watermark across the current page, rotated around the page center
+func (doc *Document) appendWatermark() {
+	pageWidth, pageHeight := doc.pdf.GetPageSize()
+	centerX, centerY := pageWidth/2, pageHeight/2
+
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", doc.Options.WatermarkFontSize)
+
+	// PDF/A forbids transparency, the color is blended with the white page instead
+	opacity := doc.Options.WatermarkOpacity
+	blend := func(c int) int {
+		if !doc.Options.PDFA {
+			return c
+		}
+		return int(math.Round(255 - float64(255-c)*opacity))
+	}
+	doc.pdf.SetTextColor(
+		blend(doc.Options.WatermarkColor[0]),
+		blend(doc.Options.WatermarkColor[1]),
+		blend(doc.Options.WatermarkColor[2]),
+	)
+	if !doc.Options.PDFA {
+		doc.pdf.SetAlpha(opacity, "Normal")
+	}
+
+	text := doc.encodeString(doc.Options.Watermark)
+	_, fontHeight := doc.pdf.GetFontSize()
+
+	doc.pdf.TransformBegin()
+	doc.pdf.TransformRotate(doc.Options.WatermarkAngle, centerX, centerY)
+	doc.pdf.Text(centerX-doc.pdf.GetStringWidth(text)/2, centerY+fontHeight/3, text)
+	doc.pdf.TransformEnd()
+
+	// Fonts and colors are restored by fpdf after header funcs, alpha is not
+	if !doc.Options.PDFA {
+		doc.pdf.SetAlpha(1, "Normal")
+	}
+}
@@ -0,0 +1,269 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// currencyWords define the names of a currency and of its hundredth, singular and plural
+type currencyWords struct {
+	unit, units   string
+	cent, cents   string
+	feminine      bool // French feminine units ex une livre
+	elidedOfUnits bool // French units starting with a vowel ex un million d'euros
+}
+
+// currenciesWords are the currencies names by language then ISO 4217 code
+var currenciesWords = map[string]map[string]currencyWords{
+	LanguageEnglish: {
+		"EUR": {unit: "euro", units: "euros", cent: "cent", cents: "cents"},
+		"USD": {unit: "dollar", units: "dollars", cent: "cent", cents: "cents"},
+		"CAD": {unit: "Canadian dollar", units: "Canadian dollars", cent: "cent", cents: "cents"},
+		"GBP": {unit: "pound", units: "pounds", cent: "penny", cents: "pence"},
+		"CHF": {unit: "Swiss franc", units: "Swiss francs", cent: "centime", cents: "centimes"},
+	},
+	LanguageFrench: {
+		"EUR": {unit: "euro", units: "euros", cent: "centime", cents: "centimes", elidedOfUnits: true},
+		"USD": {unit: "dollar", units: "dollars", cent: "cent", cents: "cents"},
+		"CAD": {unit: "dollar canadien", units: "dollars canadiens", cent: "cent", cents: "cents"},
+		"GBP": {unit: "livre sterling", units: "livres sterling", cent: "penny", cents: "pence", feminine: true},
+		"CHF": {unit: "franc suisse", units: "francs suisses", cent: "centime", cents: "centimes"},
+	},
+}
+
+// TotalInWords returns the document total with tax spelled out with its currency, see AmountInWords
+func (doc *Document) TotalInWords() string {
+	return AmountInWords(doc.TotalWithTax(), doc.Options.Language, doc.Options.CurrencyCode, doc.Options.CurrencyPrecision)
+}
+
+// maxSpelledAmount is the largest units or cents count AmountInWords spells out
+var maxSpelledAmount = decimal.NewFromInt(math.MaxInt64)
+
+// AmountInWords returns amount spelled out in language (English or French, other languages use English)
+// with the currency of ISO 4217 code, rounded to precision decimals and with its cents when precision > 0,
+// ex one thousand two hundred thirty-four euros and fifty-six cents.
+// Unknown currencies are named by their code. It returns an empty string for amounts beyond int64,
+// the total in words is then skipped.
+func AmountInWords(amount decimal.Decimal, language string, code string, precision int) string {
+	if language != LanguageFrench {
+		language = LanguageEnglish
+	}
+
+	currency, ok := currenciesWords[language][code]
+	if !ok {
+		currency = currencyWords{unit: code, units: code, cent: "cent", cents: "cents"}
+		if language == LanguageFrench {
+			currency.cent, currency.cents = "centime", "centimes"
+		}
+	}
+
+	spell, and, minus := englishNumberWords, "and", "minus"
+	if language == LanguageFrench {
+		spell, and, minus = frenchNumberWords, "et", "moins"
+	}
+
+	// Spell the rounded amount, as rendered, and its fraction at the currency precision ex fils for 3
+	if precision < 0 {
+		precision = 0
+	}
+	amount = amount.Round(int32(precision))
+
+	words := []string{}
+	if amount.IsNegative() {
+		words = append(words, minus)
+		amount = amount.Neg()
+	}
+
+	units := amount.Truncate(0)
+	fraction := amount.Sub(units).Shift(int32(precision))
+	if units.GreaterThan(maxSpelledAmount) || fraction.GreaterThan(maxSpelledAmount) {
+		return ""
+	}
+	cents := fraction.IntPart()
+
+	// Units, with the French feminine and "de" after round millions
+	unitsWords := spell(units.IntPart())
+	if currency.feminine && strings.HasSuffix(unitsWords, "un") {
+		unitsWords += "e"
+	}
+	words = append(words, unitsWords)
+
+	unitsName := currency.units
+	if units.IntPart() == 1 || (language == LanguageFrench && units.IsZero()) {
+		unitsName = currency.unit
+	}
+	if language == LanguageFrench && (strings.HasSuffix(unitsWords, "million") || strings.HasSuffix(unitsWords, "millions") ||
+		strings.HasSuffix(unitsWords, "milliard") || strings.HasSuffix(unitsWords, "milliards")) {
+		if currency.elidedOfUnits {
+			unitsName = "d'" + unitsName
+		} else {
+			unitsName = "de " + unitsName
+		}
+	}
+	words = append(words, unitsName)
+
+	// Cents
+	if cents > 0 {
+		centsName := currency.cents
+		if cents == 1 {
+			centsName = currency.cent
+		}
+		words = append(words, and, spell(cents), centsName)
+	}
+
+	return strings.Join(words, " ")
+}
+
+var (
+	englishUnits = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+	}
+	englishTens = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+)
+
+// englishNumberWords returns n spelled out in English ex two hundred thirty-four
+func englishNumberWords(n int64) string {
+	if n < 20 {
+		return englishUnits[n]
+	}
+
+	if n < 100 {
+		if n%10 == 0 {
+			return englishTens[n/10]
+		}
+		return fmt.Sprintf("%s-%s", englishTens[n/10], englishUnits[n%10])
+	}
+
+	if n < 1000 {
+		return joinNumberWords(englishUnits[n/100]+" hundred", n%100, englishNumberWords)
+	}
+
+	for _, scale := range []struct {
+		value int64
+		name  string
+	}{{1e9, "billion"}, {1e6, "million"}, {1e3, "thousand"}} {
+		if n >= scale.value {
+			return joinNumberWords(englishNumberWords(n/scale.value)+" "+scale.name, n%scale.value, englishNumberWords)
+		}
+	}
+
+	return ""
+}
+
+var (
+	frenchUnits = []string{
+		"zéro", "un", "deux", "trois", "quatre", "cinq", "six", "sept", "huit", "neuf", "dix",
+		"onze", "douze", "treize", "quatorze", "quinze", "seize", "dix-sept", "dix-huit", "dix-neuf",
+	}
+	frenchTens = []string{"", "", "vingt", "trente", "quarante", "cinquante", "soixante", "soixante", "quatre-vingt", "quatre-vingt"}
+)
+
+// frenchNumberWords returns n spelled out in French (traditional spelling) ex deux cent trente-quatre
+func frenchNumberWords(n int64) string {
+	if n < 20 {
+		return frenchUnits[n]
+	}
+
+	if n < 100 {
+		tens, units := n/10, n%10
+		if tens == 7 || tens == 9 {
+			// soixante-dix, quatre-vingt-dix
+			units += 10
+		}
+
+		switch {
+		case units == 0 && tens == 8:
+			return "quatre-vingts"
+		case units == 0:
+			return frenchTens[tens]
+		case (units == 1 || units == 11) && tens != 8 && tens != 9:
+			return fmt.Sprintf("%s et %s", frenchTens[tens], frenchUnits[units])
+		}
+		return fmt.Sprintf("%s-%s", frenchTens[tens], frenchUnits[units])
+	}
+
+	if n < 1000 {
+		hundreds := "cent"
+		if n/100 > 1 {
+			hundreds = frenchUnits[n/100] + " cent"
+			if n%100 == 0 {
+				hundreds += "s"
+			}
+		}
+		return joinNumberWords(hundreds, n%100, frenchNumberWords)
+	}
+
+	if n < 1e6 {
+		// mille is invariable, and makes cents and quatre-vingts singular
+		thousands := "mille"
+		if n/1000 > 1 {
+			thousands = frenchNumberWords(n / 1000)
+			if strings.HasSuffix(thousands, "cents") || strings.HasSuffix(thousands, "vingts") {
+				thousands = strings.TrimSuffix(thousands, "s")
+			}
+			thousands += " mille"
+		}
+		return joinNumberWords(thousands, n%1000, frenchNumberWords)
+	}
+
+	for _, scale := range []struct {
+		value int64
+		name  string
+	}{{1e9, "milliard"}, {1e6, "million"}} {
+		if n >= scale.value {
+			name := scale.name
+			if n/scale.value > 1 {
+				name += "s"
+			}
+			return joinNumberWords(frenchNumberWords(n/scale.value)+" "+name, n%scale.value, frenchNumberWords)
+		}
+	}
+
+	return ""
+}
+
+// joinNumberWords returns prefix followed by the spelled out rest, prefix alone when the rest is 0
+func joinNumberWords(prefix string, rest int64, spell func(int64) string) string {
+	if rest == 0 {
+		return prefix
+	}
+
+	return prefix + " " + spell(rest)
+}
+
+// showTotalInWords returns whether the total in words is rendered, see Options.ShowTotalInWords.
+// AmountInWords only spells English and French, other languages would mix English words in a legal mention.
+func (doc *Document) showTotalInWords() bool {
+	if !doc.Options.ShowTotalInWords {
+		return false
+	}
+
+	_, ok := currenciesWords[doc.Options.Language]
+	return (ok || len(doc.Options.Language) == 0) && len(doc.TotalInWords()) > 0
+}
+
+// appendTotalInWords append the document total spelled out below the totals block, see Options.ShowTotalInWords
+func (doc *Document) appendTotalInWords() {
+	doc.pdf.SetY(doc.pdf.GetY() + 12)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+
+	doc.pdf.SetX(doc.rtlX(doc.rightX(120), 80))
+	doc.pdf.MultiCell(80, 3, doc.encodeString(fmt.Sprintf(doc.Options.TextTotalInWords, doc.TotalInWords())), "0", doc.rtlAlign("L"), false)
+
+	// Reset font
+	doc.pdf.SetFont(doc.Options.Font, "", LargeTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+}